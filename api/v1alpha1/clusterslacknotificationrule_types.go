@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSlackNotificationRuleSpec defines the desired state of ClusterSlackNotificationRule
+// +kubebuilder:validation:XValidation:rule="!has(self.slackConfigRef.kind) || self.slackConfigRef.kind == 'ClusterSlackConfig'",message="slackConfigRef must reference a ClusterSlackConfig"
+type ClusterSlackNotificationRuleSpec struct {
+	// NamespaceSelector selects the namespaces whose CronJobs or CronWorkflows
+	// the rule applies to. An empty selector selects all namespaces.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// The rule itself. SlackConfigRef references a ClusterSlackConfig.
+	SlackNotificationRuleSpec `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetResource`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedTargetCount`
+// +kubebuilder:printcolumn:name="Sent",type=integer,JSONPath=`.status.sentCount`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterSlackNotificationRule is the Schema for the clusterslacknotificationrules API.
+// It is a SlackNotificationRule applied to the CronJobs or CronWorkflows of
+// every namespace matched by its namespace selector.
+type ClusterSlackNotificationRule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClusterSlackNotificationRule
+	// +required
+	Spec ClusterSlackNotificationRuleSpec `json:"spec"`
+
+	// status defines the observed state of ClusterSlackNotificationRule.
+	// MatchedTargets are listed as namespace/name.
+	// +optional
+	Status SlackNotificationRuleStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSlackNotificationRuleList contains a list of ClusterSlackNotificationRule
+type ClusterSlackNotificationRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterSlackNotificationRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSlackNotificationRule{}, &ClusterSlackNotificationRuleList{})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SinkConfigSpec defines the desired state of SinkConfig
+type SinkConfigSpec struct {
+	// Type is the service notifications are delivered to: "Teams" posts
+	// Adaptive Cards to a Microsoft Teams workflow or incoming webhook,
+	// "Discord" posts embeds to a Discord webhook, "Mattermost" posts
+	// attachments to a Mattermost incoming webhook and "Webhook" posts a
+	// JSON body to any URL. "PagerDuty" and "Opsgenie" open incidents that
+	// are resolved when a later run of the same CronJob or CronWorkflow
+	// succeeds. "Email" sends an email with a plain text and an HTML body
+	// over SMTP. "Alertmanager" fires alerts through the Alertmanager v2 API,
+	// which are resolved like incidents.
+	// +kubebuilder:validation:Enum=Teams;Discord;Mattermost;Webhook;PagerDuty;Opsgenie;Email;Alertmanager
+	Type string `json:"type"`
+
+	// URLSecretRef references a Secret containing the URL notifications are
+	// posted to, or the base URL of Alertmanager, e.g.
+	// http://alertmanager.monitoring:9093. Required unless Type is PagerDuty,
+	// Opsgenie or Email.
+	// +optional
+	URLSecretRef *corev1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+
+	// Webhook configures the requests of a generic webhook. Only allowed with Type Webhook.
+	// +optional
+	Webhook *WebhookSink `json:"webhook,omitempty"`
+
+	// Incident configures a PagerDuty or Opsgenie sink. Required with Type
+	// PagerDuty or Opsgenie.
+	// +optional
+	Incident *IncidentSink `json:"incident,omitempty"`
+
+	// Email configures the SMTP server and recipients of an email sink.
+	// Required with Type Email.
+	// +optional
+	Email *EmailSink `json:"email,omitempty"`
+
+	// Alertmanager configures the alerts of an Alertmanager sink. Only allowed
+	// with Type Alertmanager.
+	// +optional
+	Alertmanager *AlertmanagerSink `json:"alertmanager,omitempty"`
+}
+
+// AlertmanagerSink configures the alerts posted to Alertmanager. Alerts are
+// labeled with alertname, rule, namespace, target and status, and annotated
+// with the title of the notification as summary and its fields.
+type AlertmanagerSink struct {
+	// Labels are added to every alert, e.g. a severity to route on. An
+	// alertname label replaces the default CronJobNotification.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Expiry is how long an alert fires when the CronJob or CronWorkflow does
+	// not recover or fail again. Defaults to 24h.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+}
+
+// EmailSink configures the SMTP server emails are sent through and their
+// sender and recipients.
+type EmailSink struct {
+	// Host is the host name of the SMTP server.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port is the port of the SMTP server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=587
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// TLS is how the connection is secured: "StartTLS" upgrades it with the
+	// STARTTLS command, "TLS" connects with TLS right away, usually on port
+	// 465, and "None" sends emails unencrypted.
+	// +kubebuilder:validation:Enum=None;StartTLS;TLS
+	// +kubebuilder:default=StartTLS
+	// +optional
+	TLS string `json:"tls,omitempty"`
+
+	// Username authenticates to the SMTP server with PLAIN authentication,
+	// together with the password in PasswordSecretRef.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef references a Secret containing the password of Username.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// From is the sender address, e.g. "Notifier <notifier@example.com>".
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// To are the recipient addresses.
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+
+	// MaxRecipientsPerMessage limits the recipients of a single email. Larger
+	// recipient lists are split over several emails. Defaults to 50.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRecipientsPerMessage int32 `json:"maxRecipientsPerMessage,omitempty"`
+}
+
+// IncidentSink configures the incidents opened in PagerDuty or Opsgenie.
+// Incidents are deduplicated by rule and CronJob or CronWorkflow, so
+// repeated failures update the open incident rather than opening new ones.
+type IncidentSink struct {
+	// KeySecretRef references a Secret containing the integration key of a
+	// PagerDuty Events API v2 integration, or the API key of an Opsgenie API
+	// integration.
+	KeySecretRef corev1.SecretKeySelector `json:"keySecretRef"`
+
+	// Severity is the severity of PagerDuty incidents. For Opsgenie, critical
+	// is priority P1, error P2, warning P3 and info P4.
+	// +kubebuilder:validation:Enum=critical;error;warning;info
+	// +kubebuilder:default=critical
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// APIURL overrides the endpoint of the service, e.g.
+	// https://api.eu.opsgenie.com for the EU instance of Opsgenie. Defaults to
+	// https://events.pagerduty.com/v2/enqueue and https://api.opsgenie.com.
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+// WebhookSink configures the requests of a generic webhook.
+type WebhookSink struct {
+	// BodyTemplate is a Go template rendering the body of the request from
+	// the notification, with the fields .Title, .Status, .Color, .Fields and
+	// .Object, the object that triggered it. The json function encodes a
+	// value as JSON. Defaults to the notification encoded as JSON.
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+
+	// SigningSecretRef references a Secret containing a key the requests
+	// are signed with. The X-Signature-256 header carries "sha256=" followed
+	// by the hex HMAC-SHA256 of the X-Signature-Timestamp header, a dot and
+	// the body.
+	// +optional
+	SigningSecretRef *corev1.SecretKeySelector `json:"signingSecretRef,omitempty"`
+
+	// Headers are added to the requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// SinkConfigStatus defines the observed state of SinkConfig.
+type SinkConfigStatus struct {
+	// conditions represent the current state of the SinkConfig resource.
+	// Ready is True when its Secrets were read and its URL, key and body template are valid.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SinkConfig is the Schema for the sinkconfigs API.
+// It configures a service other than Slack that the notifications of
+// SlackNotificationRules in its namespace can be delivered to.
+type SinkConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of SinkConfig
+	// +required
+	Spec SinkConfigSpec `json:"spec"`
+
+	// status defines the observed state of SinkConfig
+	// +optional
+	Status SinkConfigStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SinkConfigList contains a list of SinkConfig
+type SinkConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SinkConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SinkConfig{}, &SinkConfigList{})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChannelPolicy restricts where SlackNotificationRules post and what they mention.
+type ChannelPolicy struct {
+	// NamespaceSelector selects the namespaces of the rules the policy applies
+	// to. The policy applies to all namespaces if it is unset.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedChannels are the channels notifications may be posted to, by
+	// name (e.g., #team-a-alerts) or ID. Names may contain shell patterns
+	// such as #team-a-*. Any channel is allowed if it is empty.
+	// +optional
+	AllowedChannels []string `json:"allowedChannels,omitempty"`
+
+	// DisallowBroadcastMentions rejects notifications mentioning @channel,
+	// @here or @everyone.
+	// +optional
+	DisallowBroadcastMentions bool `json:"disallowBroadcastMentions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// SlackChannelPolicy is the Schema for the slackchannelpolicies API.
+// It is a ChannelPolicy applied to the SlackNotificationRules of every
+// namespace it selects, whichever configuration they use.
+type SlackChannelPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of SlackChannelPolicy
+	// +required
+	Spec ChannelPolicy `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlackChannelPolicyList contains a list of SlackChannelPolicy
+type SlackChannelPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SlackChannelPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlackChannelPolicy{}, &SlackChannelPolicyList{})
+}
@@ -30,19 +30,129 @@ type SlackConfigSpec struct {
 	// +kubebuilder:validation:Enum=Webhook;Token
 	AuthType string `json:"authType"`
 
+	// CredentialProvider selects where the webhook URL, token and secrets
+	// referenced by this configuration are read from: "Secret" reads the key
+	// of a Secret in the namespace of the configuration, "File" reads the file
+	// <name>/<key> of the credentials directory mounted into the controller and
+	// "Env" reads the environment variable <key> of the controller. File and
+	// Env are only available to ClusterSlackConfigs and SlackConfigs in the
+	// namespace of the controller.
+	// +kubebuilder:validation:Enum=Secret;File;Env
+	// +kubebuilder:default=Secret
+	// +optional
+	CredentialProvider string `json:"credentialProvider,omitempty"`
+
 	// WebhookUrlSecretRef references a Secret containing the Webhook URL. Required if AuthType is Webhook.
 	// +optional
 	WebhookURLSecretRef *corev1.SecretKeySelector `json:"webhookUrlSecretRef,omitempty"`
 
 	// TokenSecretRef references a Secret containing the Slack OAuth Token. Required if AuthType is Token.
+	// With TokenRotation, the controller writes the rotated token to this
+	// Secret, creating it if needed. An existing Secret has to be labeled
+	// app.kubernetes.io/managed-by=slack-notifier-controller.
 	// +optional
 	TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
 
+	// TokenRotation refreshes the token of a Slack App with token rotation
+	// enabled before it expires. Only supported with AuthType Token and
+	// CredentialProvider Secret.
+	// +optional
+	TokenRotation *SlackTokenRotation `json:"tokenRotation,omitempty"`
+
 	// Channel is the default channel to send notifications to.
 	// +optional
 	Channel string `json:"channel,omitempty"`
+
+	// Interactivity configures how interactive messages (e.g., Acknowledge buttons) are handled.
+	// Only supported with AuthType Token.
+	// +optional
+	Interactivity *SlackInteractivity `json:"interactivity,omitempty"`
+
+	// ChannelPolicies restrict the channels and mentions of the
+	// SlackNotificationRules using this configuration. Every policy selecting
+	// the namespace of a rule applies.
+	// +optional
+	ChannelPolicies []ChannelPolicy `json:"channelPolicies,omitempty"`
+}
+
+// SlackTokenRotation configures the refresh of rotating Slack App tokens.
+// Refreshed tokens expire after 12 hours and are exchanged for new ones with
+// oauth.v2.access an hour before. The access token and the refresh token
+// issued with it are stored in the Secret of TokenSecretRef.
+type SlackTokenRotation struct {
+	// ClientID is the client ID of the Slack App.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+
+	// ClientSecretRef references a Secret containing the client secret of the Slack App.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+
+	// RefreshTokenSecretRef references a Secret containing the refresh token
+	// (starts with xoxe-) issued when the App was installed. It is only used
+	// until the controller has stored a refresh token in the Secret of
+	// TokenSecretRef.
+	RefreshTokenSecretRef corev1.SecretKeySelector `json:"refreshTokenSecretRef"`
+}
+
+// RotatedRefreshTokenKey is the key the refresh token of a rotating token is
+// stored with in the Secret of TokenSecretRef.
+const RotatedRefreshTokenKey = "refresh-token"
+
+// SlackInteractivity configures interactive messages of the Slack App.
+// Requests are received over HTTP, verified with SigningSecretRef, or over
+// Socket Mode, authenticated with AppTokenSecretRef.
+// +kubebuilder:validation:XValidation:rule="has(self.signingSecretRef) || has(self.appTokenSecretRef)",message="signingSecretRef or appTokenSecretRef is required"
+type SlackInteractivity struct {
+	// SigningSecretRef references a Secret containing the Slack App signing secret.
+	// Interactivity requests sent over HTTP are rejected unless they are signed with it.
+	// +optional
+	SigningSecretRef *corev1.SecretKeySelector `json:"signingSecretRef,omitempty"`
+
+	// AppTokenSecretRef references a Secret containing an app-level token
+	// (starts with xapp-) with the connections:write scope. When set, the
+	// controller receives interactivity requests over a Socket Mode connection,
+	// so no public endpoint is needed.
+	// +optional
+	AppTokenSecretRef *corev1.SecretKeySelector `json:"appTokenSecretRef,omitempty"`
+
+	// Authorizations grant Slack users and user groups permission to perform
+	// actions on resources in the namespace of this SlackConfig. They apply only
+	// to notifications of resources in that namespace: actions on resources in
+	// namespaces that use this SlackConfig through a SlackConfigGrant are refused.
+	// Authorizations of a ClusterSlackConfig are ignored.
+	// +optional
+	Authorizations []SlackAuthorization `json:"authorizations,omitempty"`
+
+	// SlashCommandChannels are the Slack channel IDs (e.g., C0123ABCD) allowed to
+	// query CronJobs and CronWorkflows in the namespace of this SlackConfig with
+	// the /k8s-cron slash command.
+	// +optional
+	SlashCommandChannels []string `json:"slashCommandChannels,omitempty"`
 }
 
+// SlackAuthorization grants Slack users and user groups permission to perform actions.
+type SlackAuthorization struct {
+	// Users are Slack user IDs (e.g., U0123ABCD).
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// UserGroups are Slack user group IDs (e.g., S0123ABCD).
+	// +optional
+	UserGroups []string `json:"userGroups,omitempty"`
+
+	// Actions are the permitted actions.
+	// +kubebuilder:validation:MinItems=1
+	Actions []NotificationAction `json:"actions"`
+}
+
+// Condition types reported in the status of SlackConfig and SlackNotificationRule.
+const (
+	// ConditionReady is True when the resource was verified and notifications can be sent with it.
+	ConditionReady = "Ready"
+	// ConditionDegraded is True when a SlackConfig cannot be used; its reason tells why.
+	ConditionDegraded = "Degraded"
+)
+
 // SlackConfigStatus defines the observed state of SlackConfig.
 type SlackConfigStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -64,6 +174,25 @@ type SlackConfigStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// TokenExpirationTime is when the current rotating token expires. It is
+	// only set with TokenRotation.
+	// +optional
+	TokenExpirationTime *metav1.Time `json:"tokenExpirationTime,omitempty"`
+
+	// Channel is the channel ChannelID was looked up for.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// ChannelID is the ID of Channel. It is remembered so that a channel given
+	// by name is not searched for in the channel list on every verification.
+	// +optional
+	ChannelID string `json:"channelID,omitempty"`
+
+	// TeamID is the ID of the Slack workspace the token belongs to. Slash
+	// commands are only accepted from it.
+	// +optional
+	TeamID string `json:"teamID,omitempty"`
 }
 
 // +kubebuilder:object:root=true
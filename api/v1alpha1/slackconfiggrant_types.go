@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SlackConfigGrantSpec defines the desired state of SlackConfigGrant
+type SlackConfigGrantSpec struct {
+	// From lists the namespaces whose SlackNotificationRules may reference
+	// SlackConfigs in the namespace of the grant.
+	// +kubebuilder:validation:MinItems=1
+	From []SlackConfigGrantFrom `json:"from"`
+
+	// To lists the SlackConfigs that may be referenced. All SlackConfigs in
+	// the namespace of the grant may be referenced if it is empty.
+	// +optional
+	To []SlackConfigGrantTo `json:"to,omitempty"`
+}
+
+// SlackConfigGrantFrom is a namespace allowed to reference SlackConfigs.
+type SlackConfigGrantFrom struct {
+	// Namespace is the namespace of the referencing SlackNotificationRules.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace"`
+}
+
+// SlackConfigGrantTo is a SlackConfig that may be referenced.
+type SlackConfigGrantTo struct {
+	// Name is the name of the SlackConfig.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlackConfigGrant is the Schema for the slackconfiggrants API.
+// It allows SlackNotificationRules in other namespaces to reference the
+// SlackConfigs of its namespace, whose Secrets stay in that namespace.
+type SlackConfigGrant struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of SlackConfigGrant
+	// +required
+	Spec SlackConfigGrantSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlackConfigGrantList contains a list of SlackConfigGrant
+type SlackConfigGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SlackConfigGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlackConfigGrant{}, &SlackConfigGrantList{})
+}
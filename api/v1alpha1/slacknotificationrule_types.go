@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -33,11 +34,67 @@ type SlackNotificationRuleSpec struct {
 	// LabelSelector selects the resources to be monitored.
 	LabelSelector metav1.LabelSelector `json:"labelSelector"`
 
-	// SlackConfigRef references the SlackConfig to use.
-	SlackConfigRef corev1.LocalObjectReference `json:"slackConfigRef"`
+	// SlackConfigRef references the SlackConfig or ClusterSlackConfig to use.
+	SlackConfigRef SlackConfigReference `json:"slackConfigRef"`
 
 	// Notifications defines the rules for sending notifications.
-	Notifications []NotificationRule `json:"notifications"`
+	// The defaulting webhook fills in notifications for failures when it is empty.
+	// +optional
+	Notifications []NotificationRule `json:"notifications,omitempty"`
+
+	// History configures how many notifications of the rule are kept in its
+	// status, and for how long.
+	// +optional
+	History *HistoryRetention `json:"history,omitempty"`
+
+	// DryRun evaluates and renders the notifications of the rule without
+	// sending them. They are logged and recorded in the history and events of
+	// the rule instead, e.g. to try a new rule or template next to the rule
+	// it is meant to replace.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// HistoryRetention bounds the notification records kept in the status of a rule.
+type HistoryRetention struct {
+	// Limit is the number of records kept. The oldest records are dropped first.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	// +optional
+	Limit int32 `json:"limit,omitempty"`
+
+	// MaxAge drops records last attempted longer ago. Records are kept
+	// regardless of their age when it is unset.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// Kinds of configuration a SlackConfigReference can reference.
+const (
+	KindSlackConfig        = "SlackConfig"
+	KindClusterSlackConfig = "ClusterSlackConfig"
+)
+
+// SlackConfigReference references a SlackConfig or a ClusterSlackConfig.
+// +kubebuilder:validation:XValidation:rule="!has(self.namespace) || !has(self.kind) || self.kind == 'SlackConfig'",message="namespace is only allowed for a SlackConfig"
+type SlackConfigReference struct {
+	// Kind is SlackConfig or ClusterSlackConfig. Defaults to SlackConfig for a
+	// SlackNotificationRule and to ClusterSlackConfig for a ClusterSlackNotificationRule.
+	// +kubebuilder:validation:Enum=SlackConfig;ClusterSlackConfig
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the SlackConfig or ClusterSlackConfig.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the SlackConfig. Defaults to the namespace
+	// of the rule. A SlackConfig in another namespace is only used when a
+	// SlackConfigGrant in that namespace allows the namespace of the rule.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type NotificationRule struct {
@@ -51,33 +108,303 @@ type NotificationRule struct {
 	// Channel overrides the default channel in SlackConfig.
 	// +optional
 	Channel string `json:"channel,omitempty"`
+
+	// Critical marks the notification as critical. Critical notifications ignore QuietHours.
+	// +optional
+	Critical bool `json:"critical,omitempty"`
+
+	// QuietHours holds back or reroutes the notification during the given time windows.
+	// +optional
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+
+	// Escalation posts the notification with an Acknowledge button and escalates it
+	// if nobody acknowledges it in time. Requires a SlackConfig with AuthType Token and Interactivity.
+	// +optional
+	Escalation *Escalation `json:"escalation,omitempty"`
+
+	// Actions are buttons attached to the notification. Rerun and Resubmit are only
+	// offered for CronJobs and CronWorkflows respectively.
+	// Requires a SlackConfig with AuthType Token and Interactivity.
+	// +optional
+	Actions []NotificationAction `json:"actions,omitempty"`
+
+	// Destinations sends the notification to each of the given configurations
+	// and channels instead of the channel of the SlackConfigRef of the rule, e.g.
+	// to a team channel in one workspace and an SRE channel in another.
+	// Cannot be combined with Channel, Escalation or quiet hours that reroute.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Destinations []NotificationDestination `json:"destinations,omitempty"`
+}
+
+// NotificationDestination is a configuration and channel a notification is
+// sent to, or a sink.
+type NotificationDestination struct {
+	// SlackConfigRef references the SlackConfig or ClusterSlackConfig to send
+	// through. Defaults to the SlackConfigRef of the rule.
+	// +optional
+	SlackConfigRef *SlackConfigReference `json:"slackConfigRef,omitempty"`
+
+	// Channel is the channel to post to. Defaults to the channel of the configuration.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// SinkRef references a SinkConfig in the namespace of the rule to deliver
+	// to instead of Slack. It cannot be combined with SlackConfigRef and
+	// Channel, and is not available to ClusterSlackNotificationRules.
+	// +optional
+	SinkRef *corev1.LocalObjectReference `json:"sinkRef,omitempty"`
+}
+
+// NotificationAction is an action that can be performed from a notification.
+//   - Rerun: create a Job from the CronJob's jobTemplate.
+//   - Suspend/Resume: suspend or resume the CronJob or CronWorkflow.
+//   - Resubmit: submit a copy of the Workflow.
+//
+// +kubebuilder:validation:Enum=Rerun;Suspend;Resume;Resubmit
+type NotificationAction string
+
+const (
+	ActionRerun    NotificationAction = "Rerun"
+	ActionSuspend  NotificationAction = "Suspend"
+	ActionResume   NotificationAction = "Resume"
+	ActionResubmit NotificationAction = "Resubmit"
+)
+
+// Escalation defines how an unacknowledged notification is escalated.
+type Escalation struct {
+	// After is how long to wait for an acknowledgement before escalating.
+	After metav1.Duration `json:"after"`
+
+	// Channel is the channel the notification is re-posted to. Defaults to the channel of the notification.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// MentionGroup is the ID of a Slack user group (e.g., an on-call group) mentioned in the escalation.
+	// +optional
+	MentionGroup string `json:"mentionGroup,omitempty"`
+}
+
+// QuietHours defines when a notification is considered quiet and what to do with it.
+type QuietHours struct {
+	// TimeZone is the IANA time zone the windows are evaluated in (e.g., Asia/Tokyo). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows are the periods during which notifications are quiet.
+	// +kubebuilder:validation:MinItems=1
+	Windows []TimeWindow `json:"windows"`
+
+	// Action is applied to notifications that fall into a window.
+	// "Defer" holds the notification until no window is active, "Reroute" sends it to Channel instead.
+	// +kubebuilder:validation:Enum=Defer;Reroute
+	// +kubebuilder:default=Defer
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// Channel is the low-priority channel used when Action is Reroute.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+}
+
+// TimeWindow is a recurring period of the day.
+type TimeWindow struct {
+	// Days are the weekdays the window starts on. Empty means every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the start of the window in HH:MM (24-hour) format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the end of the window in HH:MM (24-hour) format.
+	// A window whose End is not after Start ends on the following day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
 }
 
+// Weekday is an abbreviated day of the week.
+// +kubebuilder:validation:Enum=Sun;Mon;Tue;Wed;Thu;Fri;Sat
+type Weekday string
+
 // SlackNotificationRuleStatus defines the observed state of SlackNotificationRule.
 type SlackNotificationRuleStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// MatchedTargets are the names of the CronJobs or CronWorkflows currently
+	// selected by the rule, sorted and limited to the first 50.
+	// +optional
+	MatchedTargets []string `json:"matchedTargets,omitempty"`
+
+	// MatchedTargetCount is the number of CronJobs or CronWorkflows currently selected by the rule.
+	// +optional
+	MatchedTargetCount int32 `json:"matchedTargetCount,omitempty"`
 
-	// For Kubernetes API conventions, see:
-	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+	// LastNotificationTime is when a notification of the rule was last sent.
+	// +optional
+	LastNotificationTime *metav1.Time `json:"lastNotificationTime,omitempty"`
+
+	// SentCount is the number of notifications of the rule sent.
+	// +optional
+	SentCount int64 `json:"sentCount,omitempty"`
+
+	// FailedCount is the number of notifications of the rule that could not be sent.
+	// +optional
+	FailedCount int64 `json:"failedCount,omitempty"`
+
+	// Destinations reports the deliveries to the destinations of the
+	// notifications of the rule, by destination.
+	// +optional
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+
+	// Incidents are the PagerDuty incidents and Opsgenie alerts opened by the
+	// rule that have not been resolved yet.
+	// +optional
+	Incidents []IncidentStatus `json:"incidents,omitempty"`
+
+	// History records the latest notifications of the rule, oldest first,
+	// within the retention of spec.history.
+	// +optional
+	History []NotificationRecord `json:"history,omitempty"`
 
 	// conditions represent the current state of the SlackNotificationRule resource.
-	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
-	//
-	// Standard condition types include:
-	// - "Available": the resource is fully functional
-	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
-	//
-	// The status of each condition is one of True, False, or Unknown.
+	// Ready is True when the rule is valid and its SlackConfig is Ready.
 	// +listType=map
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// IncidentStatus is an incident opened for the failed run of a CronJob or
+// CronWorkflow. It is resolved when a later run succeeds.
+type IncidentStatus struct {
+	// Sink is the name of the SinkConfig the incident was opened with.
+	Sink string `json:"sink"`
+
+	// Target is the name of the CronJob or CronWorkflow.
+	Target string `json:"target"`
+
+	// DedupKey is the PagerDuty dedup key or Opsgenie alias of the incident.
+	DedupKey string `json:"dedupKey"`
+
+	// Status is the status of the notification that triggered the incident,
+	// which labels Alertmanager alerts.
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Run is the name of the Job or Workflow that last triggered the incident.
+	Run string `json:"run"`
+
+	// RunCreationTime is when Run was created. Only runs created after it resolve the incident.
+	RunCreationTime metav1.Time `json:"runCreationTime"`
+
+	// LastTriggerTime is when the incident was last triggered.
+	LastTriggerTime metav1.Time `json:"lastTriggerTime"`
+}
+
+// NotificationRecord records a notification of a rule for a run of a CronJob
+// or CronWorkflow to a destination.
+type NotificationRecord struct {
+	// TriggerUID is the UID of the Job or Workflow the notification is about.
+	// +optional
+	TriggerUID types.UID `json:"triggerUID,omitempty"`
+
+	// Trigger is the name of the Job or Workflow.
+	Trigger string `json:"trigger"`
+
+	// Namespace is the namespace of the Job or Workflow and of Target.
+	Namespace string `json:"namespace"`
+
+	// Target is the name of the CronJob or CronWorkflow.
+	Target string `json:"target"`
+
+	// Status is the status the notification is for, e.g. Failed.
+	Status string `json:"status"`
+
+	// SlackConfigRef references the configuration the notification is sent
+	// through. It is unset for a sink.
+	// +optional
+	SlackConfigRef SlackConfigReference `json:"slackConfigRef,omitzero"`
+
+	// Sink is the name of the SinkConfig the notification is sent to.
+	// +optional
+	Sink string `json:"sink,omitempty"`
+
+	// Channel is the channel of the notification. Empty for the channel of the configuration.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Title is the rendered title of the notification.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// ChannelID is the ID of the Slack channel the message was posted to.
+	// +optional
+	ChannelID string `json:"channelID,omitempty"`
+
+	// MessageTS is the timestamp identifying the posted Slack message, to
+	// update it. It is only known for messages posted with token authentication.
+	// +optional
+	MessageTS string `json:"messageTS,omitempty"`
+
+	// Attempts is the number of times the notification was sent or tried.
+	Attempts int32 `json:"attempts"`
+
+	// Outcome is Delivered, Failed, Suppressed by a channel policy, or DryRun
+	// if it was rendered but not sent by a dry run.
+	// +kubebuilder:validation:Enum=Delivered;Failed;Suppressed;DryRun
+	Outcome string `json:"outcome"`
+
+	// Error is why the last attempt failed or was suppressed.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// FirstAttemptTime is when the notification was first sent or tried.
+	FirstAttemptTime metav1.Time `json:"firstAttemptTime"`
+
+	// LastAttemptTime is when the notification was last sent or tried.
+	LastAttemptTime metav1.Time `json:"lastAttemptTime"`
+}
+
+// DestinationStatus is the delivery state of a destination of the notifications of a rule.
+type DestinationStatus struct {
+	// SlackConfigRef references the configuration of the destination, with its kind
+	// and, for a SlackConfig, its namespace. It is unset for a sink.
+	// +optional
+	SlackConfigRef SlackConfigReference `json:"slackConfigRef,omitzero"`
+
+	// Sink is the name of the SinkConfig of the destination.
+	// +optional
+	Sink string `json:"sink,omitempty"`
+
+	// Channel is the channel of the destination. Empty for the channel of the configuration.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// LastNotificationTime is when a notification was last sent to the destination.
+	// +optional
+	LastNotificationTime *metav1.Time `json:"lastNotificationTime,omitempty"`
+
+	// SentCount is the number of notifications sent to the destination.
+	// +optional
+	SentCount int64 `json:"sentCount,omitempty"`
+
+	// FailedCount is the number of notifications that could not be sent to the destination.
+	// +optional
+	FailedCount int64 `json:"failedCount,omitempty"`
+
+	// LastError is why the last notification could not be sent to the
+	// destination. It is cleared when a notification is sent.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetResource`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedTargetCount`
+// +kubebuilder:printcolumn:name="Sent",type=integer,JSONPath=`.status.sentCount`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SlackNotificationRule is the Schema for the slacknotificationrules API
 type SlackNotificationRule struct {
@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/controller"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// runPreview prints the messages the rules would send for a Job or Workflow.
+func runPreview(ctx context.Context, args []string) error {
+	fs := newFlagSet("preview", "(job|workflow) NAME [flags]")
+	var o options
+	o.addFlags(fs, true)
+	status := fs.String("status", "", "The status to preview the notifications of, e.g. Failed. Defaults to the status of the Job or Workflow.")
+	rule := fs.String("rule", "", "Only preview the notifications of this SlackNotificationRule or ClusterSlackNotificationRule.")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	kind, name, err := kindAndName(args, controller.KindJob, controller.KindWorkflow)
+	if err != nil {
+		return err
+	}
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	triggerObj, targetObj, triggerStatus, err := controller.LoadTrigger(ctx, c, kind, client.ObjectKey{Namespace: namespace, Name: name})
+	if err != nil {
+		return err
+	}
+	if *status == "" {
+		*status = triggerStatus
+	}
+	notifier := &controller.Notifier{Client: c, ClusterResourceNamespace: o.clusterResourceNamespace}
+	previews, err := notifier.Preview(ctx, triggerObj, targetObj, *status)
+	if err != nil {
+		return err
+	}
+
+	shown := 0
+	for _, p := range previews {
+		if *rule != "" && p.Rule != *rule && p.Rule != namespace+"/"+*rule {
+			continue
+		}
+		shown++
+		fmt.Printf("# %s: %s notification to %s\n", p.Rule, p.Status, p.Destination)
+		if p.Err != nil {
+			fmt.Printf("# Not sent: %v\n", p.Err)
+		}
+		var payload any
+		switch {
+		case p.Slack != nil:
+			payload = p.Slack
+		case p.Sink != nil:
+			payload = p.Sink
+		default:
+			continue
+		}
+		out, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode message: %w", err)
+		}
+		fmt.Println(string(out))
+	}
+	if shown == 0 {
+		fmt.Printf("No rule sends a %s notification for %s %s/%s\n", *status, kind, namespace, name)
+	}
+	return nil
+}
+
+// runTestSend sends a sample message through a SlackConfig or ClusterSlackConfig.
+func runTestSend(ctx context.Context, args []string) error {
+	fs := newFlagSet("test-send", "(slackconfig|clusterslackconfig) NAME [flags]")
+	var o options
+	o.addFlags(fs, false)
+	channel := fs.String("channel", "", "The channel to post to. Defaults to the channel of the configuration.")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	kind, name, err := kindAndName(args, notificationv1alpha1.KindSlackConfig, notificationv1alpha1.KindClusterSlackConfig)
+	if err != nil {
+		return err
+	}
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	notifier := &controller.Notifier{Client: c, SlackClient: slack.NewClient(), ClusterResourceNamespace: o.clusterResourceNamespace}
+	ref := notificationv1alpha1.SlackConfigReference{Kind: kind, Name: name}
+	channelID, ts, err := notifier.SendTest(ctx, namespace, ref, *channel)
+	if err != nil {
+		return err
+	}
+	if ts == "" {
+		fmt.Printf("Sent a test notification through %s %s\n", kind, name)
+		return nil
+	}
+	fmt.Printf("Sent a test notification through %s %s to channel %s (ts %s)\n", kind, name, channelID, ts)
+	return nil
+}
+
+// runExplain prints which rules select a CronJob or CronWorkflow.
+func runExplain(ctx context.Context, args []string) error {
+	fs := newFlagSet("explain", "(cronjob|cronworkflow) NAME [flags]")
+	var o options
+	o.addFlags(fs, true)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	kind, name, err := kindAndName(args, "CronJob", "CronWorkflow")
+	if err != nil {
+		return err
+	}
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	var targetObj client.Object = &batchv1.CronJob{}
+	if kind == "CronWorkflow" {
+		targetObj = &argov1alpha1.CronWorkflow{}
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, targetObj); err != nil {
+		return fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+	notifier := &controller.Notifier{Client: c, ClusterResourceNamespace: o.clusterResourceNamespace}
+	matches, err := notifier.Explain(ctx, targetObj)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No rules in namespace %s and no cluster rules\n", namespace)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tRULE\tSELECTED\tSTATUSES\tREASON")
+	for _, m := range matches {
+		ruleKind, selected, reason := "SlackNotificationRule", "no", m.Reason
+		if m.Cluster {
+			ruleKind = "ClusterSlackNotificationRule"
+		}
+		if m.Matched {
+			selected, reason = "yes", "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ruleKind, m.Rule, selected, strings.Join(m.Statuses, ","), reason)
+	}
+	return w.Flush()
+}
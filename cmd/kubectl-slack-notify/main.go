@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kubectl-slack-notify previews and tests the notifications of the
+// slack-notifier-controller. Installed on the PATH it runs as the kubectl
+// plugin "kubectl slack-notify".
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+const usage = `kubectl slack-notify previews and tests the notifications of the slack-notifier-controller.
+
+Usage:
+  kubectl slack-notify preview (job|workflow) NAME [--status STATUS] [--rule RULE] [-f FILE]...
+  kubectl slack-notify test-send (slackconfig|clusterslackconfig) NAME [--channel CHANNEL]
+  kubectl slack-notify explain (cronjob|cronworkflow) NAME [-f FILE]...
+
+Commands:
+  preview    Render the notifications the rules would send for a Job or Workflow, without sending them.
+  test-send  Send a sample notification through a SlackConfig or ClusterSlackConfig.
+  explain    Show which rules select a CronJob or CronWorkflow, and why the others do not.
+
+Run "kubectl slack-notify COMMAND -h" for the flags of a command.
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(notificationv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(argov1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func(context.Context, []string) error{
+		"preview":   runPreview,
+		"test-send": runTestSend,
+		"explain":   runExplain,
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+			fmt.Print(usage)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	// The notifier logs what the commands report themselves.
+	log.SetLogger(logr.Discard())
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if err := run(ctx, os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the flags shared by the commands.
+type options struct {
+	kubeconfig               string
+	context                  string
+	namespace                string
+	clusterResourceNamespace string
+	files                    fileList
+}
+
+// addFlags adds the shared flags to fs, and --filename if offline is set.
+func (o *options) addFlags(fs *flag.FlagSet, offline bool) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to $KUBECONFIG or ~/.kube/config.")
+	fs.StringVar(&o.context, "context", "", "The kubeconfig context to use.")
+	fs.StringVar(&o.namespace, "namespace", "", "The namespace. Defaults to the namespace of the kubeconfig context.")
+	fs.StringVar(&o.namespace, "n", "", "Shorthand for --namespace.")
+	fs.StringVar(&o.clusterResourceNamespace, "cluster-resource-namespace", "slack-notifier-controller-system",
+		"The namespace the controller reads the Secrets of ClusterSlackConfigs from.")
+	if offline {
+		fs.Var(&o.files, "filename", "A YAML file with the objects to use instead of the cluster, "+
+			"e.g. the Job, CronJob, rules and SlackConfigs. May be repeated; - reads stdin.")
+		fs.Var(&o.files, "f", "Shorthand for --filename.")
+	}
+}
+
+// client returns a client of the cluster, or of the objects in the files
+// given with --filename, and the namespace to use.
+func (o *options) client() (client.Client, string, error) {
+	if len(o.files) > 0 {
+		namespace := o.namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		c, err := fileClient(o.files, namespace)
+		return c, namespace, err
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+	restConfig, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace := o.namespace
+	if namespace == "" {
+		if namespace, _, err = config.Namespace(); err != nil {
+			return nil, "", fmt.Errorf("failed to get the namespace of the kubeconfig context: %w", err)
+		}
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, namespace, nil
+}
+
+// parse parses the flags of fs anywhere in args and returns the other
+// arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// kindAndName returns the kind among kinds and the name of an object given
+// as "KIND NAME" or "KIND/NAME". Kinds are matched case-insensitively.
+func kindAndName(args []string, kinds ...string) (string, string, error) {
+	if len(args) == 1 {
+		args = strings.SplitN(args[0], "/", 2)
+	}
+	if len(args) != 2 || args[1] == "" {
+		return "", "", fmt.Errorf("expected %s and a name", strings.Join(kinds, " or "))
+	}
+	for _, kind := range kinds {
+		if strings.EqualFold(args[0], kind) {
+			return kind, args[1], nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown kind %q: expected %s", args[0], strings.Join(kinds, " or "))
+}
+
+// newFlagSet returns a FlagSet of the command name printing usage before its
+// flags.
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  kubectl slack-notify %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -37,7 +40,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/cloudevents"
 	"github.com/murasame29/slack-notifier-controller/internal/controller"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/interactivity"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
+	webhooknotificationv1alpha1 "github.com/murasame29/slack-notifier-controller/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,12 +70,51 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var interactivityAddr string
+	var clusterResourceNamespace string
+	var credentialsDir string
+	var credentialCacheTTL time.Duration
+	var cloudEventsURL, cloudEventsMode string
+	var cloudEventsRetries int
+	var cloudEventsBackoff time.Duration
+	var otlpEndpoint string
+	var otlpInsecure bool
+	var traceSampleRatio float64
+	var dryRun bool
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&interactivityAddr, "slack-interactivity-bind-address", "0", "The address the Slack interactivity "+
+		"endpoint binds to (e.g. :8082). Leave as 0 to disable interactive notifications and slash commands.")
+	flag.StringVar(&clusterResourceNamespace, "cluster-resource-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace the Secrets referenced by ClusterSlackConfigs are read from. Defaults to the namespace "+
+			"the controller runs in.")
+	flag.StringVar(&credentialsDir, "credentials-dir", "/var/run/secrets/slack-notifier",
+		"The directory SlackConfigs with credentialProvider File read <name>/<key> from.")
+	flag.DurationVar(&credentialCacheTTL, "credential-cache-ttl", 5*time.Minute,
+		"How long credentials read for notifications are cached. Set to 0 to disable caching. Must be "+
+			"shorter than 1h, how long before they expire rotating tokens are refreshed.")
+	flag.StringVar(&cloudEventsURL, "cloudevents-url", "",
+		"The URL a CloudEvent is posted to for every notification that is delivered, fails or is suppressed, "+
+			"e.g. a Knative broker. Leave empty to disable CloudEvents.")
+	flag.StringVar(&cloudEventsMode, "cloudevents-mode", cloudevents.ModeStructured,
+		"The content mode of CloudEvents: structured or binary.")
+	flag.IntVar(&cloudEventsRetries, "cloudevents-retries", 3,
+		"How often a CloudEvent is resent after a network error, a 429 or a 5xx response.")
+	flag.DurationVar(&cloudEventsBackoff, "cloudevents-retry-backoff", time.Second,
+		"The wait before the first retry of a CloudEvent, doubled for every further retry.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"The host:port of the OTLP gRPC receiver, e.g. an OpenTelemetry collector, spans of reconciles and "+
+			"notifications are exported to. Leave empty to disable tracing.")
+	flag.BoolVar(&otlpInsecure, "otlp-insecure", false, "If set, spans are exported to the OTLP receiver without TLS.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, notifications are rendered, logged and recorded in the history of their rules and as events, "+
+			"but not sent to Slack or sinks.")
+	flag.Float64Var(&traceSampleRatio, "trace-sample-ratio", 1,
+		"The fraction of reconciles traced, from 0 to 1.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -88,6 +136,37 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctx := ctrl.SetupSignalHandler()
+
+	if credentialCacheTTL >= controller.TokenRefreshMargin {
+		setupLog.Error(fmt.Errorf("cache TTL %v is not shorter than %v", credentialCacheTTL, controller.TokenRefreshMargin),
+			"invalid --credential-cache-ttl")
+		os.Exit(1)
+	}
+
+	if otlpEndpoint != "" {
+		if traceSampleRatio < 0 || traceSampleRatio > 1 {
+			setupLog.Error(fmt.Errorf("sample ratio %v is not between 0 and 1", traceSampleRatio), "invalid --trace-sample-ratio")
+			os.Exit(1)
+		}
+		shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+			Endpoint:    otlpEndpoint,
+			Insecure:    otlpInsecure,
+			SampleRatio: traceSampleRatio,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		defer func() {
+			// Export the spans of the last reconciles before exiting.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				setupLog.Error(err, "unable to export remaining spans")
+			}
+		}()
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -180,36 +259,170 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Secrets are read from the API server rather than an informer, which would
+	// keep every Secret of the cluster in memory; the controllers only watch
+	// their metadata and the resolver caches the values it reads.
+	credentialResolver := &credentials.Resolver{
+		Providers: map[string]credentials.Provider{
+			credentials.ProviderSecret: &credentials.SecretProvider{Client: mgr.GetAPIReader()},
+			credentials.ProviderFile:   &credentials.FileProvider{Dir: credentialsDir},
+			credentials.ProviderEnv:    &credentials.EnvProvider{},
+		},
+		TTL: credentialCacheTTL,
+	}
+	notifier := &controller.Notifier{
+		Client:                   mgr.GetClient(),
+		SlackClient:              slack.NewClient(),
+		ClusterResourceNamespace: clusterResourceNamespace,
+		CredentialResolver:       credentialResolver,
+		Recorder:                 mgr.GetEventRecorderFor("slack-notifier"),
+		DryRun:                   dryRun,
+	}
+	if dryRun {
+		setupLog.Info("dry run: notifications are not sent")
+	}
+	if cloudEventsURL != "" {
+		if cloudEventsMode != cloudevents.ModeStructured && cloudEventsMode != cloudevents.ModeBinary {
+			setupLog.Error(fmt.Errorf("unknown content mode %q", cloudEventsMode), "invalid --cloudevents-mode")
+			os.Exit(1)
+		}
+		// Events are published in the background, so that retries do not hold
+		// up reconciles.
+		queue := &cloudevents.Queue{Publisher: &cloudevents.Publisher{
+			URL:     cloudEventsURL,
+			Mode:    cloudEventsMode,
+			Retries: cloudEventsRetries,
+			Backoff: cloudEventsBackoff,
+		}}
+		if err := mgr.Add(queue); err != nil {
+			setupLog.Error(err, "unable to set up CloudEvents publishing")
+			os.Exit(1)
+		}
+		notifier.Events = queue
+	}
+
 	if err = (&controller.SlackConfigReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		CredentialResolver:       credentialResolver,
+		ClusterResourceNamespace: clusterResourceNamespace,
+		APIReader:                mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SlackConfig")
 		os.Exit(1)
 	}
 	if err = (&controller.SlackNotificationRuleReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("slacknotificationrule-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SlackNotificationRule")
 		os.Exit(1)
 	}
 	if err = (&controller.CronJobReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: notifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CronJob")
 		os.Exit(1)
 	}
 	if err = (&controller.CronWorkflowReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: notifier,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CronWorkflow")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterSlackConfigReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClusterResourceNamespace: clusterResourceNamespace,
+		CredentialResolver:       credentialResolver,
+		APIReader:                mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSlackConfig")
+		os.Exit(1)
+	}
+	if err = (&controller.SinkConfigReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		CredentialResolver: credentialResolver,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SinkConfig")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterSlackNotificationRuleReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "CronWorkflow")
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSlackNotificationRule")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupSlackConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SlackConfig")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupSlackNotificationRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SlackNotificationRule")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupClusterSlackConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSlackConfig")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupClusterSlackNotificationRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSlackNotificationRule")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupSlackChannelPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SlackChannelPolicy")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknotificationv1alpha1.SetupSinkConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SinkConfig")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if interactivityAddr != "0" {
+		if err := mgr.Add(&interactivity.Server{
+			BindAddress: interactivityAddr,
+			Handler:     interactivity.NewHandler(notifier, notifier),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up Slack interactivity server")
+			os.Exit(1)
+		}
+	}
+	// Socket Mode connections are only opened for SlackConfigs with an app-level token.
+	if err := mgr.Add(&interactivity.SocketMode{
+		Tokens:   notifier,
+		Actions:  notifier,
+		Commands: notifier,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up Slack Socket Mode")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -220,7 +433,7 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
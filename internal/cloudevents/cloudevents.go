@@ -0,0 +1,166 @@
+// Package cloudevents publishes CloudEvents over HTTP in structured or binary
+// content mode, retrying deliveries that fail transiently.
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SpecVersion is the version of the CloudEvents specification events are published with.
+const SpecVersion = "1.0"
+
+// Content modes of the HTTP protocol binding.
+const (
+	// ModeStructured sends the event as a JSON document with the data in its data member.
+	ModeStructured = "structured"
+	// ModeBinary sends the data as the body and the attributes as ce- headers.
+	ModeBinary = "binary"
+)
+
+const (
+	contentTypeJSON       = "application/json"
+	contentTypeStructured = "application/cloudevents+json; charset=UTF-8"
+	// defaultBackoff is the wait before the first retry of a Publisher without a Backoff.
+	defaultBackoff = time.Second
+)
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Event is a CloudEvent with JSON data.
+type Event struct {
+	ID      string
+	Source  string
+	Type    string
+	Subject string
+	Time    time.Time
+	// Data is encoded as JSON.
+	Data any
+}
+
+// Publisher posts events to an HTTP endpoint such as a Knative broker.
+type Publisher struct {
+	URL string
+	// Mode is ModeStructured or ModeBinary. Defaults to ModeStructured.
+	Mode string
+	// Retries is how often an event is resent after a network error, a 429
+	// or a 5xx response.
+	Retries int
+	// Backoff is the wait before the first retry, doubled for every further
+	// one. Defaults to one second.
+	Backoff time.Duration
+	// Client defaults to an http.Client with a 30 second timeout.
+	Client *http.Client
+}
+
+// statusError is the error of a response with a non-2xx status code.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("event was rejected with status %d: %s", e.code, e.body)
+}
+
+// retryable reports whether sending an event again may succeed after err.
+func retryable(err error) bool {
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.code == http.StatusTooManyRequests || statusErr.code >= 500
+}
+
+// Publish sends event, retrying transient failures up to Retries times.
+func (p *Publisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event data: %w", err)
+	}
+	body, header, err := p.encode(event, data)
+	if err != nil {
+		return err
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err = p.send(ctx, body, header)
+		if err == nil || attempt >= p.Retries || !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to publish event: %w", errors.Join(err, ctx.Err()))
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// encode returns the body and headers of a request carrying event in the
+// content mode of the publisher.
+func (p *Publisher) encode(event Event, data []byte) ([]byte, http.Header, error) {
+	header := http.Header{}
+	switch p.Mode {
+	case "", ModeStructured:
+		envelope := struct {
+			SpecVersion     string          `json:"specversion"`
+			ID              string          `json:"id"`
+			Source          string          `json:"source"`
+			Type            string          `json:"type"`
+			Subject         string          `json:"subject,omitempty"`
+			Time            string          `json:"time"`
+			DataContentType string          `json:"datacontenttype"`
+			Data            json.RawMessage `json:"data"`
+		}{SpecVersion, event.ID, event.Source, event.Type, event.Subject, event.Time.UTC().Format(time.RFC3339Nano), contentTypeJSON, data}
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode event: %w", err)
+		}
+		header.Set("Content-Type", contentTypeStructured)
+		return body, header, nil
+	case ModeBinary:
+		header.Set("Ce-Specversion", SpecVersion)
+		header.Set("Ce-Id", event.ID)
+		header.Set("Ce-Source", event.Source)
+		header.Set("Ce-Type", event.Type)
+		if event.Subject != "" {
+			header.Set("Ce-Subject", event.Subject)
+		}
+		header.Set("Ce-Time", event.Time.UTC().Format(time.RFC3339Nano))
+		header.Set("Content-Type", contentTypeJSON)
+		return data, header, nil
+	}
+	return nil, nil, fmt.Errorf("unknown content mode %q", p.Mode)
+}
+
+// send posts a single request to the endpoint.
+func (p *Publisher) send(ctx context.Context, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header.Clone()
+	client := p.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{code: resp.StatusCode, body: string(msg)}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// request is a request received by the stand-in for an event broker.
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+var _ = Describe("Publisher", func() {
+	var (
+		ctx      context.Context
+		event    Event
+		requests chan request
+		statuses []int
+		calls    atomic.Int32
+		url      string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		event = Event{
+			ID:      "5f0c",
+			Source:  "/apis/notification.murasame29.com/v1alpha1/namespaces/team-a/slacknotificationrules/backups",
+			Type:    "com.murasame29.notification.delivered",
+			Subject: "team-a/backup",
+			Time:    time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+			Data:    map[string]string{"status": "Failed"},
+		}
+		requests = make(chan request, 10)
+		statuses = nil
+		calls.Store(0)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- request{header: r.Header, body: body}
+			if i := int(calls.Add(1)) - 1; i < len(statuses) {
+				w.WriteHeader(statuses[i])
+			}
+		}))
+		DeferCleanup(srv.Close)
+		url = srv.URL
+	})
+
+	It("publishes structured events", func() {
+		p := &Publisher{URL: url}
+		Expect(p.Publish(ctx, event)).To(Succeed())
+
+		var r request
+		Expect(requests).To(Receive(&r))
+		Expect(r.header.Get("Content-Type")).To(Equal("application/cloudevents+json; charset=UTF-8"))
+		var envelope map[string]any
+		Expect(json.Unmarshal(r.body, &envelope)).To(Succeed())
+		Expect(envelope).To(Equal(map[string]any{
+			"specversion":     "1.0",
+			"id":              "5f0c",
+			"source":          event.Source,
+			"type":            "com.murasame29.notification.delivered",
+			"subject":         "team-a/backup",
+			"time":            "2025-01-15T10:00:00Z",
+			"datacontenttype": "application/json",
+			"data":            map[string]any{"status": "Failed"},
+		}))
+	})
+
+	It("publishes binary events", func() {
+		p := &Publisher{URL: url, Mode: ModeBinary}
+		Expect(p.Publish(ctx, event)).To(Succeed())
+
+		var r request
+		Expect(requests).To(Receive(&r))
+		Expect(r.header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(r.header.Get("Ce-Specversion")).To(Equal("1.0"))
+		Expect(r.header.Get("Ce-Id")).To(Equal("5f0c"))
+		Expect(r.header.Get("Ce-Source")).To(Equal(event.Source))
+		Expect(r.header.Get("Ce-Type")).To(Equal("com.murasame29.notification.delivered"))
+		Expect(r.header.Get("Ce-Subject")).To(Equal("team-a/backup"))
+		Expect(r.header.Get("Ce-Time")).To(Equal("2025-01-15T10:00:00Z"))
+		Expect(string(r.body)).To(MatchJSON(`{"status": "Failed"}`))
+	})
+
+	It("retries server errors and throttling", func() {
+		statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+		p := &Publisher{URL: url, Retries: 2, Backoff: time.Millisecond}
+		Expect(p.Publish(ctx, event)).To(Succeed())
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("gives up after the configured retries", func() {
+		statuses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+		p := &Publisher{URL: url, Retries: 1, Backoff: time.Millisecond}
+		Expect(p.Publish(ctx, event)).To(MatchError(ContainSubstring("status 502")))
+		Expect(calls.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("does not retry rejected events", func() {
+		statuses = []int{http.StatusBadRequest}
+		p := &Publisher{URL: url, Retries: 3, Backoff: time.Millisecond}
+		Expect(p.Publish(ctx, event)).To(MatchError(ContainSubstring("status 400")))
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("stops retrying when the context is done", func() {
+		statuses = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		p := &Publisher{URL: url, Retries: 1, Backoff: time.Hour}
+		Expect(p.Publish(ctx, event)).To(MatchError(context.DeadlineExceeded))
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("rejects unknown content modes", func() {
+		p := &Publisher{URL: url, Mode: "batched"}
+		Expect(p.Publish(ctx, event)).To(MatchError(ContainSubstring(`unknown content mode "batched"`)))
+	})
+
+	Context("when queued", func() {
+		It("publishes events in the background once started", func() {
+			statuses = []int{http.StatusServiceUnavailable}
+			q := &Queue{Publisher: &Publisher{URL: url, Retries: 1, Backoff: time.Millisecond}}
+			Expect(q.Publish(ctx, event)).To(Succeed())
+			Expect(requests).NotTo(Receive())
+
+			ctx, cancel := context.WithCancel(ctx)
+			done := make(chan error)
+			go func() { done <- q.Start(ctx) }()
+			Eventually(calls.Load).Should(BeEquivalentTo(2))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		It("drops events when the queue is full", func() {
+			q := &Queue{Publisher: &Publisher{URL: url}, Size: 1}
+			Expect(q.Publish(ctx, event)).To(Succeed())
+			Expect(q.Publish(ctx, event)).To(MatchError(ErrQueueFull))
+		})
+	})
+})
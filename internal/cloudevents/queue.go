@@ -0,0 +1,73 @@
+package cloudevents
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultQueueSize is the number of events a Queue without a Size holds.
+const defaultQueueSize = 1000
+
+// ErrQueueFull is returned by Queue.Publish when an event is dropped because
+// the queue is full.
+var ErrQueueFull = errors.New("event queue is full")
+
+// Queue publishes events in the background, so that callers are not held up
+// by retries to a slow or unreachable endpoint. It is a manager Runnable:
+// events are only published once it is started.
+type Queue struct {
+	Publisher *Publisher
+	// Size is how many events may wait to be published before further events
+	// are dropped. Defaults to 1000.
+	Size int
+
+	once   sync.Once
+	events chan Event
+}
+
+func (q *Queue) queue() chan Event {
+	q.once.Do(func() {
+		size := q.Size
+		if size <= 0 {
+			size = defaultQueueSize
+		}
+		q.events = make(chan Event, size)
+	})
+	return q.events
+}
+
+// Publish queues event without waiting for it to be published. Events that
+// fail to publish are logged.
+func (q *Queue) Publish(_ context.Context, event Event) error {
+	select {
+	case q.queue() <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Start publishes queued events until ctx is done.
+func (q *Queue) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("cloudevents")
+	events := q.queue()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			if err := q.Publisher.Publish(ctx, event); err != nil {
+				logger.Error(err, "Failed to publish event", "id", event.ID, "type", event.Type, "subject", event.Subject)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Events
+// queued on any replica are published.
+func (q *Queue) NeedLeaderElection() bool {
+	return false
+}
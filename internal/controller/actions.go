@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// auditLog records every action requested from Slack, whether it was performed or not.
+var auditLog = logf.Log.WithName("audit")
+
+// Workflow labels maintained by Argo that must not be copied to a resubmitted Workflow.
+var argoRuntimeLabels = []string{
+	"workflows.argoproj.io/completed",
+	"workflows.argoproj.io/phase",
+	"workflows.argoproj.io/workflow-archiving-status",
+}
+
+// actionSupported reports whether action can be performed on a target of the given kind.
+func actionSupported(action notificationv1alpha1.NotificationAction, targetKind string) bool {
+	switch action {
+	case notificationv1alpha1.ActionRerun:
+		return targetKind == "CronJob"
+	case notificationv1alpha1.ActionResubmit:
+		return targetKind == "CronWorkflow"
+	case notificationv1alpha1.ActionSuspend, notificationv1alpha1.ActionResume:
+		return targetKind == "CronJob" || targetKind == "CronWorkflow"
+	}
+	return false
+}
+
+func notificationAction(actionID string) notificationv1alpha1.NotificationAction {
+	for action, button := range actionButtons {
+		if button.ActionID == actionID {
+			return action
+		}
+	}
+	return ""
+}
+
+// performAction performs an action clicked on a notification if the user is
+// authorized to, audits the outcome and replies to the notification with it.
+func (n *Notifier) performAction(ctx context.Context, callback *goslack.InteractionCallback, action *goslack.BlockAction) error {
+	ref, err := decodeNotificationRef(action.Value)
+	if err != nil {
+		return err
+	}
+	rule, config, err := n.getNotificationRule(ctx, ref)
+	if err != nil {
+		return err
+	}
+	dest, err := n.resolveDestination(ctx, *rule, notificationv1alpha1.NotificationRule{Status: ref.Status})
+	if err != nil {
+		return err
+	}
+
+	act := notificationAction(action.ActionID)
+	userID := callback.User.ID
+	audit := auditLog.WithValues(
+		"user", userID, "team", callback.Team.ID, "action", act,
+		"namespace", ref.Namespace, "kind", ref.targetKind(), "name", ref.Target, "rule", ref.Rule,
+	)
+	subject := fmt.Sprintf("%s %s/%s", ref.targetKind(), ref.Namespace, ref.Target)
+
+	if err := n.authorizeAction(ctx, dest.token, rule, config, ref, act, userID); err != nil {
+		audit.Info("Action denied", "reason", err.Error())
+		n.reply(ctx, dest, callback, fmt.Sprintf(":no_entry: <@%s> is not permitted to %s %s: %s", userID, strings.ToLower(string(act)), subject, err))
+		return nil
+	}
+
+	result, err := n.runAction(ctx, ref, act)
+	if err != nil {
+		audit.Info("Action failed", "error", err.Error())
+		n.reply(ctx, dest, callback, fmt.Sprintf(":x: <@%s> failed to %s %s: %s", userID, strings.ToLower(string(act)), subject, err))
+		return err
+	}
+	audit.Info("Action performed", "result", result)
+	n.reply(ctx, dest, callback, fmt.Sprintf(":white_check_mark: <@%s> %s", userID, result))
+	return nil
+}
+
+// authorizeAction checks that the notification offers the action and that the
+// SlackConfig grants it to the user, directly or through a user group. Only a
+// SlackConfig in the namespace of the target grants actions: the authorizations
+// of ClusterSlackConfigs and of SlackConfigs shared with other namespaces do not
+// reach the namespaces that use them.
+func (n *Notifier) authorizeAction(ctx context.Context, token string, rule *notificationv1alpha1.SlackNotificationRule, config *notificationv1alpha1.SlackConfig, ref NotificationRef, act notificationv1alpha1.NotificationAction, userID string) error {
+	if !actionSupported(act, ref.targetKind()) {
+		return fmt.Errorf("%s is not supported for %s", act, ref.targetKind())
+	}
+	offered := false
+	for _, note := range rule.Spec.Notifications {
+		if strings.EqualFold(note.Status, ref.Status) && slices.Contains(note.Actions, act) {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		return fmt.Errorf("rule %s does not offer %s", rule.Name, act)
+	}
+
+	if config.Spec.Interactivity == nil {
+		return fmt.Errorf("no authorization grants %s", act)
+	}
+	if configRefKind(*rule) != notificationv1alpha1.KindSlackConfig || config.Namespace != ref.Namespace {
+		return fmt.Errorf("only a SlackConfig in namespace %s can grant %s", ref.Namespace, act)
+	}
+	for _, auth := range config.Spec.Interactivity.Authorizations {
+		if !slices.Contains(auth.Actions, act) {
+			continue
+		}
+		if slices.Contains(auth.Users, userID) {
+			return nil
+		}
+		for _, group := range auth.UserGroups {
+			members, err := n.SlackClient.UserGroupMembers(ctx, token, group)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(members, userID) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no authorization grants %s", act)
+}
+
+// runAction performs an authorized action and describes the outcome.
+func (n *Notifier) runAction(ctx context.Context, ref NotificationRef, act notificationv1alpha1.NotificationAction) (string, error) {
+	switch act {
+	case notificationv1alpha1.ActionRerun:
+		var cronJob batchv1.CronJob
+		if err := n.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Target}, &cronJob); err != nil {
+			return "", err
+		}
+		job := jobFromCronJob(&cronJob)
+		if err := n.Client.Create(ctx, job); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("created Job %s/%s from CronJob %s", job.Namespace, job.Name, cronJob.Name), nil
+
+	case notificationv1alpha1.ActionSuspend, notificationv1alpha1.ActionResume:
+		suspend := act == notificationv1alpha1.ActionSuspend
+		if err := n.setSuspend(ctx, ref, suspend); err != nil {
+			return "", err
+		}
+		verb := "resumed"
+		if suspend {
+			verb = "suspended"
+		}
+		return fmt.Sprintf("%s %s %s/%s", verb, ref.targetKind(), ref.Namespace, ref.Target), nil
+
+	case notificationv1alpha1.ActionResubmit:
+		var wf argov1alpha1.Workflow
+		if err := n.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &wf); err != nil {
+			return "", err
+		}
+		resubmitted := resubmittedWorkflow(&wf)
+		if err := n.Client.Create(ctx, resubmitted); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("resubmitted Workflow %s/%s as %s", wf.Namespace, wf.Name, resubmitted.Name), nil
+	}
+	return "", fmt.Errorf("unsupported action %q", act)
+}
+
+func (n *Notifier) setSuspend(ctx context.Context, ref NotificationRef, suspend bool) error {
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Target}
+	switch ref.targetKind() {
+	case "CronJob":
+		var cronJob batchv1.CronJob
+		if err := n.Client.Get(ctx, key, &cronJob); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(cronJob.DeepCopy())
+		cronJob.Spec.Suspend = ptr.To(suspend)
+		return n.Client.Patch(ctx, &cronJob, patch)
+	case "CronWorkflow":
+		var cronWf argov1alpha1.CronWorkflow
+		if err := n.Client.Get(ctx, key, &cronWf); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(cronWf.DeepCopy())
+		cronWf.Spec.Suspend = suspend
+		return n.Client.Patch(ctx, &cronWf, patch)
+	}
+	return fmt.Errorf("unsupported target kind %q", ref.targetKind())
+}
+
+// jobFromCronJob builds a Job from the CronJob's jobTemplate, like
+// "kubectl create job --from=cronjob/<name>" does.
+func jobFromCronJob(cronJob *batchv1.CronJob) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	labels := map[string]string{}
+	for k, v := range cronJob.Spec.JobTemplate.Labels {
+		labels[k] = v
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName:    cronJob.Name + "-manual-",
+			Namespace:       cronJob.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// resubmittedWorkflow builds a copy of wf that starts from scratch, like
+// "argo resubmit" does. It keeps the owner references so the copy is still
+// attributed to the CronWorkflow.
+func resubmittedWorkflow(wf *argov1alpha1.Workflow) *argov1alpha1.Workflow {
+	labels := map[string]string{}
+	for k, v := range wf.Labels {
+		if !slices.Contains(argoRuntimeLabels, k) {
+			labels[k] = v
+		}
+	}
+	labels["workflows.argoproj.io/resubmitted-from-workflow"] = wf.Name
+
+	annotations := map[string]string{}
+	for k, v := range wf.Annotations {
+		if !strings.HasPrefix(k, notificationv1alpha1.GroupVersion.Group+"/") {
+			annotations[k] = v
+		}
+	}
+
+	return &argov1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName:    wf.Name + "-",
+			Namespace:       wf.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: wf.OwnerReferences,
+		},
+		Spec: *wf.Spec.DeepCopy(),
+	}
+}
+
+func (n *Notifier) reply(ctx context.Context, dest *destination, callback *goslack.InteractionCallback, text string) {
+	if err := n.SlackClient.Reply(ctx, dest.token, callback.Container.ChannelID, callback.Container.MessageTs, text); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to reply to notification")
+	}
+}
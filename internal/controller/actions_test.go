@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+var _ = Describe("Notification actions", func() {
+	const namespace = "default"
+
+	var (
+		ctx      context.Context
+		slackFk  *fakeSlackClient
+		notifier *Notifier
+		cronJob  *batchv1.CronJob
+		job      *batchv1.Job
+	)
+
+	newRule := func(name, target string, actions ...notificationv1alpha1.NotificationAction) *notificationv1alpha1.SlackNotificationRule {
+		return &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: target,
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Actions: actions}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		slackFk = &fakeSlackClient{userGroups: map[string][]string{"S0ONCALL": {"U_ONCALL"}}}
+
+		cronJob = &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, UID: "cronjob-uid"},
+			Spec: batchv1.CronJobSpec{
+				Schedule: "0 * * * *",
+				JobTemplate: batchv1.JobTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+					Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "backup", Image: "busybox"}},
+					}}},
+				},
+			},
+		}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace}}
+		cronWf := &argov1alpha1.CronWorkflow{ObjectMeta: metav1.ObjectMeta{Name: "etl", Namespace: namespace}}
+		wf := &argov1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "etl-1", Namespace: namespace,
+				Labels:      map[string]string{"workflows.argoproj.io/cron-workflow": "etl", "workflows.argoproj.io/phase": "Failed"},
+				Annotations: map[string]string{AnnotationEscalations: "{}"},
+			},
+			Spec: argov1alpha1.WorkflowSpec{Entrypoint: "main"},
+		}
+		config := &notificationv1alpha1.SlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackConfigSpec{
+				AuthType:       "Token",
+				TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+				Channel:        "#team",
+				Interactivity: &notificationv1alpha1.SlackInteractivity{
+					SigningSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "signing-secret"},
+					Authorizations: []notificationv1alpha1.SlackAuthorization{
+						{Users: []string{"U_LEAD"}, Actions: []notificationv1alpha1.NotificationAction{"Rerun", "Suspend", "Resume", "Resubmit"}},
+						{UserGroups: []string{"S0ONCALL"}, Actions: []notificationv1alpha1.NotificationAction{"Rerun"}},
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Data:       map[string][]byte{"token": []byte("xoxb-test"), "signing-secret": []byte("s3cr3t")},
+		}
+
+		notifier = &Notifier{
+			Client: fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(
+				cronJob, job, cronWf, wf, config, secret,
+				newRule("cronjobs", "CronJob", "Rerun", "Suspend", "Resubmit"),
+				newRule("workflows", "CronWorkflow", "Resubmit", "Suspend"),
+			).Build(),
+			SlackClient: slackFk,
+		}
+	})
+
+	click := func(actionID, user string, ref NotificationRef) {
+		value, err := ref.encode()
+		Expect(err).NotTo(HaveOccurred())
+		callback := &goslack.InteractionCallback{User: goslack.User{ID: user}}
+		_ = notifier.HandleAction(ctx, callback, &goslack.BlockAction{ActionID: actionID, Value: value})
+	}
+	cronJobRef := NotificationRef{Kind: "Job", Namespace: namespace, Name: "backup-1", Target: "backup", Rule: "cronjobs", Status: "Failed"}
+	workflowRef := NotificationRef{Kind: "Workflow", Namespace: namespace, Name: "etl-1", Target: "etl", Rule: "workflows", Status: "Failed"}
+
+	It("only attaches buttons supported by the target", func() {
+		note := notificationv1alpha1.NotificationRule{Status: "Failed", Actions: []notificationv1alpha1.NotificationAction{"Rerun", "Suspend", "Resubmit"}}
+		buttons := notificationButtons(note, cronJobRef, "v", false)
+		Expect(buttons).To(HaveLen(2))
+		Expect(buttons[0].ActionID).To(Equal(slack.ActionRerun))
+		Expect(buttons[1].ActionID).To(Equal(slack.ActionSuspend))
+	})
+
+	It("reruns a CronJob for users in a granted user group", func() {
+		click(slack.ActionRerun, "U_ONCALL", cronJobRef)
+
+		var jobs batchv1.JobList
+		Expect(notifier.Client.List(ctx, &jobs, client.InNamespace(namespace))).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(2))
+		var manual batchv1.Job
+		for _, j := range jobs.Items {
+			if j.Name != "backup-1" {
+				manual = j
+			}
+		}
+		Expect(manual.Annotations).To(HaveKeyWithValue("cronjob.kubernetes.io/instantiate", "manual"))
+		Expect(manual.Labels).To(HaveKeyWithValue("app", "backup"))
+		Expect(manual.OwnerReferences).To(ConsistOf(HaveField("Name", "backup")))
+		Expect(slackFk.replies).To(ConsistOf(ContainSubstring("created Job")))
+	})
+
+	It("refuses actions that are not granted to the user", func() {
+		click(slack.ActionSuspend, "U_ONCALL", cronJobRef)
+
+		var updated batchv1.CronJob
+		Expect(notifier.Client.Get(ctx, client.ObjectKeyFromObject(cronJob), &updated)).To(Succeed())
+		Expect(updated.Spec.Suspend).To(BeNil())
+		Expect(slackFk.replies).To(ConsistOf(ContainSubstring("not permitted")))
+	})
+
+	It("refuses actions the rule does not offer", func() {
+		click(slack.ActionResume, "U_LEAD", cronJobRef)
+		Expect(slackFk.replies).To(ConsistOf(ContainSubstring("does not offer")))
+	})
+
+	It("suspends a CronJob", func() {
+		click(slack.ActionSuspend, "U_LEAD", cronJobRef)
+
+		var updated batchv1.CronJob
+		Expect(notifier.Client.Get(ctx, client.ObjectKeyFromObject(cronJob), &updated)).To(Succeed())
+		Expect(updated.Spec.Suspend).To(HaveValue(BeTrue()))
+	})
+
+	It("suspends a CronWorkflow", func() {
+		click(slack.ActionSuspend, "U_LEAD", workflowRef)
+
+		var updated argov1alpha1.CronWorkflow
+		Expect(notifier.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "etl"}, &updated)).To(Succeed())
+		Expect(updated.Spec.Suspend).To(BeTrue())
+	})
+
+	It("resubmits a Workflow", func() {
+		click(slack.ActionResubmit, "U_LEAD", workflowRef)
+
+		var wfs argov1alpha1.WorkflowList
+		Expect(notifier.Client.List(ctx, &wfs, client.InNamespace(namespace))).To(Succeed())
+		Expect(wfs.Items).To(HaveLen(2))
+		var resubmitted argov1alpha1.Workflow
+		for _, w := range wfs.Items {
+			if w.Name != "etl-1" {
+				resubmitted = w
+			}
+		}
+		Expect(resubmitted.Labels).To(HaveKeyWithValue("workflows.argoproj.io/resubmitted-from-workflow", "etl-1"))
+		Expect(resubmitted.Labels).To(HaveKeyWithValue("workflows.argoproj.io/cron-workflow", "etl"))
+		Expect(resubmitted.Labels).NotTo(HaveKey("workflows.argoproj.io/phase"))
+		Expect(resubmitted.Annotations).NotTo(HaveKey(AnnotationEscalations))
+		Expect(resubmitted.Spec.Entrypoint).To(Equal("main"))
+	})
+
+	It("refuses actions granted by a SlackConfig in another namespace", func() {
+		var config notificationv1alpha1.SlackConfig
+		Expect(notifier.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "slack"}, &config)).To(Succeed())
+		rule := newRule("cronjobs", "CronJob", "Rerun")
+		rule.Namespace = "team-b"
+		rule.Spec.SlackConfigRef.Namespace = namespace
+		ref := cronJobRef
+		ref.Namespace = "team-b"
+
+		err := notifier.authorizeAction(ctx, "xoxb-test", rule, &config, ref, notificationv1alpha1.ActionRerun, "U_LEAD")
+		Expect(err).To(MatchError(ContainSubstring("only a SlackConfig in namespace team-b")))
+	})
+
+	It("refuses actions granted by a ClusterSlackConfig", func() {
+		var config notificationv1alpha1.SlackConfig
+		Expect(notifier.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "slack"}, &config)).To(Succeed())
+		rule := newRule("cronjobs", "CronJob", "Rerun")
+		rule.Spec.SlackConfigRef.Kind = notificationv1alpha1.KindClusterSlackConfig
+
+		err := notifier.authorizeAction(ctx, "xoxb-test", rule, &config, cronJobRef, notificationv1alpha1.ActionRerun, "U_LEAD")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/cloudevents"
+	"github.com/murasame29/slack-notifier-controller/internal/metrics"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+// Types of the CloudEvents published for notification decisions.
+const (
+	EventTypeDelivered  = "com.murasame29.notification.delivered"
+	EventTypeFailed     = "com.murasame29.notification.failed"
+	EventTypeSuppressed = "com.murasame29.notification.suppressed"
+)
+
+// Outcomes of notification decisions.
+const (
+	OutcomeDelivered  = "Delivered"
+	OutcomeFailed     = "Failed"
+	OutcomeSuppressed = "Suppressed"
+	// OutcomeDryRun is only recorded in the history of rules; dry runs are
+	// published as suppressed.
+	OutcomeDryRun = "DryRun"
+)
+
+// Reasons notifications are suppressed.
+const (
+	SuppressedQuietHours      = "QuietHours"
+	SuppressedPolicyViolation = "PolicyViolation"
+	SuppressedDryRun          = "DryRun"
+)
+
+// EventPublisher publishes CloudEvents, e.g. a *cloudevents.Queue. Publish is
+// called while reconciling, so it should not wait for the event to be sent.
+type EventPublisher interface {
+	Publish(ctx context.Context, event cloudevents.Event) error
+}
+
+// objectReference identifies an object in the data of a CloudEvent.
+type objectReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+}
+
+// eventDestination is where a notification was sent, or would have been.
+type eventDestination struct {
+	SlackConfigRef *notificationv1alpha1.SlackConfigReference `json:"slackConfigRef,omitempty"`
+	Channel        string                                     `json:"channel,omitempty"`
+	Sink           string                                     `json:"sink,omitempty"`
+}
+
+// notificationEvent is the data of the CloudEvent of a notification decision.
+type notificationEvent struct {
+	Rule        objectReference  `json:"rule"`
+	Status      string           `json:"status"`
+	Trigger     objectReference  `json:"trigger"`
+	Target      objectReference  `json:"target"`
+	Destination eventDestination `json:"destination"`
+	Fields      []sink.Field     `json:"fields"`
+	Outcome     string           `json:"outcome"`
+	// Reason is why a notification was suppressed.
+	Reason string `json:"reason,omitempty"`
+	// Error is why a notification could not be delivered.
+	Error string `json:"error,omitempty"`
+}
+
+// recordDecision records the delivery of a notification in the status and
+// history of rule and as events, and publishes its outcome. dest is nil for a
+// notification without destinations, and posted is the Slack message posted
+// for it, if known. Notifications rendered by a dry run are only recorded in
+// the history and as events.
+func (n *Notifier) recordDecision(ctx context.Context, triggerObj, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus, posted postedMessage, sendErr error) {
+	record := n.newRecord(triggerObj, targetObj, rule, note, dest, posted, sendErr)
+	reason := ""
+	switch {
+	case record.Outcome == OutcomeDryRun:
+		// Dry runs are not counted as sent.
+		n.recordHistory(ctx, rule, record)
+		n.recordNotificationEvents(triggerObj, rule, note, dest, record)
+		reason = SuppressedDryRun
+	case errors.Is(sendErr, policy.ErrViolation):
+		// checkPolicy already recorded a PolicyViolation event.
+		n.recordDelivery(ctx, rule, dest, &record, sendErr)
+		reason = SuppressedPolicyViolation
+	default:
+		n.recordDelivery(ctx, rule, dest, &record, sendErr)
+		n.recordNotificationEvents(triggerObj, rule, note, dest, record)
+	}
+	n.reportDecision(ctx, triggerObj, targetObj, rule, note, dest, reason, sendErr)
+}
+
+// reportDecision counts the outcome of a notification of rule in the
+// notification metrics and publishes it as a CloudEvent: suppressed if reason
+// is set, failed if sendErr is, and delivered otherwise. Failing to publish
+// it does not fail the notification.
+func (n *Notifier) reportDecision(ctx context.Context, triggerObj, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus, reason string, sendErr error) {
+	sinkName := metrics.SinkSlack
+	if dest != nil && dest.Sink != "" {
+		sinkName = dest.Sink
+	}
+	outcome := metrics.OutcomeDelivered
+	switch {
+	case reason != "":
+		outcome = metrics.OutcomeSuppressed
+	case sendErr != nil:
+		outcome = metrics.OutcomeFailed
+	}
+	metrics.RecordNotification(rule.Namespace, rule.Name, sinkName, note.Status, outcome)
+	if n.Events == nil {
+		return
+	}
+	data := notificationEvent{
+		Rule:    ruleReference(rule),
+		Status:  note.Status,
+		Trigger: n.objectReference(triggerObj),
+		Target:  n.objectReference(targetObj),
+		Outcome: OutcomeDelivered,
+		Reason:  reason,
+	}
+	if dest != nil {
+		data.Destination = eventDestination{Channel: dest.Channel, Sink: dest.Sink}
+		if dest.Sink == "" {
+			data.Destination.SlackConfigRef = &dest.SlackConfigRef
+		}
+	} else {
+		ref := qualifiedConfigRef(rule)
+		data.Destination = eventDestination{SlackConfigRef: &ref, Channel: note.Channel}
+	}
+	for _, f := range n.buildFields(triggerObj, targetObj, note.Status) {
+		data.Fields = append(data.Fields, sink.Field{Title: f.Title, Value: f.Value})
+	}
+	eventType := EventTypeDelivered
+	switch {
+	case reason != "":
+		data.Outcome, eventType = OutcomeSuppressed, EventTypeSuppressed
+	case sendErr != nil:
+		data.Outcome, eventType = OutcomeFailed, EventTypeFailed
+	}
+	if sendErr != nil {
+		data.Error = sendErr.Error()
+	}
+
+	err := n.Events.Publish(ctx, cloudevents.Event{
+		ID:      string(uuid.NewUUID()),
+		Source:  ruleSource(rule),
+		Type:    eventType,
+		Subject: targetObj.GetNamespace() + "/" + targetObj.GetName(),
+		Time:    n.now(),
+		Data:    data,
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to publish notification event", "rule", rule.Name, "outcome", data.Outcome)
+	}
+}
+
+// ruleReference identifies rule, which may be the view of a ClusterSlackNotificationRule.
+func ruleReference(rule notificationv1alpha1.SlackNotificationRule) objectReference {
+	ref := objectReference{APIVersion: notificationv1alpha1.GroupVersion.String(), Name: rule.Name, UID: string(rule.UID)}
+	if isClusterRule(rule) {
+		ref.Kind = "ClusterSlackNotificationRule"
+	} else {
+		ref.Kind = "SlackNotificationRule"
+		ref.Namespace = rule.Namespace
+	}
+	return ref
+}
+
+// ruleSource is the CloudEvents source of the events of rule: its API path.
+func ruleSource(rule notificationv1alpha1.SlackNotificationRule) string {
+	prefix := "/apis/" + notificationv1alpha1.GroupVersion.String()
+	if isClusterRule(rule) {
+		return prefix + "/clusterslacknotificationrules/" + rule.Name
+	}
+	return prefix + "/namespaces/" + rule.Namespace + "/slacknotificationrules/" + rule.Name
+}
+
+// objectReference identifies obj, with the kind registered in the scheme of the client.
+func (n *Notifier) objectReference(obj client.Object) objectReference {
+	ref := objectReference{Namespace: obj.GetNamespace(), Name: obj.GetName(), UID: string(obj.GetUID())}
+	if gvk, err := apiutil.GVKForObject(obj, n.Client.Scheme()); err == nil {
+		ref.APIVersion, ref.Kind = gvk.GroupVersion().String(), gvk.Kind
+	}
+	return ref
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+var _ = Describe("CloudEvents", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx       context.Context
+		publisher *fakePublisher
+		notifier  *Notifier
+		rule      *notificationv1alpha1.SlackNotificationRule
+		objects   []client.Object
+		job       *batchv1.Job
+		cronJob   *batchv1.CronJob
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		publisher = &fakePublisher{}
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, UID: "0b5c"}}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace, UID: "9d1e"}}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed", Channel: "#team-a"}},
+			},
+		}
+		objects = []client.Object{
+			&notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:       "Token",
+					TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					Channel:        "#general",
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Data:       map[string][]byte{"token": []byte("xoxb-test")},
+			},
+		}
+	})
+
+	notify := func() {
+		c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(append(objects, rule)...).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		notifier = &Notifier{
+			Client:      c,
+			SlackClient: &fakeSlackClient{},
+			Clock:       clocktesting.NewFakePassiveClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)),
+			Events:      publisher,
+		}
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("publishes delivered notifications", func() {
+		notify()
+		Expect(publisher.events).To(HaveLen(1))
+		event := publisher.events[0]
+		Expect(event.ID).NotTo(BeEmpty())
+		Expect(event.Type).To(Equal(EventTypeDelivered))
+		Expect(event.Source).To(Equal("/apis/notification.murasame29.com/v1alpha1/namespaces/team-a/slacknotificationrules/backups"))
+		Expect(event.Subject).To(Equal("team-a/backup"))
+		Expect(event.Time).To(Equal(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)))
+
+		data := event.Data.(notificationEvent)
+		Expect(data.Outcome).To(Equal(OutcomeDelivered))
+		Expect(data.Status).To(Equal("Failed"))
+		Expect(data.Rule).To(Equal(objectReference{APIVersion: "notification.murasame29.com/v1alpha1", Kind: "SlackNotificationRule", Namespace: namespace, Name: "backups"}))
+		Expect(data.Trigger).To(Equal(objectReference{APIVersion: "batch/v1", Kind: "Job", Namespace: namespace, Name: "backup-1", UID: "9d1e"}))
+		Expect(data.Target).To(Equal(objectReference{APIVersion: "batch/v1", Kind: "CronJob", Namespace: namespace, Name: "backup", UID: "0b5c"}))
+		Expect(data.Destination.SlackConfigRef).To(Equal(&notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindSlackConfig, Name: "slack", Namespace: namespace}))
+		Expect(data.Destination.Channel).To(Equal("#team-a"))
+		Expect(data.Fields).To(ContainElement(sink.Field{Title: "Namespace", Value: namespace}))
+	})
+
+	It("publishes notifications held back by quiet hours as suppressed", func() {
+		rule.Spec.Notifications[0].QuietHours = &notificationv1alpha1.QuietHours{
+			TimeZone: "UTC",
+			Windows:  []notificationv1alpha1.TimeWindow{{Start: "09:00", End: "18:00"}},
+		}
+		notify()
+		Expect(publisher.events).To(ConsistOf(HaveField("Type", EventTypeSuppressed)))
+		data := publisher.events[0].Data.(notificationEvent)
+		Expect(data.Outcome).To(Equal(OutcomeSuppressed))
+		Expect(data.Reason).To(Equal(SuppressedQuietHours))
+
+		By("reconciling the Job again before the quiet hours end")
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(publisher.events).To(HaveLen(1))
+	})
+
+	It("publishes notifications blocked by a channel policy as suppressed", func() {
+		objects = append(objects, &notificationv1alpha1.SlackChannelPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "general-only"},
+			Spec:       notificationv1alpha1.ChannelPolicy{AllowedChannels: []string{"#general"}},
+		})
+		notify()
+		Expect(publisher.events).To(ConsistOf(HaveField("Type", EventTypeSuppressed)))
+		data := publisher.events[0].Data.(notificationEvent)
+		Expect(data.Reason).To(Equal(SuppressedPolicyViolation))
+		Expect(data.Error).To(ContainSubstring("channel #team-a is not allowed"))
+	})
+
+	It("publishes failed deliveries for every destination", func() {
+		rule.Spec.Notifications[0].Channel = ""
+		rule.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+			{Channel: "#sre"},
+			{SinkRef: &corev1.LocalObjectReference{Name: "missing"}},
+		}
+		notify()
+		Expect(publisher.events).To(HaveLen(2))
+		Expect(publisher.events[0].Type).To(Equal(EventTypeDelivered))
+		Expect(publisher.events[0].Data.(notificationEvent).Destination.Channel).To(Equal("#sre"))
+		Expect(publisher.events[1].Type).To(Equal(EventTypeFailed))
+		data := publisher.events[1].Data.(notificationEvent)
+		Expect(data.Outcome).To(Equal(OutcomeFailed))
+		Expect(data.Destination).To(Equal(eventDestination{Sink: "missing"}))
+		Expect(data.Error).To(ContainSubstring("failed to get SinkConfig"))
+	})
+})
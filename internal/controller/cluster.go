@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// errNoClusterResourceNamespace is returned when a ClusterSlackConfig is used
+// but the namespace its Secrets are read from is not configured.
+var errNoClusterResourceNamespace = errors.New("the cluster resource namespace is not configured, so ClusterSlackConfigs cannot be used")
+
+// clusterRuleView returns a ClusterSlackNotificationRule as a
+// SlackNotificationRule without a namespace, so that it is evaluated and sent
+// like namespaced rules. Its SlackConfigRef always has a kind.
+func clusterRuleView(rule *notificationv1alpha1.ClusterSlackNotificationRule) notificationv1alpha1.SlackNotificationRule {
+	view := notificationv1alpha1.SlackNotificationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: rule.Name, UID: rule.UID, Generation: rule.Generation},
+		Spec:       *rule.Spec.SlackNotificationRuleSpec.DeepCopy(),
+		Status:     *rule.Status.DeepCopy(),
+	}
+	if view.Spec.SlackConfigRef.Kind == "" {
+		view.Spec.SlackConfigRef.Kind = notificationv1alpha1.KindClusterSlackConfig
+	}
+	return view
+}
+
+// isClusterRule reports whether rule is a view of a ClusterSlackNotificationRule.
+func isClusterRule(rule notificationv1alpha1.SlackNotificationRule) bool {
+	return rule.Namespace == ""
+}
+
+// configRefKind returns the kind of the configuration a rule references.
+func configRefKind(rule notificationv1alpha1.SlackNotificationRule) string {
+	if kind := rule.Spec.SlackConfigRef.Kind; kind != "" {
+		return kind
+	}
+	if isClusterRule(rule) {
+		return notificationv1alpha1.KindClusterSlackConfig
+	}
+	return notificationv1alpha1.KindSlackConfig
+}
+
+// clusterConfigView returns a ClusterSlackConfig as a SlackConfig in namespace,
+// the namespace its Secrets are read from.
+func clusterConfigView(config *notificationv1alpha1.ClusterSlackConfig, namespace string) notificationv1alpha1.SlackConfig {
+	return notificationv1alpha1.SlackConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Namespace: namespace, UID: config.UID, Generation: config.Generation},
+		Spec:       *config.Spec.DeepCopy(),
+		Status:     *config.Status.DeepCopy(),
+	}
+}
+
+// resolveSlackConfig returns the SlackConfig or the view of the ClusterSlackConfig
+// a rule references. A SlackConfig in another namespace is only returned if a
+// SlackConfigGrant allows it.
+func resolveSlackConfig(ctx context.Context, c client.Reader, clusterNamespace string, rule notificationv1alpha1.SlackNotificationRule) (*notificationv1alpha1.SlackConfig, error) {
+	name := rule.Spec.SlackConfigRef.Name
+	if configRefKind(rule) == notificationv1alpha1.KindClusterSlackConfig {
+		if clusterNamespace == "" {
+			return nil, errNoClusterResourceNamespace
+		}
+		var config notificationv1alpha1.ClusterSlackConfig
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, &config); err != nil {
+			return nil, fmt.Errorf("failed to get ClusterSlackConfig: %w", err)
+		}
+		view := clusterConfigView(&config, clusterNamespace)
+		return &view, nil
+	}
+
+	namespace := slackConfigNamespace(rule)
+	if namespace != rule.Namespace {
+		if err := checkReferenceGrant(ctx, c, rule.Namespace, namespace, name); err != nil {
+			return nil, err
+		}
+	}
+	var config notificationv1alpha1.SlackConfig
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &config); err != nil {
+		return nil, fmt.Errorf("failed to get SlackConfig: %w", err)
+	}
+	return &config, nil
+}
+
+// effectiveRules returns the SlackNotificationRules of a namespace followed by
+// the views of the ClusterSlackNotificationRules whose namespace selector
+// matches it.
+func effectiveRules(ctx context.Context, c client.Reader, namespace string) ([]notificationv1alpha1.SlackNotificationRule, error) {
+	var rules notificationv1alpha1.SlackNotificationRuleList
+	if err := c.List(ctx, &rules, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	var clusterRules notificationv1alpha1.ClusterSlackNotificationRuleList
+	if err := c.List(ctx, &clusterRules); err != nil {
+		return nil, fmt.Errorf("failed to list cluster rules: %w", err)
+	}
+	if len(clusterRules.Items) == 0 {
+		return rules.Items, nil
+	}
+
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, &ns); apierrors.IsNotFound(err) {
+		return rules.Items, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	result := rules.Items
+	for i := range clusterRules.Items {
+		rule := &clusterRules.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(&rule.Spec.NamespaceSelector)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Invalid namespace selector", "clusterRule", rule.Name)
+			continue
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			result = append(result, clusterRuleView(rule))
+		}
+	}
+	return result, nil
+}
+
+// listSlackConfigs returns all SlackConfigs followed by the views of all ClusterSlackConfigs.
+func (n *Notifier) listSlackConfigs(ctx context.Context) ([]notificationv1alpha1.SlackConfig, error) {
+	var configs notificationv1alpha1.SlackConfigList
+	if err := n.Client.List(ctx, &configs); err != nil {
+		return nil, fmt.Errorf("failed to list SlackConfigs: %w", err)
+	}
+	if n.ClusterResourceNamespace == "" {
+		return configs.Items, nil
+	}
+	var clusterConfigs notificationv1alpha1.ClusterSlackConfigList
+	if err := n.Client.List(ctx, &clusterConfigs); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterSlackConfigs: %w", err)
+	}
+	result := configs.Items
+	for i := range clusterConfigs.Items {
+		result = append(result, clusterConfigView(&clusterConfigs.Items[i], n.ClusterResourceNamespace))
+	}
+	return result, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// ReasonNoClusterResourceNamespace is the reason a ClusterSlackConfig is not
+// Ready when the controller does not know where to read its Secrets from.
+const ReasonNoClusterResourceNamespace = "ClusterResourceNamespaceNotSet"
+
+// ClusterSlackConfigReconciler reconciles a ClusterSlackConfig object
+type ClusterSlackConfigReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	SlackClient slack.Client
+	// ClusterResourceNamespace is the namespace the Secrets referenced by
+	// ClusterSlackConfigs are read from.
+	ClusterResourceNamespace string
+	// RecheckInterval is how often a ClusterSlackConfig is verified again.
+	// Defaults to 10 minutes.
+	RecheckInterval time.Duration
+	// CredentialResolver reads the credentials of ClusterSlackConfigs.
+	// Defaults to reading Secrets, without caching.
+	CredentialResolver *credentials.Resolver
+	// APIReader reads the Secrets of rotating tokens from the API server.
+	// Defaults to the client.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslackconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslackconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslackconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+
+// Reconcile verifies a ClusterSlackConfig like a SlackConfig, reading the
+// Secrets it references from the cluster resource namespace.
+func (r *ClusterSlackConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var config notificationv1alpha1.ClusterSlackConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := config.Status.DeepCopy()
+
+	var message string
+	var err error
+	if r.ClusterResourceNamespace == "" {
+		err = invalidConfig(ReasonNoClusterResourceNamespace, "%s", errNoClusterResourceNamespace.Error())
+	} else {
+		view := clusterConfigView(&config, r.ClusterResourceNamespace)
+		message, err = r.verifier().verify(ctx, &view)
+		config.Status.TokenExpirationTime = view.Status.TokenExpirationTime
+		config.Status.Channel, config.Status.ChannelID = view.Status.Channel, view.Status.ChannelID
+		config.Status.TeamID = view.Status.TeamID
+	}
+	err = setVerifyConditions(ctx, &config.Status.Conditions, config.Generation, message, err)
+
+	if !equality.Semantic.DeepEqual(status, &config.Status) {
+		if updateErr := r.Status().Update(ctx, &config); updateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update ClusterSlackConfig status: %w", updateErr)
+		}
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.verifier().requeueAfter(&config.Status)}, nil
+}
+
+// verifier returns a SlackConfigReconciler to verify views of ClusterSlackConfigs with.
+func (r *ClusterSlackConfigReconciler) verifier() *SlackConfigReconciler {
+	return &SlackConfigReconciler{
+		Client:                   r.Client,
+		SlackClient:              r.SlackClient,
+		RecheckInterval:          r.RecheckInterval,
+		CredentialResolver:       r.CredentialResolver,
+		ClusterResourceNamespace: r.ClusterResourceNamespace,
+		APIReader:                r.APIReader,
+	}
+}
+
+// configsForSecret maps a Secret in the cluster resource namespace to the ClusterSlackConfigs referencing it.
+func (r *ClusterSlackConfigReconciler) configsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	if r.ClusterResourceNamespace == "" || secret.GetNamespace() != r.ClusterResourceNamespace {
+		return nil
+	}
+	var configs notificationv1alpha1.ClusterSlackConfigList
+	if err := r.List(ctx, &configs, client.MatchingFields{slackConfigSecretIndex: secret.GetName()}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ClusterSlackConfigs referencing secret", "secret", secret.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(configs.Items))
+	for _, config := range configs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: config.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterSlackConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.SlackClient == nil {
+		r.SlackClient = slack.NewClient()
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &notificationv1alpha1.ClusterSlackConfig{}, slackConfigSecretIndex, slackConfigSecretNames); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&notificationv1alpha1.ClusterSlackConfig{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.configsForSecret), builder.OnlyMetadata).
+		Named("clusterslackconfig").
+		Complete(r)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("ClusterSlackConfig Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-cluster-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{Name: resourceName}
+		clusterslackconfig := &notificationv1alpha1.ClusterSlackConfig{}
+
+		BeforeEach(func() {
+			By("creating the webhook Secret referenced by the ClusterSlackConfig")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Data:       map[string][]byte{"url": []byte("https://hooks.slack.com/services/T000/B000/XXXX")},
+			}
+			if err := k8sClient.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the custom resource for the Kind ClusterSlackConfig")
+			err := k8sClient.Get(ctx, typeNamespacedName, clusterslackconfig)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &notificationv1alpha1.ClusterSlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: resourceName},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType: "Webhook",
+						WebhookURLSecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: resourceName},
+							Key:                  "url",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &notificationv1alpha1.ClusterSlackConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance ClusterSlackConfig")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &ClusterSlackConfigReconciler{
+				Client:                   k8sClient,
+				Scheme:                   k8sClient.Scheme(),
+				SlackClient:              &fakeSlackClient{},
+				ClusterResourceNamespace: "default",
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &notificationv1alpha1.ClusterSlackConfig{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+		})
+	})
+
+	Context("When verifying a ClusterSlackConfig", func() {
+		const namespace = "slack-system"
+
+		var (
+			ctx    context.Context
+			config *notificationv1alpha1.ClusterSlackConfig
+			secret *corev1.Secret
+			c      client.Client
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			config = &notificationv1alpha1.ClusterSlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform", Generation: 2},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType: "Token",
+					Channel:  "#platform",
+					TokenSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "slack"},
+						Key:                  "token",
+					},
+				},
+			}
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Data:       map[string][]byte{"token": []byte("xoxb-platform")},
+			}
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, secret).
+				WithStatusSubresource(&notificationv1alpha1.ClusterSlackConfig{}).
+				WithIndex(&notificationv1alpha1.ClusterSlackConfig{}, slackConfigSecretIndex, slackConfigSecretNames).
+				Build()
+		})
+
+		reconcileConfig := func(clusterNamespace string) *metav1.Condition {
+			r := &ClusterSlackConfigReconciler{
+				Client:                   c,
+				Scheme:                   c.Scheme(),
+				ClusterResourceNamespace: clusterNamespace,
+				SlackClient:              &fakeSlackClient{channels: map[string]*goslack.Channel{"#platform": {IsMember: true}}},
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.ClusterSlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			cond := meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.ObservedGeneration).To(Equal(int64(2)))
+			return cond
+		}
+
+		It("reads its Secrets from the cluster resource namespace", func() {
+			cond := reconcileConfig(namespace)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(ContainSubstring("#platform"))
+		})
+
+		It("does not find Secrets in other namespaces", func() {
+			cond := reconcileConfig("default")
+			Expect(cond.Reason).To(Equal(ReasonSecretNotFound))
+		})
+
+		It("is not ready without a cluster resource namespace", func() {
+			cond := reconcileConfig("")
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(ReasonNoClusterResourceNamespace))
+		})
+
+		It("reconciles the ClusterSlackConfigs referencing a changed Secret in the cluster resource namespace", func() {
+			r := &ClusterSlackConfigReconciler{Client: c, ClusterResourceNamespace: namespace}
+			Expect(r.configsForSecret(ctx, secret)).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: "platform"}}))
+
+			elsewhere := secret.DeepCopy()
+			elsewhere.Namespace = "team-a"
+			Expect(r.configsForSecret(ctx, elsewhere)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// ClusterSlackNotificationRuleReconciler reconciles a ClusterSlackNotificationRule object
+type ClusterSlackNotificationRuleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslacknotificationrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslacknotificationrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslacknotificationrules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslackconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=cronworkflows,verbs=get;list;watch
+
+// Reconcile validates a ClusterSlackNotificationRule, checks that its
+// ClusterSlackConfig is Ready and reports the result with the Ready condition,
+// along with the CronJobs or CronWorkflows it matches in the selected namespaces.
+func (r *ClusterSlackNotificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	var rule notificationv1alpha1.ClusterSlackNotificationRule
+	if err := r.Get(ctx, req.NamespacedName, &rule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := rule.Status.DeepCopy()
+
+	reason, message, err := r.check(ctx, &rule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if reason != ReasonRuleReady {
+		logger.Info("ClusterSlackNotificationRule is not ready", "reason", reason, "message", message)
+	}
+	setRuleReadyCondition(&rule.Status, rule.Generation, reason, message)
+
+	if !equality.Semantic.DeepEqual(status, &rule.Status) {
+		if err := r.Status().Update(ctx, &rule); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update ClusterSlackNotificationRule status: %w", err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// check validates the rule and records the targets it matches in its status.
+// It returns the reason and message of the Ready condition.
+func (r *ClusterSlackNotificationRuleReconciler) check(ctx context.Context, rule *notificationv1alpha1.ClusterSlackNotificationRule) (string, string, error) {
+	if errs := validation.ValidateClusterRuleSpec(&rule.Spec, field.NewPath("spec")); len(errs) > 0 {
+		rule.Status.MatchedTargets = nil
+		rule.Status.MatchedTargetCount = 0
+		return ReasonInvalidSpec, errs.ToAggregate().Error(), nil
+	}
+
+	targets, namespaces, err := r.matchedTargets(ctx, rule)
+	if err != nil {
+		return "", "", err
+	}
+	rule.Status.MatchedTargetCount = int32(len(targets))
+	rule.Status.MatchedTargets = targets[:min(len(targets), maxListedMatchedTargets)]
+
+	view := clusterRuleView(rule)
+	rule.Status.Destinations = pruneDestinationStatuses(view)
+	if reason, message, err := checkSlackConfigs(ctx, r.Client, &view); err != nil || reason != ReasonRuleReady {
+		return reason, message, err
+	}
+	return ReasonRuleReady, fmt.Sprintf("Matches %d %s(s) in %d namespace(s)", len(targets), rule.Spec.TargetResource, namespaces), nil
+}
+
+// matchedTargets returns the sorted namespace/name of the CronJobs or
+// CronWorkflows the rule selects, and the number of namespaces it selects.
+func (r *ClusterSlackNotificationRuleReconciler) matchedTargets(ctx context.Context, rule *notificationv1alpha1.ClusterSlackNotificationRule) ([]string, int, error) {
+	nsSelector, err := metav1.LabelSelectorAsSelector(&rule.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, 0, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&rule.Spec.LabelSelector)
+	if err != nil {
+		return nil, 0, err
+	}
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+		return nil, 0, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var names []string
+	for _, ns := range namespaces.Items {
+		targets, err := listTargets(ctx, r.Client, rule.Spec.TargetResource, client.InNamespace(ns.Name), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, target := range targets {
+			names = append(names, target.String())
+		}
+	}
+	slices.Sort(names)
+	return names, len(namespaces.Items), nil
+}
+
+// rulesForClusterConfig maps a ClusterSlackConfig to the cluster rules referencing it.
+func (r *ClusterSlackNotificationRuleReconciler) rulesForClusterConfig(ctx context.Context, config client.Object) []reconcile.Request {
+	key := configRefKey(notificationv1alpha1.KindClusterSlackConfig, "", config.GetName())
+	return r.allRules(ctx, client.MatchingFields{slackConfigRefIndex: key})
+}
+
+// rulesForObject maps a Namespace, CronJob or CronWorkflow to all cluster
+// rules, as a label change may add it to or remove it from any of them.
+func (r *ClusterSlackNotificationRuleReconciler) rulesForObject(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.allRules(ctx)
+}
+
+func (r *ClusterSlackNotificationRuleReconciler) allRules(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	var rules notificationv1alpha1.ClusterSlackNotificationRuleList
+	if err := r.List(ctx, &rules, opts...); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ClusterSlackNotificationRules")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(rules.Items))
+	for _, rule := range rules.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: rule.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterSlackNotificationRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &notificationv1alpha1.ClusterSlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&notificationv1alpha1.ClusterSlackNotificationRule{}).
+		Watches(&notificationv1alpha1.ClusterSlackConfig{}, handler.EnqueueRequestsFromMapFunc(r.rulesForClusterConfig)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.rulesForObject)).
+		Watches(&batchv1.CronJob{}, handler.EnqueueRequestsFromMapFunc(r.rulesForObject)).
+		Watches(&argov1alpha1.CronWorkflow{}, handler.EnqueueRequestsFromMapFunc(r.rulesForObject)).
+		Named("clusterslacknotificationrule").
+		Complete(r)
+}
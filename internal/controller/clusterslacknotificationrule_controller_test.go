@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("ClusterSlackNotificationRule Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-cluster-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{Name: resourceName}
+		clusterslacknotificationrule := &notificationv1alpha1.ClusterSlackNotificationRule{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind ClusterSlackNotificationRule")
+			err := k8sClient.Get(ctx, typeNamespacedName, clusterslacknotificationrule)
+			if err != nil && apierrors.IsNotFound(err) {
+				resource := &notificationv1alpha1.ClusterSlackNotificationRule{
+					ObjectMeta: metav1.ObjectMeta{Name: resourceName},
+					Spec: notificationv1alpha1.ClusterSlackNotificationRuleSpec{
+						SlackNotificationRuleSpec: notificationv1alpha1.SlackNotificationRuleSpec{
+							TargetResource: "CronJob",
+							SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "missing"},
+							Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed"}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &notificationv1alpha1.ClusterSlackNotificationRule{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance ClusterSlackNotificationRule")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &ClusterSlackNotificationRuleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &notificationv1alpha1.ClusterSlackNotificationRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			cond := meta.FindStatusCondition(resource.Status.Conditions, notificationv1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonConfigNotFound))
+			Expect(cond.Message).To(ContainSubstring("ClusterSlackConfig missing"))
+		})
+	})
+
+	Context("When applying a rule to the namespaces it selects", func() {
+		const controllerNamespace = "slack-system"
+
+		var (
+			ctx         context.Context
+			rule        *notificationv1alpha1.ClusterSlackNotificationRule
+			config      *notificationv1alpha1.ClusterSlackConfig
+			slackFk     *fakeSlackClient
+			c           client.Client
+			notifier    *Notifier
+			objects     []client.Object
+			prodCronJob *batchv1.CronJob
+			devCronJob  *batchv1.CronJob
+		)
+
+		namespace := func(name, env string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}}}
+		}
+		cronJob := func(namespace, name string) *batchv1.CronJob {
+			return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			slackFk = &fakeSlackClient{}
+			rule = &notificationv1alpha1.ClusterSlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-failures", Generation: 1},
+				Spec: notificationv1alpha1.ClusterSlackNotificationRuleSpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					SlackNotificationRuleSpec: notificationv1alpha1.SlackNotificationRuleSpec{
+						TargetResource: "CronJob",
+						SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "platform"},
+						Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.namespace }}/{{ .metadata.name }} failed"}},
+					},
+				},
+			}
+			config = &notificationv1alpha1.ClusterSlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:       "Token",
+					Channel:        "#platform",
+					TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+				},
+				Status: notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+					Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+				}}},
+			}
+			prodCronJob = cronJob("payments", "settle")
+			devCronJob = cronJob("sandbox", "settle")
+			objects = []client.Object{
+				rule, config,
+				namespace("payments", "prod"), namespace("billing", "prod"), namespace("sandbox", "dev"),
+				prodCronJob, cronJob("billing", "invoice"), devCronJob,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: controllerNamespace},
+					Data:       map[string][]byte{"token": []byte("xoxb-platform")},
+				},
+			}
+		})
+
+		build := func() {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(objects...).
+				WithStatusSubresource(&notificationv1alpha1.ClusterSlackNotificationRule{}, &notificationv1alpha1.SlackNotificationRule{}).
+				WithIndex(&notificationv1alpha1.ClusterSlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+				WithIndex(&notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+				Build()
+			notifier = &Notifier{Client: c, SlackClient: slackFk, ClusterResourceNamespace: controllerNamespace}
+		}
+
+		failed := func(cronJob *batchv1.CronJob) {
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: cronJob.Name + "-1", Namespace: cronJob.Namespace}}
+			_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("lists the CronJobs it matches across the selected namespaces", func() {
+			build()
+			r := &ClusterSlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.ClusterSlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(got.Status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(got.Status.MatchedTargets).To(Equal([]string{"billing/invoice", "payments/settle"}))
+			Expect(got.Status.MatchedTargetCount).To(Equal(int32(2)))
+
+			Expect(r.rulesForClusterConfig(ctx, config)).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod-failures"}}))
+		})
+
+		It("notifies failures in selected namespaces through the ClusterSlackConfig", func() {
+			build()
+			failed(prodCronJob)
+			failed(devCronJob)
+
+			Expect(slackFk.sent).To(HaveLen(1))
+			Expect(slackFk.sent[0].Token).To(Equal("xoxb-platform"))
+			Expect(slackFk.sent[0].Channel).To(Equal("#platform"))
+			Expect(slackFk.sent[0].Title).To(Equal("payments/settle-1 failed"))
+
+			var got notificationv1alpha1.ClusterSlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			Expect(got.Status.SentCount).To(Equal(int64(1)))
+		})
+
+		It("lets namespaced rules reference the ClusterSlackConfig", func() {
+			objects = append(objects, &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Namespace: "sandbox"},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "platform"},
+					Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "sandbox failure"}},
+				},
+			})
+			build()
+			failed(devCronJob)
+			Expect(slackFk.sent).To(ConsistOf(HaveField("Title", "sandbox failure")))
+
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.rulesForClusterConfig(ctx, config)).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "sandbox", Name: "sandbox"}}))
+		})
+
+		It("finds the cluster rule a notification button refers to", func() {
+			build()
+			ref := newNotificationRef(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "settle-1", Namespace: "payments"}}, prodCronJob, clusterRuleView(rule), rule.Spec.Notifications[0])
+			Expect(ref.ClusterRule).To(BeTrue())
+			Expect(ref.key()).To(Equal("cluster/prod-failures/Failed"))
+
+			got, gotConfig, err := notifier.getNotificationRule(ctx, ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got.Name).To(Equal("prod-failures"))
+			Expect(gotConfig.Namespace).To(Equal(controllerNamespace))
+		})
+
+		It("does not send through a ClusterSlackConfig without a cluster resource namespace", func() {
+			build()
+			notifier.ClusterResourceNamespace = ""
+			failed(prodCronJob)
+			Expect(slackFk.sent).To(BeEmpty())
+
+			var got notificationv1alpha1.ClusterSlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			Expect(got.Status.FailedCount).To(Equal(int64(1)))
+		})
+	})
+})
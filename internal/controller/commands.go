@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/robfig/cron/v3"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/interactivity"
+)
+
+// cronWorkflowLabel is set by Argo on Workflows created by a CronWorkflow.
+const cronWorkflowLabel = "workflows.argoproj.io/cron-workflow"
+
+// targetSummary describes the schedule and the latest run of a CronJob or CronWorkflow.
+type targetSummary struct {
+	Kind           string
+	Namespace      string
+	Name           string
+	Schedules      []string
+	Suspended      bool
+	Active         int
+	LastScheduled  *metav1.Time
+	LastSuccessful *metav1.Time
+	// LastRun and LastResult are the name and status of the latest Job or Workflow.
+	LastRun    string
+	LastResult string
+	NextRun    *time.Time
+	Rules      []string
+}
+
+// CommandScopes returns the namespaces whose SlackConfig allows the channel a
+// slash command was sent from, with the Slack App credentials of each SlackConfig.
+// Only SlackConfigs verified for the workspace of the command are considered,
+// so that unverified commands cannot make the controller read the Secrets of
+// any other. ClusterSlackConfigs do not belong to a namespace and grant no scope.
+func (n *Notifier) CommandScopes(ctx context.Context, cmd *goslack.SlashCommand) ([]interactivity.CommandScope, error) {
+	logger := log.FromContext(ctx)
+
+	var configs notificationv1alpha1.SlackConfigList
+	if err := n.Client.List(ctx, &configs); err != nil {
+		return nil, fmt.Errorf("failed to list SlackConfigs: %w", err)
+	}
+
+	var scopes []interactivity.CommandScope
+	for _, config := range configs.Items {
+		if config.Spec.Interactivity == nil || !slices.Contains(config.Spec.Interactivity.SlashCommandChannels, cmd.ChannelID) {
+			continue
+		}
+		if config.Status.TeamID == "" || config.Status.TeamID != cmd.TeamID {
+			continue
+		}
+		creds, err := n.appCredentials(ctx, &config)
+		if err != nil {
+			logger.Error(err, "Failed to get Slack App credentials", "slackConfig", client.ObjectKeyFromObject(&config))
+			continue
+		}
+		scopes = append(scopes, interactivity.CommandScope{Namespace: config.Namespace, AppCredentials: creds})
+	}
+	return scopes, nil
+}
+
+// HandleCommand answers the /k8s-cron slash command:
+//
+//	list [namespace]            lists CronJobs and CronWorkflows matched by rules
+//	status <namespace>/<name>   shows the details of one of them
+func (n *Notifier) HandleCommand(ctx context.Context, cmd *goslack.SlashCommand, namespaces []string) (*goslack.Msg, error) {
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 || args[0] == "help" {
+		return commandReply(commandUsage(cmd.Command, namespaces)), nil
+	}
+
+	switch {
+	case args[0] == "list" && len(args) <= 2:
+		queried := namespaces
+		if len(args) == 2 {
+			if !slices.Contains(namespaces, args[1]) {
+				return commandReply(fmt.Sprintf(":no_entry: This channel is not allowed to query namespace `%s`.", args[1])), nil
+			}
+			queried = []string{args[1]}
+		}
+		var summaries []targetSummary
+		for _, ns := range queried {
+			s, err := n.summarizeTargets(ctx, ns, "")
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, s...)
+		}
+		return commandReply(formatTargetList(summaries)), nil
+
+	case args[0] == "status" && len(args) == 2:
+		ns, name, ok := strings.Cut(args[1], "/")
+		if !ok || ns == "" || name == "" {
+			break
+		}
+		if !slices.Contains(namespaces, ns) {
+			return commandReply(fmt.Sprintf(":no_entry: This channel is not allowed to query namespace `%s`.", ns)), nil
+		}
+		summaries, err := n.summarizeTargets(ctx, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(summaries) == 0 {
+			return commandReply(fmt.Sprintf("No CronJob or CronWorkflow `%s/%s` is matched by a SlackNotificationRule.", ns, name)), nil
+		}
+		var details []string
+		for _, s := range summaries {
+			details = append(details, formatTargetStatus(s))
+		}
+		return commandReply(strings.Join(details, "\n\n")), nil
+	}
+	return commandReply(fmt.Sprintf("Unknown command `%s`.\n%s", cmd.Text, commandUsage(cmd.Command, namespaces))), nil
+}
+
+func commandReply(text string) *goslack.Msg {
+	return &goslack.Msg{ResponseType: goslack.ResponseTypeEphemeral, Text: text}
+}
+
+func commandUsage(command string, namespaces []string) string {
+	return fmt.Sprintf("Usage:\n"+
+		"• `%[1]s list [namespace]` lists the CronJobs and CronWorkflows matched by notification rules\n"+
+		"• `%[1]s status <namespace>/<name>` shows the details of one of them\n"+
+		"This channel may query: %[2]s", command, "`"+strings.Join(namespaces, "`, `")+"`")
+}
+
+// summarizeTargets summarizes the CronJobs and CronWorkflows in a namespace
+// matched by at least one rule, including cluster rules selecting the namespace.
+// If name is set, only targets with that name are summarized.
+func (n *Notifier) summarizeTargets(ctx context.Context, namespace, name string) ([]targetSummary, error) {
+	rules, err := effectiveRules(ctx, n.Client, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var cronJobRules, cronWorkflowRules bool
+	for _, rule := range rules {
+		cronJobRules = cronJobRules || rule.Spec.TargetResource == "CronJob"
+		cronWorkflowRules = cronWorkflowRules || rule.Spec.TargetResource == "CronWorkflow"
+	}
+
+	var summaries []targetSummary
+	if cronJobRules {
+		s, err := n.summarizeCronJobs(ctx, namespace, name, rules)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s...)
+	}
+	if cronWorkflowRules {
+		s, err := n.summarizeCronWorkflows(ctx, namespace, name, rules)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s...)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		if summaries[i].Kind != summaries[j].Kind {
+			return summaries[i].Kind < summaries[j].Kind
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+func (n *Notifier) summarizeCronJobs(ctx context.Context, namespace, name string, rules []notificationv1alpha1.SlackNotificationRule) ([]targetSummary, error) {
+	var cronJobs batchv1.CronJobList
+	if err := n.Client.List(ctx, &cronJobs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list CronJobs: %w", err)
+	}
+	var jobs batchv1.JobList
+	if err := n.Client.List(ctx, &jobs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	var summaries []targetSummary
+	for i := range cronJobs.Items {
+		cronJob := &cronJobs.Items[i]
+		if name != "" && cronJob.Name != name {
+			continue
+		}
+		matched := matchingRuleNames(ctx, rules, cronJob)
+		if len(matched) == 0 {
+			continue
+		}
+
+		schedule := cronJob.Spec.Schedule
+		if cronJob.Spec.TimeZone != nil {
+			schedule = "CRON_TZ=" + *cronJob.Spec.TimeZone + " " + schedule
+		}
+		s := targetSummary{
+			Kind:           "CronJob",
+			Namespace:      cronJob.Namespace,
+			Name:           cronJob.Name,
+			Schedules:      []string{schedule},
+			Suspended:      cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+			Active:         len(cronJob.Status.Active),
+			LastScheduled:  cronJob.Status.LastScheduleTime,
+			LastSuccessful: cronJob.Status.LastSuccessfulTime,
+			Rules:          matched,
+		}
+
+		var latest *batchv1.Job
+		for j := range jobs.Items {
+			job := &jobs.Items[j]
+			if metav1.IsControlledBy(job, cronJob) && (latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp)) {
+				latest = job
+			}
+		}
+		if latest != nil {
+			s.LastRun = latest.Name
+			s.LastResult = jobStatus(latest)
+		}
+		if !s.Suspended {
+			s.NextRun = n.nextRun(ctx, s.Schedules)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
+
+func (n *Notifier) summarizeCronWorkflows(ctx context.Context, namespace, name string, rules []notificationv1alpha1.SlackNotificationRule) ([]targetSummary, error) {
+	var cronWfs argov1alpha1.CronWorkflowList
+	if err := n.Client.List(ctx, &cronWfs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list CronWorkflows: %w", err)
+	}
+	var wfs argov1alpha1.WorkflowList
+	if err := n.Client.List(ctx, &wfs, client.InNamespace(namespace), client.HasLabels{cronWorkflowLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list Workflows: %w", err)
+	}
+
+	var summaries []targetSummary
+	for i := range cronWfs.Items {
+		cronWf := &cronWfs.Items[i]
+		if name != "" && cronWf.Name != name {
+			continue
+		}
+		matched := matchingRuleNames(ctx, rules, cronWf)
+		if len(matched) == 0 {
+			continue
+		}
+
+		s := targetSummary{
+			Kind:          "CronWorkflow",
+			Namespace:     cronWf.Namespace,
+			Name:          cronWf.Name,
+			Schedules:     cronWf.Spec.GetSchedulesWithTimezone(ctx),
+			Suspended:     cronWf.Spec.Suspend,
+			Active:        len(cronWf.Status.Active),
+			LastScheduled: cronWf.Status.LastScheduledTime,
+			Rules:         matched,
+		}
+
+		var latest *argov1alpha1.Workflow
+		for j := range wfs.Items {
+			wf := &wfs.Items[j]
+			if wf.Labels[cronWorkflowLabel] == cronWf.Name && (latest == nil || latest.CreationTimestamp.Before(&wf.CreationTimestamp)) {
+				latest = wf
+			}
+		}
+		if latest != nil {
+			s.LastRun = latest.Name
+			s.LastResult = string(latest.Status.Phase)
+			if s.LastResult == "" {
+				s.LastResult = string(argov1alpha1.WorkflowPending)
+			}
+		}
+		if !s.Suspended {
+			s.NextRun = n.nextRun(ctx, s.Schedules)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
+
+// matchingRuleNames returns the names of the rules that target obj.
+func matchingRuleNames(ctx context.Context, rules []notificationv1alpha1.SlackNotificationRule, obj client.Object) []string {
+	var names []string
+	for _, rule := range rules {
+		matched, err := ruleMatches(rule, obj)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Invalid label selector", "rule", rule.Name)
+			continue
+		}
+		if matched {
+			names = append(names, rule.Name)
+		}
+	}
+	return names
+}
+
+// nextRun returns the earliest next run of the cron schedules, which may
+// carry a CRON_TZ prefix. Invalid schedules are ignored.
+func (n *Notifier) nextRun(ctx context.Context, schedules []string) *time.Time {
+	now := n.now()
+	var next *time.Time
+	for _, spec := range schedules {
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			log.FromContext(ctx).Info("Ignoring invalid schedule", "schedule", spec, "error", err.Error())
+			continue
+		}
+		t := schedule.Next(now)
+		if next == nil || t.Before(*next) {
+			next = &t
+		}
+	}
+	return next
+}
+
+func formatTargetList(summaries []targetSummary) string {
+	if len(summaries) == 0 {
+		return "No CronJobs or CronWorkflows are matched by a SlackNotificationRule."
+	}
+	var b strings.Builder
+	for _, s := range summaries {
+		fmt.Fprintf(&b, "• %s `%s/%s` last scheduled %s, last result %s, next run %s\n",
+			s.Kind, s.Namespace, s.Name, slackTime(s.LastScheduled), lastResult(s), nextRunText(s))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatTargetStatus(s targetSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s `%s/%s`*\n", s.Kind, s.Namespace, s.Name)
+	fmt.Fprintf(&b, "Schedule: `%s`\n", strings.Join(s.Schedules, "`, `"))
+	fmt.Fprintf(&b, "Suspended: %t\n", s.Suspended)
+	fmt.Fprintf(&b, "Active runs: %d\n", s.Active)
+	fmt.Fprintf(&b, "Last scheduled: %s\n", slackTime(s.LastScheduled))
+	if s.Kind == "CronJob" {
+		fmt.Fprintf(&b, "Last successful: %s\n", slackTime(s.LastSuccessful))
+	}
+	fmt.Fprintf(&b, "Last result: %s\n", lastResult(s))
+	fmt.Fprintf(&b, "Next run: %s\n", nextRunText(s))
+	fmt.Fprintf(&b, "Rules: %s", strings.Join(s.Rules, ", "))
+	return b.String()
+}
+
+func lastResult(s targetSummary) string {
+	if s.LastRun == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("*%s* (`%s`)", s.LastResult, s.LastRun)
+}
+
+func nextRunText(s targetSummary) string {
+	if s.Suspended {
+		return "suspended"
+	}
+	if s.NextRun == nil {
+		return "unknown"
+	}
+	return slackTime(&metav1.Time{Time: *s.NextRun})
+}
+
+// slackTime formats t so that Slack shows it in the time zone of the reader.
+func slackTime(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", t.Unix(), t.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("Slash commands", func() {
+	const namespace = "default"
+
+	var (
+		ctx      context.Context
+		notifier *Notifier
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, UID: "backup-uid", Labels: map[string]string{"team": "a"}},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", TimeZone: ptr.To("Asia/Tokyo")},
+			Status:     batchv1.CronJobStatus{LastScheduleTime: &metav1.Time{Time: now.Add(-30 * time.Minute)}},
+		}
+		unmatched := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: namespace, Labels: map[string]string{"team": "b"}},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 0 * * *"},
+		}
+		ownedJob := func(name string, created time.Time, status batchv1.JobStatus) *batchv1.Job {
+			return &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created),
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+				},
+				Status: status,
+			}
+		}
+		cronWf := &argov1alpha1.CronWorkflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "etl", Namespace: namespace},
+			Spec:       argov1alpha1.CronWorkflowSpec{Schedules: []string{"0 12 * * *", "45 10 * * *"}, Suspend: false},
+		}
+		wf := &argov1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "etl-1", Namespace: namespace, Labels: map[string]string{cronWorkflowLabel: "etl"}},
+			Status:     argov1alpha1.WorkflowStatus{Phase: argov1alpha1.WorkflowSucceeded},
+		}
+		rule := func(name, target string, selector map[string]string) *notificationv1alpha1.SlackNotificationRule {
+			return &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: target,
+					LabelSelector:  metav1.LabelSelector{MatchLabels: selector},
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				},
+			}
+		}
+		config := &notificationv1alpha1.SlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackConfigSpec{
+				AuthType: "Token",
+				Interactivity: &notificationv1alpha1.SlackInteractivity{
+					SigningSecretRef:     &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "signing-secret"},
+					SlashCommandChannels: []string{"C123"},
+				},
+			},
+			Status: notificationv1alpha1.SlackConfigStatus{TeamID: "T0EXAMPLE"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Data:       map[string][]byte{"signing-secret": []byte("s3cr3t")},
+		}
+
+		notifier = &Notifier{
+			Client: fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(
+				cronJob, unmatched, cronWf, wf, config, secret,
+				ownedJob("backup-1", now.Add(-90*time.Minute), batchv1.JobStatus{Succeeded: 1}),
+				ownedJob("backup-2", now.Add(-30*time.Minute), batchv1.JobStatus{Failed: 1}),
+				rule("team-a", "CronJob", map[string]string{"team": "a"}),
+				rule("workflows", "CronWorkflow", nil),
+			).Build(),
+			Clock: clocktesting.NewFakeClock(now),
+		}
+	})
+
+	command := func(text string, namespaces ...string) string {
+		msg, err := notifier.HandleCommand(ctx, &goslack.SlashCommand{Command: "/k8s-cron", Text: text}, namespaces)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg.ResponseType).To(Equal(goslack.ResponseTypeEphemeral))
+		return msg.Text
+	}
+
+	It("authorizes channels listed by a SlackConfig", func() {
+		scopes, err := notifier.CommandScopes(ctx, &goslack.SlashCommand{TeamID: "T0EXAMPLE", ChannelID: "C123"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).To(ConsistOf(HaveField("Namespace", namespace)))
+		Expect(scopes[0].SigningSecret).To(Equal("s3cr3t"))
+
+		scopes, err = notifier.CommandScopes(ctx, &goslack.SlashCommand{TeamID: "T0EXAMPLE", ChannelID: "C999"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).To(BeEmpty())
+	})
+
+	It("reads no Secrets for commands from other workspaces", func() {
+		reads := 0
+		notifier.Client = interceptor.NewClient(notifier.Client.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					reads++
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		})
+		for _, teamID := range []string{"T0OTHER", ""} {
+			scopes, err := notifier.CommandScopes(ctx, &goslack.SlashCommand{TeamID: teamID, ChannelID: "C123"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scopes).To(BeEmpty())
+		}
+		Expect(reads).To(BeZero())
+	})
+
+	It("lists targets matched by rules with their latest run and next run", func() {
+		text := command("list", namespace)
+		Expect(text).To(ContainSubstring("CronJob `default/backup`"))
+		Expect(text).To(ContainSubstring("*Failed* (`backup-2`)"))
+		// The next run is at the top of the hour in Asia/Tokyo.
+		Expect(text).To(ContainSubstring("2025-01-15T11:00:00Z"))
+		Expect(text).To(ContainSubstring("CronWorkflow `default/etl`"))
+		Expect(text).To(ContainSubstring("*Succeeded* (`etl-1`)"))
+		Expect(text).To(ContainSubstring("2025-01-15T10:45:00Z"))
+		Expect(text).NotTo(ContainSubstring("cleanup"))
+	})
+
+	It("shows the details of a target", func() {
+		text := command("status default/backup", namespace)
+		Expect(text).To(ContainSubstring("Schedule: `CRON_TZ=Asia/Tokyo 0 * * * *`"))
+		Expect(text).To(ContainSubstring("Last successful: never"))
+		Expect(text).To(ContainSubstring("Rules: team-a"))
+	})
+
+	It("refuses namespaces the channel may not query", func() {
+		Expect(command("list kube-system", namespace)).To(ContainSubstring("not allowed"))
+		Expect(command("status kube-system/backup", namespace)).To(ContainSubstring("not allowed"))
+	})
+
+	It("explains its usage", func() {
+		Expect(command("", namespace)).To(ContainSubstring("/k8s-cron status <namespace>/<name>"))
+		Expect(command("restart everything", namespace)).To(HavePrefix("Unknown command"))
+	})
+})
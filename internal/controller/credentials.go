@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+)
+
+// errCredentialProviderNotAllowed is returned for a SlackConfig outside of the
+// cluster resource namespace reading its credentials from the controller pod.
+var errCredentialProviderNotAllowed = errors.New("credential provider not allowed")
+
+// credentialProvider returns the provider the credentials of config are read
+// with. File and Env read from the controller pod, which tenants must not
+// reach, so only configs in clusterNamespace, including the views of
+// ClusterSlackConfigs, may use them.
+func credentialProvider(config *notificationv1alpha1.SlackConfig, clusterNamespace string) (string, error) {
+	provider := config.Spec.CredentialProvider
+	if provider == "" || provider == credentials.ProviderSecret {
+		return credentials.ProviderSecret, nil
+	}
+	if clusterNamespace == "" || config.Namespace != clusterNamespace {
+		return "", fmt.Errorf("%w: credential provider %s is only available to ClusterSlackConfigs and SlackConfigs in the namespace of the controller", errCredentialProviderNotAllowed, provider)
+	}
+	return provider, nil
+}
+
+// credentialResolver returns r, or a Resolver reading Secrets with c without caching if r is nil.
+func credentialResolver(r *credentials.Resolver, c client.Reader) *credentials.Resolver {
+	if r != nil {
+		return r
+	}
+	return &credentials.Resolver{Providers: map[string]credentials.Provider{
+		credentials.ProviderSecret: &credentials.SecretProvider{Client: c},
+	}}
+}
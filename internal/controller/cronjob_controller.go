@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
 )
 
 // CronJobReconciler reconciles a Job object owned by a CronJob
@@ -20,13 +21,15 @@ type CronJobReconciler struct {
 	Notifier *Notifier
 }
 
-// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
-// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slacknotificationrules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
-func (r *CronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileJob", tracing.TriggerKey.String(req.String()))
+	defer func() { tracing.End(span, err) }()
 	logger := log.FromContext(ctx)
 
 	var job batchv1.Job
@@ -49,23 +52,8 @@ func (r *CronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// Determine Status
-	status := "Running"
-	if job.Status.Succeeded > 0 {
-		status = "Succeeded"
-	} else if job.Status.Failed > 0 {
-		status = "Failed"
-	}
-	// TODO: Handle more complex status or strictly look for conditions.
-	// Simple check: active > 0 => Running?
-	// But completed jobs stay.
-	// We need to notify triggered by state change.
-	// Since we are watching Job, we get called on update.
-	// We should check if we already sent notification for this terminal state.
-
-	// Check if already sent
-	// NOTE: Ignoring annotation check for MVP to ensure notification logic works,
-	// but strictly should check if annotation contains current status.
-	// For "Running", it might spam. "Succeeded"/"Failed" is terminal.
+	status := jobStatus(&job)
+	// Notifications already sent for status are skipped by the Notifier, see AnnotationSentStatuses.
 
 	// Fetch Owner CronJob to pass as Target
 	var cronJob batchv1.CronJob
@@ -76,18 +64,33 @@ func (r *CronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	if err := r.Notifier.Notify(ctx, &job, &cronJob, status); err != nil {
+	requeueAfter, err := r.Notifier.Notify(ctx, &job, &cronJob, status)
+	if err != nil {
 		logger.Error(err, "Failed to notify")
 		// Don't error out the reconciliation to avoid retry loops for notification failures unless critical
 	}
 
-	return ctrl.Result{}, nil
+	// Notifications held back by quiet hours, awaiting escalation or that failed are handled on a later reconcile.
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// jobStatus returns the notification status of a Job: Running, Succeeded or Failed.
+func jobStatus(job *batchv1.Job) string {
+	if job.Status.Succeeded > 0 {
+		return "Succeeded"
+	} else if job.Status.Failed > 0 {
+		return "Failed"
+	}
+	return "Running"
 }
 
 func (r *CronJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.Notifier = &Notifier{
-		Client:      mgr.GetClient(), // Changed from r.Client to mgr.GetClient()
-		SlackClient: slack.NewClient(),
+	if r.Notifier == nil {
+		r.Notifier = &Notifier{
+			Client:      mgr.GetClient(),
+			SlackClient: slack.NewClient(),
+			Recorder:    mgr.GetEventRecorderFor("slack-notifier"),
+		}
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&batchv1.Job{}).
@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
 )
 
 // CronWorkflowReconciler reconciles a Workflow object owned by a CronWorkflow
@@ -20,10 +21,12 @@ type CronWorkflowReconciler struct {
 	Notifier *Notifier
 }
 
-// +kubebuilder:rbac:groups=argoproj.io,resources=workflows,verbs=get;list;watch
-// +kubebuilder:rbac:groups=argoproj.io,resources=cronworkflows,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=workflows,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=argoproj.io,resources=cronworkflows,verbs=get;list;watch;patch
 
-func (r *CronWorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CronWorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileWorkflow", tracing.TriggerKey.String(req.String()))
+	defer func() { tracing.End(span, err) }()
 	logger := log.FromContext(ctx)
 
 	var wf argov1alpha1.Workflow
@@ -57,17 +60,22 @@ func (r *CronWorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
-	if err := r.Notifier.Notify(ctx, &wf, &cronWf, status); err != nil {
+	requeueAfter, err := r.Notifier.Notify(ctx, &wf, &cronWf, status)
+	if err != nil {
 		logger.Error(err, "Failed to notify")
 	}
 
-	return ctrl.Result{}, nil
+	// Notifications held back by quiet hours, awaiting escalation or that failed are handled on a later reconcile.
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *CronWorkflowReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.Notifier = &Notifier{
-		Client:      mgr.GetClient(), // Initialize Notifier's Client with the manager's client
-		SlackClient: slack.NewClient(),
+	if r.Notifier == nil {
+		r.Notifier = &Notifier{
+			Client:      mgr.GetClient(),
+			SlackClient: slack.NewClient(),
+			Recorder:    mgr.GetEventRecorderFor("slack-notifier"),
+		}
 	}
 	// Note: You must register argov1alpha1 Scheme in main.go
 	return ctrl.NewControllerManagedBy(mgr).
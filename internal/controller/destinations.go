@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// kindSinkConfig is the kind rules are indexed by for the SinkConfigs of their destinations.
+const kindSinkConfig = "SinkConfig"
+
+// delivery is a notification of a rule as it is sent to one destination: the
+// rule references the configuration of the destination and the notification
+// has its channel, or sink names the SinkConfig it is delivered to instead.
+type delivery struct {
+	rule notificationv1alpha1.SlackNotificationRule
+	note notificationv1alpha1.NotificationRule
+	sink string
+	// destination identifies the destination in the status of the rule. It is
+	// nil for a notification without destinations.
+	destination *notificationv1alpha1.DestinationStatus
+}
+
+// deliveries returns the deliveries of a notification of rule, one for every
+// destination, or the notification itself if it has no destinations.
+func deliveries(rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) []delivery {
+	if len(note.Destinations) == 0 {
+		return []delivery{{rule: rule, note: note}}
+	}
+	result := make([]delivery, 0, len(note.Destinations))
+	for _, dest := range note.Destinations {
+		if dest.SinkRef != nil {
+			d := delivery{rule: rule, note: note, sink: dest.SinkRef.Name}
+			d.destination = &notificationv1alpha1.DestinationStatus{Sink: dest.SinkRef.Name}
+			result = append(result, d)
+			continue
+		}
+		view := destinationRuleView(rule, dest)
+		d := delivery{rule: view, note: note}
+		d.note.Channel = dest.Channel
+		d.destination = &notificationv1alpha1.DestinationStatus{SlackConfigRef: qualifiedConfigRef(view), Channel: dest.Channel}
+		result = append(result, d)
+	}
+	return result
+}
+
+// destinationRuleView returns rule referencing the configuration of dest.
+func destinationRuleView(rule notificationv1alpha1.SlackNotificationRule, dest notificationv1alpha1.NotificationDestination) notificationv1alpha1.SlackNotificationRule {
+	view := *rule.DeepCopy()
+	if dest.SlackConfigRef != nil {
+		view.Spec.SlackConfigRef = *dest.SlackConfigRef
+	}
+	return view
+}
+
+// configViews returns rule and a view of it for every other Slack
+// configuration the destinations of its notifications reference.
+func configViews(rule notificationv1alpha1.SlackNotificationRule) []notificationv1alpha1.SlackNotificationRule {
+	views := []notificationv1alpha1.SlackNotificationRule{rule}
+	seen := map[notificationv1alpha1.SlackConfigReference]bool{qualifiedConfigRef(rule): true}
+	for _, note := range rule.Spec.Notifications {
+		for _, dest := range note.Destinations {
+			if dest.SinkRef != nil {
+				continue
+			}
+			view := destinationRuleView(rule, dest)
+			if ref := qualifiedConfigRef(view); !seen[ref] {
+				seen[ref] = true
+				views = append(views, view)
+			}
+		}
+	}
+	return views
+}
+
+// sinkNames returns the names of the SinkConfigs the destinations of the
+// notifications of rule reference.
+func sinkNames(rule notificationv1alpha1.SlackNotificationRule) []string {
+	var names []string
+	for _, note := range rule.Spec.Notifications {
+		for _, dest := range note.Destinations {
+			if dest.SinkRef != nil && !slices.Contains(names, dest.SinkRef.Name) {
+				names = append(names, dest.SinkRef.Name)
+			}
+		}
+	}
+	return names
+}
+
+// qualifiedConfigRef returns the configuration reference of rule with its kind
+// and, for a SlackConfig, its namespace.
+func qualifiedConfigRef(rule notificationv1alpha1.SlackNotificationRule) notificationv1alpha1.SlackConfigReference {
+	ref := notificationv1alpha1.SlackConfigReference{Kind: configRefKind(rule), Name: rule.Spec.SlackConfigRef.Name}
+	if ref.Kind == notificationv1alpha1.KindSlackConfig {
+		ref.Namespace = slackConfigNamespace(rule)
+	}
+	return ref
+}
+
+// hasDestination reports whether the notifications of rule for status are
+// sent through the configuration ref references, which may be the one of the rule.
+func hasDestination(rule notificationv1alpha1.SlackNotificationRule, status string, ref notificationv1alpha1.SlackConfigReference) bool {
+	for _, note := range rule.Spec.Notifications {
+		if !strings.EqualFold(note.Status, status) {
+			continue
+		}
+		for _, d := range deliveries(rule, note) {
+			if d.sink == "" && d.rule.Spec.SlackConfigRef == ref {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordDestination counts a notification sent to dest in status, or one that
+// failed to be sent if sendErr is set.
+func recordDestination(status *notificationv1alpha1.SlackNotificationRuleStatus, dest notificationv1alpha1.DestinationStatus, now metav1.Time, sendErr error) {
+	i := findDestinationStatus(status.Destinations, dest)
+	if i < 0 {
+		status.Destinations = append(status.Destinations, dest)
+		i = len(status.Destinations) - 1
+	}
+	entry := &status.Destinations[i]
+	if sendErr != nil {
+		entry.FailedCount++
+		entry.LastError = sendErr.Error()
+		return
+	}
+	entry.SentCount++
+	entry.LastNotificationTime = &now
+	entry.LastError = ""
+}
+
+// pruneDestinationStatuses returns the destination statuses of rule without
+// those of destinations that were removed from its notifications.
+func pruneDestinationStatuses(rule notificationv1alpha1.SlackNotificationRule) []notificationv1alpha1.DestinationStatus {
+	var current []notificationv1alpha1.DestinationStatus
+	for _, note := range rule.Spec.Notifications {
+		for _, d := range deliveries(rule, note) {
+			if d.destination != nil {
+				current = append(current, *d.destination)
+			}
+		}
+	}
+	var kept []notificationv1alpha1.DestinationStatus
+	for _, dest := range rule.Status.Destinations {
+		if findDestinationStatus(current, dest) >= 0 {
+			kept = append(kept, dest)
+		}
+	}
+	return kept
+}
+
+func findDestinationStatus(statuses []notificationv1alpha1.DestinationStatus, dest notificationv1alpha1.DestinationStatus) int {
+	for i, s := range statuses {
+		if s.SlackConfigRef == dest.SlackConfigRef && s.Channel == dest.Channel && s.Sink == dest.Sink {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+var _ = Describe("Dry run", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx      context.Context
+		logs     []string
+		c        client.Client
+		slackFk  *fakeSlackClient
+		recorder *record.FakeRecorder
+		notifier *Notifier
+		rule     *notificationv1alpha1.SlackNotificationRule
+		cronJob  *batchv1.CronJob
+	)
+
+	BeforeEach(func() {
+		logs = nil
+		ctx = log.IntoContext(context.Background(), funcr.New(func(_, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{}))
+		slackFk = &fakeSlackClient{}
+		recorder = record.NewFakeRecorder(10)
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace}}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed", Channel: "#team-a"}},
+			},
+		}
+	})
+
+	build := func(objects ...client.Object) {
+		c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(append(objects, rule,
+				&notificationv1alpha1.SlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType:       "Token",
+						TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Data:       map[string][]byte{"token": []byte("xoxb-test")},
+				},
+			)...).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		clock := clocktesting.NewFakePassiveClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+		notifier = &Notifier{Client: c, SlackClient: slackFk, Clock: clock, Recorder: recorder}
+	}
+
+	notify := func(name, status string) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID("uid-" + name)}}
+		_, err := notifier.Notify(ctx, job, cronJob, status)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	latest := func() notificationv1alpha1.SlackNotificationRule {
+		var got notificationv1alpha1.SlackNotificationRule
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+		return got
+	}
+
+	It("renders the notifications of a shadow rule without sending them", func() {
+		rule.Spec.DryRun = true
+		build()
+		notify("backup-1", "Failed")
+		Expect(slackFk.sent).To(BeEmpty())
+
+		got := latest()
+		Expect(got.Status.SentCount).To(BeZero())
+		Expect(got.Status.LastNotificationTime).To(BeNil())
+		Expect(got.Status.History).To(ConsistOf(And(
+			HaveField("Trigger", "backup-1"),
+			HaveField("Channel", "#team-a"),
+			HaveField("Title", "backup-1 failed"),
+			HaveField("Outcome", OutcomeDryRun),
+		)))
+		Expect(recorder.Events).To(HaveLen(2))
+		for range 2 {
+			Expect(recorder.Events).To(Receive(Equal(`Normal NotificationDryRun Failed notification to #team-a via SlackConfig team-a/slack not sent in dry run: "backup-1 failed"`)))
+		}
+		Expect(logs).To(ContainElement(And(
+			ContainSubstring("Dry run: notification not sent"),
+			ContainSubstring(`"title"="backup-1 failed"`),
+			ContainSubstring(`"channel"="#team-a"`),
+		)))
+		Expect(strings.Join(logs, "\n")).NotTo(ContainSubstring("xoxb-test"))
+
+		By("not rendering it again for the same run")
+		notify("backup-1", "Failed")
+		Expect(latest().Status.History).To(ConsistOf(HaveField("Attempts", int32(1))))
+
+		By("sending it once the rule leaves dry run")
+		got = latest()
+		got.Spec.DryRun = false
+		Expect(c.Update(ctx, &got)).To(Succeed())
+		notify("backup-1", "Failed")
+		Expect(slackFk.sent).To(HaveLen(1))
+		Expect(latest().Status.History).To(ConsistOf(And(
+			HaveField("Outcome", OutcomeDelivered),
+			HaveField("Attempts", int32(2)),
+		)))
+	})
+
+	It("fails notifications that do not render", func() {
+		rule.Spec.DryRun = true
+		rule.Spec.Notifications[0].Title = "{{ .metadata.name"
+		build()
+		notify("backup-1", "Failed")
+		got := latest()
+		Expect(got.Status.FailedCount).To(Equal(int64(1)))
+		Expect(got.Status.History).To(ConsistOf(HaveField("Outcome", OutcomeFailed)))
+	})
+
+	It("does not open or resolve incidents in a dry run of the manager", func() {
+		requests := make(chan struct{}, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests <- struct{}{}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		DeferCleanup(srv.Close)
+
+		rule.Spec.Notifications[0].Channel = ""
+		rule.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{{SinkRef: &corev1.LocalObjectReference{Name: "pagerduty"}}}
+		rule.Status.Incidents = []notificationv1alpha1.IncidentStatus{{Sink: "pagerduty", Target: "backup", Run: "backup-0", DedupKey: "slack-notifier/team-a/backups/0b5c"}}
+		build(&notificationv1alpha1.SinkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace},
+			Spec: notificationv1alpha1.SinkConfigSpec{
+				Type: sink.TypePagerDuty,
+				Incident: &notificationv1alpha1.IncidentSink{
+					KeySecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pagerduty"}, Key: "routing-key"},
+					APIURL:       srv.URL,
+				},
+			},
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace},
+			Data:       map[string][]byte{"routing-key": []byte("R0UT1NG")},
+		})
+		notifier.DryRun = true
+
+		notify("backup-1", "Failed")
+		notify("backup-2", "Succeeded")
+		Consistently(requests).ShouldNot(Receive())
+		got := latest()
+		Expect(got.Status.Incidents).To(ConsistOf(HaveField("Run", "backup-0")))
+		Expect(got.Status.History).To(ConsistOf(And(
+			HaveField("Sink", "pagerduty"),
+			HaveField("Outcome", OutcomeDryRun),
+		)))
+		Expect(logs).To(ContainElement(And(
+			ContainSubstring(`"sink"="pagerduty"`),
+			ContainSubstring(`"title"="backup-1 failed"`),
+		)))
+	})
+})
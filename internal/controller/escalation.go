@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	goslack "github.com/slack-go/slack"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+const (
+	// AnnotationEscalations holds the acknowledgement state of the notifications
+	// posted for a trigger object, keyed by "<rule>/<status>".
+	AnnotationEscalations = "notification.murasame29.com/escalations"
+)
+
+// escalationState is the acknowledgement state of a notification posted with
+// an Acknowledge button.
+type escalationState struct {
+	Channel        string       `json:"channel"`
+	TS             string       `json:"ts"`
+	PostedAt       metav1.Time  `json:"postedAt"`
+	Escalated      bool         `json:"escalated,omitempty"`
+	AcknowledgedBy string       `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *metav1.Time `json:"acknowledgedAt,omitempty"`
+}
+
+func readEscalationStates(obj client.Object) (map[string]escalationState, error) {
+	states := map[string]escalationState{}
+	raw, ok := obj.GetAnnotations()[AnnotationEscalations]
+	if !ok {
+		return states, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &states); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", AnnotationEscalations, err)
+	}
+	return states, nil
+}
+
+// updateEscalationState re-reads obj and applies mutate to the state stored
+// under key, retrying on conflicts with concurrent acknowledgements.
+func (n *Notifier) updateEscalationState(ctx context.Context, obj client.Object, key string, mutate func(state *escalationState, found bool) error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := n.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		states, err := readEscalationStates(obj)
+		if err != nil {
+			return err
+		}
+		state, found := states[key]
+		if err := mutate(&state, found); err != nil {
+			return err
+		}
+		states[key] = state
+
+		raw, err := json.Marshal(states)
+		if err != nil {
+			return err
+		}
+		patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[AnnotationEscalations] = string(raw)
+		obj.SetAnnotations(annotations)
+		return n.Client.Patch(ctx, obj, patch)
+	})
+}
+
+// sendWithEscalation posts the notification with an Acknowledge button once and
+// re-posts it to the escalation channel if it is not acknowledged in time. It
+// returns how long to wait before the escalation is due.
+func (n *Notifier) sendWithEscalation(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	dest, err := n.resolveDestination(ctx, rule, note)
+	if err != nil {
+		n.recordDecision(ctx, triggerObj, targetObj, rule, note, nil, postedMessage{}, err)
+		return 0, err
+	}
+	if !dest.interactive() {
+		// Nobody could acknowledge the notification, so send it as usual.
+		logger.Info("Escalation requires a SlackConfig with token authentication and interactivity; sending without it", "rule", rule.Name)
+		posted, err := n.resolveAndPost(ctx, triggerObj, targetObj, rule, note)
+		n.recordDecision(ctx, triggerObj, targetObj, rule, note, nil, posted, err)
+		return 0, err
+	}
+
+	ref := newNotificationRef(triggerObj, targetObj, rule, note)
+	value, err := ref.encode()
+	if err != nil {
+		return 0, err
+	}
+	buttons := notificationButtons(note, ref, value, true)
+
+	states, err := readEscalationStates(triggerObj)
+	if err != nil {
+		return 0, err
+	}
+	state, posted := states[ref.key()]
+	if posted && (state.AcknowledgedBy != "" || state.Escalated) {
+		return 0, nil
+	}
+
+	data, err := toTemplateData(triggerObj)
+	if err != nil {
+		return 0, err
+	}
+	fields := n.buildFields(triggerObj, targetObj, note.Status)
+	now := n.now()
+
+	if !posted {
+		if err := n.checkPolicy(ctx, rule, note.Status, dest.channel, messageTexts(note.Title, data, fields)...); err != nil {
+			n.recordDecision(ctx, triggerObj, targetObj, rule, note, nil, postedMessage{}, err)
+			return 0, err
+		}
+		channelID, ts, err := n.SlackClient.SendWithActions(ctx, dest.token, dest.channel, note.Title, statusColor(note.Status), fields, data, buttons)
+		n.recordDecision(ctx, triggerObj, targetObj, rule, note, nil, postedMessage{channelID: channelID, ts: ts}, err)
+		if err != nil {
+			return 0, err
+		}
+		err = n.updateEscalationState(ctx, triggerObj, ref.key(), func(state *escalationState, _ bool) error {
+			*state = escalationState{Channel: channelID, TS: ts, PostedAt: metav1.NewTime(now)}
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to record posted notification: %w", err)
+		}
+		return note.Escalation.After.Duration, nil
+	}
+
+	due := state.PostedAt.Add(note.Escalation.After.Duration)
+	if now.Before(due) {
+		return due.Sub(now), nil
+	}
+
+	channel := dest.channel
+	if note.Escalation.Channel != "" {
+		channel = note.Escalation.Channel
+	}
+	escalated := note
+	escalated.Channel = channel
+	escalated.Title = escalationTitle(note)
+	if err := n.checkPolicy(ctx, rule, note.Status, channel, messageTexts(escalated.Title, data, fields)...); err != nil {
+		n.recordDecision(ctx, triggerObj, targetObj, rule, escalated, nil, postedMessage{}, err)
+		// Give up on the escalation rather than retrying it on every reconcile.
+		if updateErr := n.updateEscalationState(ctx, triggerObj, ref.key(), func(state *escalationState, _ bool) error {
+			state.Escalated = true
+			return nil
+		}); updateErr != nil {
+			return 0, updateErr
+		}
+		return 0, err
+	}
+	var repost postedMessage
+	repost.channelID, repost.ts, err = n.SlackClient.SendWithActions(ctx, dest.token, channel, escalated.Title, "danger", fields, data, buttons)
+	n.recordDecision(ctx, triggerObj, targetObj, rule, escalated, nil, repost, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to escalate notification: %w", err)
+	}
+	logger.Info("Escalated unacknowledged notification", "rule", rule.Name, "status", note.Status, "channel", channel)
+	return 0, n.updateEscalationState(ctx, triggerObj, ref.key(), func(state *escalationState, _ bool) error {
+		state.Escalated = true
+		return nil
+	})
+}
+
+// escalationTitle prefixes the title template of the notification with the escalation notice.
+func escalationTitle(note notificationv1alpha1.NotificationRule) string {
+	prefix := fmt.Sprintf(":rotating_light: Not acknowledged within %s", note.Escalation.After.Duration)
+	if note.Escalation.MentionGroup != "" {
+		prefix = fmt.Sprintf("<!subteam^%s> %s", note.Escalation.MentionGroup, prefix)
+	}
+	if note.Title == "" {
+		return prefix
+	}
+	return prefix + ": " + note.Title
+}
+
+func (n *Notifier) acknowledge(ctx context.Context, callback *goslack.InteractionCallback, action *goslack.BlockAction) error {
+	logger := log.FromContext(ctx)
+
+	ref, err := decodeNotificationRef(action.Value)
+	if err != nil {
+		return err
+	}
+	rule, _, err := n.getNotificationRule(ctx, ref)
+	if err != nil {
+		return err
+	}
+	dest, err := n.resolveDestination(ctx, *rule, notificationv1alpha1.NotificationRule{Status: ref.Status})
+	if err != nil {
+		return err
+	}
+
+	obj, err := newTriggerObject(ref.Kind)
+	if err != nil {
+		return err
+	}
+	obj.SetNamespace(ref.Namespace)
+	obj.SetName(ref.Name)
+
+	userID := callback.User.ID
+	err = n.updateEscalationState(ctx, obj, ref.key(), func(state *escalationState, found bool) error {
+		if !found {
+			return fmt.Errorf("no notification %s found on %s %s/%s", ref.key(), ref.Kind, ref.Namespace, ref.Name)
+		}
+		if state.AcknowledgedBy == "" {
+			now := metav1.NewTime(n.now())
+			state.AcknowledgedBy = userID
+			state.AcknowledgedAt = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record acknowledgement: %w", err)
+	}
+	logger.Info("Notification acknowledged", "rule", ref.Rule, "status", ref.Status, "kind", ref.Kind, "name", ref.Name, "user", userID)
+
+	return n.SlackClient.Acknowledge(ctx, dest.token, callback.Container.ChannelID, callback.Container.MessageTs, callback.Message, userID)
+}
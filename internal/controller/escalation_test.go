@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+var _ = Describe("Escalation", func() {
+	const namespace = "default"
+
+	var (
+		ctx      context.Context
+		clock    *clocktesting.FakeClock
+		slackFk  *fakeSlackClient
+		notifier *Notifier
+		job      *batchv1.Job
+		cronJob  *batchv1.CronJob
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clock = clocktesting.NewFakeClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+		slackFk = &fakeSlackClient{}
+
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, Labels: map[string]string{"team": "a"}}}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace}}
+		config := &notificationv1alpha1.SlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackConfigSpec{
+				AuthType:       "Token",
+				TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+				Channel:        "#team",
+				Interactivity: &notificationv1alpha1.SlackInteractivity{
+					SigningSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "signing-secret"},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Data:       map[string][]byte{"token": []byte("xoxb-test"), "signing-secret": []byte("s3cr3t")},
+		}
+		rule := &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "failures", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				LabelSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications: []notificationv1alpha1.NotificationRule{{
+					Status: "Failed",
+					Title:  "{{ .metadata.name }} failed",
+					Escalation: &notificationv1alpha1.Escalation{
+						After:        metav1.Duration{Duration: 30 * time.Minute},
+						Channel:      "#oncall",
+						MentionGroup: "S0ONCALL",
+					},
+				}},
+			},
+		}
+
+		notifier = &Notifier{
+			Client:      fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(cronJob, job, config, secret, rule).Build(),
+			SlackClient: slackFk,
+			Clock:       clock,
+		}
+	})
+
+	notify := func() time.Duration {
+		Expect(notifier.Client.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+		requeueAfter, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+		return requeueAfter
+	}
+
+	It("posts once with an Acknowledge button and escalates when not acknowledged", func() {
+		Expect(notify()).To(Equal(30 * time.Minute))
+		Expect(slackFk.sent).To(HaveLen(1))
+		Expect(slackFk.sent[0].Channel).To(Equal("#team"))
+		Expect(slackFk.sent[0].Buttons).To(ContainElement(HaveField("ActionID", slack.ActionAcknowledge)))
+
+		By("not re-posting before the escalation is due")
+		clock.Step(10 * time.Minute)
+		Expect(notify()).To(Equal(20 * time.Minute))
+		Expect(slackFk.sent).To(HaveLen(1))
+
+		By("escalating once the timeout has passed")
+		clock.Step(20 * time.Minute)
+		Expect(notify()).To(BeZero())
+		Expect(slackFk.sent).To(HaveLen(2))
+		Expect(slackFk.sent[1].Channel).To(Equal("#oncall"))
+		Expect(slackFk.sent[1].Title).To(HavePrefix("<!subteam^S0ONCALL>"))
+		Expect(slackFk.sent[1].Title).To(HaveSuffix("backup-1 failed"))
+
+		By("not escalating twice")
+		Expect(notify()).To(BeZero())
+		Expect(slackFk.sent).To(HaveLen(2))
+	})
+
+	It("does not escalate acknowledged notifications", func() {
+		Expect(notify()).To(Equal(30 * time.Minute))
+
+		action := &goslack.BlockAction{ActionID: slack.ActionAcknowledge, Value: slackFk.sent[0].Buttons[0].Value}
+		creds, err := notifier.Credentials(ctx, action)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.SigningSecret).To(Equal("s3cr3t"))
+
+		callback := &goslack.InteractionCallback{
+			User:      goslack.User{ID: "U123"},
+			Container: goslack.Container{ChannelID: "C#team", MessageTs: "1700000000.000100"},
+		}
+		Expect(notifier.HandleAction(ctx, callback, action)).To(Succeed())
+		Expect(slackFk.acknowledged).To(ConsistOf("C#team/1700000000.000100/U123"))
+
+		clock.Step(time.Hour)
+		Expect(notify()).To(BeZero())
+		Expect(slackFk.sent).To(HaveLen(1))
+
+		states, err := readEscalationStates(job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(states["failures/Failed"].AcknowledgedBy).To(Equal("U123"))
+	})
+})
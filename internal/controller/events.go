@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// Reasons of the events recorded on Jobs, Workflows and rules for notifications.
+const (
+	ReasonNotificationSent   = "NotificationSent"
+	ReasonNotificationFailed = "NotificationFailed"
+	ReasonNotificationDryRun = "NotificationDryRun"
+)
+
+// defaultEventInterval is the minimum time between two events of a Notifier
+// without an EventInterval for the same object, reason and destination.
+const defaultEventInterval = time.Minute
+
+// maxLimitedEvents is how many recent events an eventLimiter remembers
+// before it forgets those whose interval passed.
+const maxLimitedEvents = 1000
+
+// eventLimiter lets an event through at most once per interval for each key.
+type eventLimiter struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// allow reports whether an event for key may be recorded at now, and if so
+// remembers it.
+func (l *eventLimiter) allow(key string, now time.Time, interval time.Duration) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.last[key]; ok && now.Sub(last) < interval {
+		return false
+	}
+	if l.last == nil {
+		l.last = map[string]time.Time{}
+	}
+	if len(l.last) >= maxLimitedEvents {
+		for k, last := range l.last {
+			if now.Sub(last) >= interval {
+				delete(l.last, k)
+			}
+		}
+	}
+	l.last[key] = now
+	return true
+}
+
+// recordNotificationEvents records a NotificationSent, NotificationFailed or
+// NotificationDryRun event for the outcome of record on triggerObj and on
+// rule, naming the destination. Events for the same object, destination and
+// outcome are recorded at most once per EventInterval.
+func (n *Notifier) recordNotificationEvents(triggerObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus, record notificationv1alpha1.NotificationRecord) {
+	if n.Recorder == nil {
+		return
+	}
+	destination := describeDestination(rule, note, dest)
+	eventType, reason := corev1.EventTypeNormal, ReasonNotificationSent
+	message := fmt.Sprintf("%s notification sent to %s", note.Status, destination)
+	switch record.Outcome {
+	case OutcomeFailed:
+		eventType, reason = corev1.EventTypeWarning, ReasonNotificationFailed
+		message = fmt.Sprintf("%s notification to %s failed: %s", note.Status, destination, record.Error)
+	case OutcomeDryRun:
+		reason = ReasonNotificationDryRun
+		message = fmt.Sprintf("%s notification to %s not sent in dry run: %q", note.Status, destination, record.Title)
+	}
+	interval := n.EventInterval
+	if interval <= 0 {
+		interval = defaultEventInterval
+	}
+	now := n.now()
+	for _, obj := range []client.Object{triggerObj, ruleObject(rule)} {
+		key := fmt.Sprintf("%T/%s/%s/%s/%s", obj, obj.GetNamespace(), obj.GetName(), reason, destination)
+		if n.eventLimiter.allow(key, now, interval) {
+			n.Recorder.Event(obj, eventType, reason, message)
+		}
+	}
+}
+
+// describeDestination names the channel and configuration, or the sink, a
+// notification of rule is sent to. The channel of a destination without one
+// is that of note, if it is known.
+func describeDestination(rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus) string {
+	ref, channel := qualifiedConfigRef(rule), note.Channel
+	if dest != nil {
+		if dest.Sink != "" {
+			return "SinkConfig " + dest.Sink
+		}
+		ref = dest.SlackConfigRef
+		if dest.Channel != "" {
+			channel = dest.Channel
+		}
+	}
+	config := ref.Kind + " " + ref.Name
+	if ref.Namespace != "" {
+		config = ref.Kind + " " + ref.Namespace + "/" + ref.Name
+	}
+	if channel == "" {
+		return config
+	}
+	return channel + " via " + config
+}
+
+// ruleObject returns the object events about rule are recorded on: the rule,
+// or the ClusterSlackNotificationRule it is a view of.
+func ruleObject(rule notificationv1alpha1.SlackNotificationRule) client.Object {
+	if isClusterRule(rule) {
+		return &notificationv1alpha1.ClusterSlackNotificationRule{ObjectMeta: rule.ObjectMeta}
+	}
+	return &rule
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("Notification events", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx      context.Context
+		recorder *record.FakeRecorder
+		clock    *clocktesting.FakePassiveClock
+		notifier *Notifier
+		rule     *notificationv1alpha1.SlackNotificationRule
+		job      *batchv1.Job
+		cronJob  *batchv1.CronJob
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		clock = clocktesting.NewFakePassiveClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace}}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace}}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed", Channel: "#team-a"}},
+			},
+		}
+	})
+
+	newNotifier := func(slackFk *fakeSlackClient) {
+		c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(
+				&notificationv1alpha1.SlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType:       "Token",
+						TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Data:       map[string][]byte{"token": []byte("xoxb-test")},
+				},
+				rule,
+			).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		notifier = &Notifier{Client: c, SlackClient: slackFk, Clock: clock, Recorder: recorder}
+	}
+
+	notify := func() {
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("records sent notifications on the Job and the rule", func() {
+		newNotifier(&fakeSlackClient{})
+		notify()
+		Expect(recorder.Events).To(HaveLen(2))
+		for range 2 {
+			Expect(recorder.Events).To(Receive(Equal("Normal NotificationSent Failed notification sent to #team-a via SlackConfig team-a/slack")))
+		}
+	})
+
+	It("records failed notifications with the error", func() {
+		rule.Spec.SlackConfigRef.Name = "missing"
+		newNotifier(&fakeSlackClient{})
+		notify()
+		Expect(recorder.Events).To(HaveLen(2))
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix("Warning NotificationFailed Failed notification to #team-a via SlackConfig team-a/missing failed:"),
+			ContainSubstring("not found"),
+		)))
+	})
+
+	It("names the configuration of every destination", func() {
+		rule.Spec.Notifications[0].Channel = ""
+		rule.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+			{Channel: "#sre"},
+			{Channel: "#sre", SlackConfigRef: &notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "shared"}},
+		}
+		newNotifier(&fakeSlackClient{})
+		notify()
+		Expect(recorder.Events).To(HaveLen(4))
+		var events []string
+		for range 4 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ConsistOf(
+			"Normal NotificationSent Failed notification sent to #sre via SlackConfig team-a/slack",
+			"Normal NotificationSent Failed notification sent to #sre via SlackConfig team-a/slack",
+			HavePrefix("Warning NotificationFailed Failed notification to #sre via ClusterSlackConfig shared failed:"),
+			HavePrefix("Warning NotificationFailed Failed notification to #sre via ClusterSlackConfig shared failed:"),
+		))
+	})
+
+	It("records an event per object and destination at most once per interval", func() {
+		newNotifier(&fakeSlackClient{})
+		notify()
+		Expect(recorder.Events).To(HaveLen(2))
+
+		// Only the second Job gets an event, the rule got one a moment ago.
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-2", Namespace: namespace}}
+		notify()
+		Expect(recorder.Events).To(HaveLen(3))
+
+		clock.SetTime(clock.Now().Add(time.Minute))
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-3", Namespace: namespace}}
+		notify()
+		Expect(recorder.Events).To(HaveLen(5))
+	})
+})
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/cloudevents"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// sentMessage is a message recorded by fakeSlackClient.
+type sentMessage struct {
+	Token   string
+	Channel string
+	Title   string
+	Buttons []slack.Button
+}
+
+// fakeSlackClient records messages instead of posting them to Slack.
+type fakeSlackClient struct {
+	sent         []sentMessage
+	acknowledged []string
+	replies      []string
+	userGroups   map[string][]string
+	authErr      error
+	channels     map[string]*goslack.Channel
+	lookups      []string
+	// oauth refreshes tokens, e.g. a slack.Client for a fake oauth.v2.access endpoint.
+	oauth slack.Client
+}
+
+func (f *fakeSlackClient) Send(_ context.Context, _ string, token string, channel string, titleTmpl string, _ string, _ []goslack.AttachmentField, data any) (string, string, error) {
+	title, err := slack.RenderTitle(titleTmpl, data)
+	if err != nil {
+		return "", "", err
+	}
+	f.sent = append(f.sent, sentMessage{Token: token, Channel: channel, Title: title})
+	if token == "" {
+		return "", "", nil
+	}
+	return "C" + channel, "1700000000.000200", nil
+}
+
+func (f *fakeSlackClient) SendWithActions(_ context.Context, token string, channel string, titleTmpl string, _ string, _ []goslack.AttachmentField, data any, buttons []slack.Button) (string, string, error) {
+	title, err := slack.RenderTitle(titleTmpl, data)
+	if err != nil {
+		return "", "", err
+	}
+	f.sent = append(f.sent, sentMessage{Token: token, Channel: channel, Title: title, Buttons: buttons})
+	return "C" + channel, "1700000000.000100", nil
+}
+
+func (f *fakeSlackClient) Acknowledge(_ context.Context, _ string, channelID string, ts string, _ goslack.Message, userID string) error {
+	f.acknowledged = append(f.acknowledged, channelID+"/"+ts+"/"+userID)
+	return nil
+}
+
+func (f *fakeSlackClient) Reply(_ context.Context, _ string, _ string, _ string, text string) error {
+	f.replies = append(f.replies, text)
+	return nil
+}
+
+func (f *fakeSlackClient) UserGroupMembers(_ context.Context, _ string, groupID string) ([]string, error) {
+	return f.userGroups[groupID], nil
+}
+
+func (f *fakeSlackClient) AuthTest(context.Context, string) (*goslack.AuthTestResponse, error) {
+	if f.authErr != nil {
+		return nil, f.authErr
+	}
+	return &goslack.AuthTestResponse{User: "notifier", Team: "example", TeamID: "T0EXAMPLE"}, nil
+}
+
+func (f *fakeSlackClient) ConversationInfo(_ context.Context, _ string, channel string) (*goslack.Channel, error) {
+	f.lookups = append(f.lookups, channel)
+	info, ok := f.channels[channel]
+	if !ok {
+		return nil, slack.ErrChannelNotFound
+	}
+	return info, nil
+}
+
+func (f *fakeSlackClient) RefreshToken(ctx context.Context, clientID string, clientSecret string, refreshToken string) (*goslack.OAuthV2Response, error) {
+	if f.oauth == nil {
+		return nil, errors.New("token refresh is not configured")
+	}
+	return f.oauth.RefreshToken(ctx, clientID, clientSecret, refreshToken)
+}
+
+func newTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(notificationv1alpha1.AddToScheme(s)).To(Succeed())
+	Expect(argov1alpha1.AddToScheme(s)).To(Succeed())
+	return s
+}
+
+// fakePublisher records CloudEvents instead of publishing them.
+type fakePublisher struct {
+	events []cloudevents.Event
+}
+
+func (f *fakePublisher) Publish(_ context.Context, event cloudevents.Event) error {
+	f.events = append(f.events, event)
+	return nil
+}
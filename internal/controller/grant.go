@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// errReferenceNotGranted is returned when a rule references a SlackConfig in
+// another namespace that no SlackConfigGrant allows it to use.
+var errReferenceNotGranted = errors.New("reference not granted")
+
+// slackConfigNamespace returns the namespace of the SlackConfig a rule references.
+func slackConfigNamespace(rule notificationv1alpha1.SlackNotificationRule) string {
+	if ns := rule.Spec.SlackConfigRef.Namespace; ns != "" {
+		return ns
+	}
+	return rule.Namespace
+}
+
+// checkReferenceGrant returns errReferenceNotGranted unless a SlackConfigGrant
+// in configNamespace allows rules in fromNamespace to reference the SlackConfig name.
+func checkReferenceGrant(ctx context.Context, c client.Reader, fromNamespace, configNamespace, name string) error {
+	var grants notificationv1alpha1.SlackConfigGrantList
+	if err := c.List(ctx, &grants, client.InNamespace(configNamespace)); err != nil {
+		return fmt.Errorf("failed to list SlackConfigGrants: %w", err)
+	}
+	for _, grant := range grants.Items {
+		if grantAllows(grant.Spec, fromNamespace, name) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no SlackConfigGrant in namespace %s allows namespace %s to use SlackConfig %s",
+		errReferenceNotGranted, configNamespace, fromNamespace, name)
+}
+
+func grantAllows(spec notificationv1alpha1.SlackConfigGrantSpec, fromNamespace, name string) bool {
+	from := slices.ContainsFunc(spec.From, func(f notificationv1alpha1.SlackConfigGrantFrom) bool {
+		return f.Namespace == fromNamespace
+	})
+	to := len(spec.To) == 0 || slices.ContainsFunc(spec.To, func(t notificationv1alpha1.SlackConfigGrantTo) bool {
+		return t.Name == name
+	})
+	return from && to
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// defaultHistoryLimit is the number of notification records kept for a rule
+// without a history limit.
+const defaultHistoryLimit = 20
+
+// Failed notifications are retried after retryBaseDelay, doubled for every
+// further attempt up to retryMaxDelay.
+const (
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// postedMessage identifies a Slack message posted for a notification.
+type postedMessage struct {
+	channelID string
+	ts        string
+}
+
+// notificationKey returns a record identifying the notification of rule
+// about triggerObj to dest, which is nil for the configuration of the rule.
+func notificationKey(triggerObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus) notificationv1alpha1.NotificationRecord {
+	record := notificationv1alpha1.NotificationRecord{
+		TriggerUID: triggerObj.GetUID(),
+		Trigger:    triggerObj.GetName(),
+		Namespace:  triggerObj.GetNamespace(),
+		Status:     note.Status,
+	}
+	switch {
+	case dest == nil:
+		record.SlackConfigRef = qualifiedConfigRef(rule)
+		record.Channel = note.Channel
+	case dest.Sink != "":
+		record.Sink = dest.Sink
+	default:
+		record.SlackConfigRef = dest.SlackConfigRef
+		record.Channel = dest.Channel
+	}
+	return record
+}
+
+// sameNotification reports whether a and b record the same notification.
+func sameNotification(a, b notificationv1alpha1.NotificationRecord) bool {
+	return a.TriggerUID == b.TriggerUID && a.Namespace == b.Namespace && a.Trigger == b.Trigger &&
+		a.Status == b.Status && a.SlackConfigRef == b.SlackConfigRef && a.Sink == b.Sink && a.Channel == b.Channel
+}
+
+// delivered reports whether the history of rule records the notification
+// about triggerObj to dest as delivered, or as rendered by a dry run that is
+// still in effect, so that it is not sent again when the Job or Workflow is
+// reconciled again.
+func (n *Notifier) delivered(triggerObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus) bool {
+	key := notificationKey(triggerObj, rule, note, dest)
+	for _, record := range rule.Status.History {
+		if sameNotification(record, key) {
+			return record.Outcome == OutcomeDelivered || record.Outcome == OutcomeDryRun && n.dryRun(rule)
+		}
+	}
+	return false
+}
+
+// attempts returns how many times the history of rule records the
+// notification about triggerObj to dest as attempted.
+func attempts(triggerObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus) int32 {
+	key := notificationKey(triggerObj, rule, note, dest)
+	for _, record := range rule.Status.History {
+		if sameNotification(record, key) {
+			return record.Attempts
+		}
+	}
+	return 0
+}
+
+// retryBackoff returns how long to wait before retrying a notification that
+// failed on its attempt-th attempt.
+func retryBackoff(attempt int32) time.Duration {
+	backoff := retryBaseDelay
+	for i := int32(1); i < attempt && backoff < retryMaxDelay; i++ {
+		backoff *= 2
+	}
+	return min(backoff, retryMaxDelay)
+}
+
+// sentMarker identifies the notifications of rule for status in the
+// AnnotationSentStatuses annotation.
+func sentMarker(rule notificationv1alpha1.SlackNotificationRule, status string) string {
+	if isClusterRule(rule) {
+		return "cluster/" + rule.Name + "/" + status
+	}
+	return rule.Name + "/" + status
+}
+
+// sentMarkers returns the notifications marked as sent on a Job or Workflow.
+func sentMarkers(triggerObj client.Object) []string {
+	raw := triggerObj.GetAnnotations()[AnnotationSentStatuses]
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// markSent marks the notifications identified by marker as sent on a Job or
+// Workflow. Failing to mark them does not fail the notification, which is
+// then only kept from being sent again by the history of the rule.
+func (n *Notifier) markSent(ctx context.Context, triggerObj client.Object, marker string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := n.Client.Get(ctx, client.ObjectKeyFromObject(triggerObj), triggerObj); err != nil {
+			return err
+		}
+		markers := sentMarkers(triggerObj)
+		if slices.Contains(markers, marker) {
+			return nil
+		}
+		patch := client.MergeFromWithOptions(triggerObj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		annotations := triggerObj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[AnnotationSentStatuses] = strings.Join(append(markers, marker), ",")
+		triggerObj.SetAnnotations(annotations)
+		return n.Client.Patch(ctx, triggerObj, patch)
+	})
+	if client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to mark notification as sent", "name", triggerObj.GetName(), "notification", marker)
+	}
+}
+
+// newRecord returns the record of an attempt to send a notification.
+func (n *Notifier) newRecord(triggerObj, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, dest *notificationv1alpha1.DestinationStatus, posted postedMessage, sendErr error) notificationv1alpha1.NotificationRecord {
+	now := metav1.NewTime(n.now())
+	record := notificationKey(triggerObj, rule, note, dest)
+	record.Target = targetObj.GetName()
+	if data, err := toTemplateData(triggerObj); err == nil {
+		// A title that does not render fails the notification, which records why.
+		record.Title, _ = slack.RenderTitle(note.Title, data)
+	}
+	record.ChannelID, record.MessageTS = posted.channelID, posted.ts
+	record.Attempts = 1
+	record.Outcome = OutcomeDelivered
+	switch {
+	case sendErr == nil && n.dryRun(rule):
+		record.Outcome = OutcomeDryRun
+	case errors.Is(sendErr, policy.ErrViolation):
+		record.Outcome = OutcomeSuppressed
+	case sendErr != nil:
+		record.Outcome = OutcomeFailed
+	}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+	record.FirstAttemptTime, record.LastAttemptTime = now, now
+	return record
+}
+
+// recordHistory adds record to the history of rule. Failing to record it
+// does not fail the notification.
+func (n *Notifier) recordHistory(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, record notificationv1alpha1.NotificationRecord) {
+	err := n.updateRuleStatus(ctx, rule, func(status *notificationv1alpha1.SlackNotificationRuleStatus) {
+		addRecord(status, rule.Spec.History, record)
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record notification in rule history", "rule", rule.Name)
+	}
+}
+
+// addRecord adds record to the history in status as its latest record,
+// counting it as another attempt of the same notification recorded before,
+// and drops the records retention does not keep.
+func addRecord(status *notificationv1alpha1.SlackNotificationRuleStatus, retention *notificationv1alpha1.HistoryRetention, record notificationv1alpha1.NotificationRecord) {
+	limit := defaultHistoryLimit
+	var maxAge time.Duration
+	if retention != nil {
+		if retention.Limit > 0 {
+			limit = int(retention.Limit)
+		}
+		if retention.MaxAge != nil {
+			maxAge = retention.MaxAge.Duration
+		}
+	}
+
+	history := make([]notificationv1alpha1.NotificationRecord, 0, len(status.History)+1)
+	for _, r := range status.History {
+		if sameNotification(r, record) {
+			record.Attempts += r.Attempts
+			record.FirstAttemptTime = r.FirstAttemptTime
+			if record.MessageTS == "" {
+				record.ChannelID, record.MessageTS = r.ChannelID, r.MessageTS
+			}
+			continue
+		}
+		if maxAge > 0 && record.LastAttemptTime.Sub(r.LastAttemptTime.Time) > maxAge {
+			continue
+		}
+		history = append(history, r)
+	}
+	history = append(history, record)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	status.History = history
+}
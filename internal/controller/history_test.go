@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("Notification history", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx      context.Context
+		c        client.Client
+		slackFk  *fakeSlackClient
+		clock    *clocktesting.FakePassiveClock
+		notifier *Notifier
+		rule     *notificationv1alpha1.SlackNotificationRule
+		cronJob  *batchv1.CronJob
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		slackFk = &fakeSlackClient{}
+		clock = clocktesting.NewFakePassiveClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace}}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed", Channel: "#team-a"}},
+			},
+		}
+	})
+
+	build := func(objects ...client.Object) {
+		c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(append(objects, rule)...).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		notifier = &Notifier{Client: c, SlackClient: slackFk, Clock: clock}
+	}
+
+	slackConfig := func() []client.Object {
+		return []client.Object{
+			&notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:       "Token",
+					TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Data:       map[string][]byte{"token": []byte("xoxb-test")},
+			},
+		}
+	}
+
+	notify := func(name string) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID("uid-" + name)}}
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	history := func() []notificationv1alpha1.NotificationRecord {
+		var got notificationv1alpha1.SlackNotificationRule
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+		return got.Status.History
+	}
+
+	It("records delivered notifications and does not send them again", func() {
+		build(slackConfig()...)
+		notify("backup-1")
+		records := history()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].FirstAttemptTime.Time).To(BeTemporally("==", clock.Now()))
+		Expect(records[0].LastAttemptTime.Time).To(BeTemporally("==", clock.Now()))
+		records[0].FirstAttemptTime, records[0].LastAttemptTime = metav1.Time{}, metav1.Time{}
+		Expect(records[0]).To(Equal(notificationv1alpha1.NotificationRecord{
+			TriggerUID:     "uid-backup-1",
+			Trigger:        "backup-1",
+			Namespace:      namespace,
+			Target:         "backup",
+			Status:         "Failed",
+			SlackConfigRef: notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindSlackConfig, Name: "slack", Namespace: namespace},
+			Channel:        "#team-a",
+			Title:          "backup-1 failed",
+			ChannelID:      "C#team-a",
+			MessageTS:      "1700000000.000200",
+			Attempts:       1,
+			Outcome:        OutcomeDelivered,
+		}))
+
+		notify("backup-1")
+		Expect(slackFk.sent).To(HaveLen(1))
+		Expect(history()).To(HaveLen(1))
+	})
+
+	It("retries failed notifications and counts the attempts", func() {
+		build()
+		notify("backup-1")
+		records := history()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Outcome).To(Equal(OutcomeFailed))
+		Expect(records[0].Error).To(ContainSubstring("not found"))
+
+		for _, obj := range slackConfig() {
+			Expect(c.Create(ctx, obj)).To(Succeed())
+		}
+		clock.SetTime(clock.Now().Add(time.Minute))
+		notify("backup-1")
+		records = history()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Outcome).To(Equal(OutcomeDelivered))
+		Expect(records[0].Error).To(BeEmpty())
+		Expect(records[0].Attempts).To(Equal(int32(2)))
+		Expect(records[0].FirstAttemptTime.Time).To(BeTemporally("==", clock.Now().Add(-time.Minute)))
+		Expect(records[0].LastAttemptTime.Time).To(BeTemporally("==", clock.Now()))
+	})
+
+	It("requeues failed notifications with a growing backoff", func() {
+		build()
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace, UID: "uid-backup-1"}}
+		for _, backoff := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second} {
+			requeueAfter, err := notifier.Notify(ctx, job, cronJob, "Failed")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeueAfter).To(Equal(backoff))
+		}
+		Expect(history()).To(ConsistOf(HaveField("Attempts", int32(3))))
+		Expect(retryBackoff(100)).To(Equal(10 * time.Minute))
+
+		for _, obj := range slackConfig() {
+			Expect(c.Create(ctx, obj)).To(Succeed())
+		}
+		requeueAfter, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+	})
+
+	It("keeps the latest records within the limit and age of the retention", func() {
+		rule.Spec.History = &notificationv1alpha1.HistoryRetention{Limit: 2, MaxAge: &metav1.Duration{Duration: time.Hour}}
+		build(slackConfig()...)
+		for _, name := range []string{"backup-1", "backup-2", "backup-3"} {
+			notify(name)
+		}
+		Expect(history()).To(HaveExactElements(HaveField("Trigger", "backup-2"), HaveField("Trigger", "backup-3")))
+
+		clock.SetTime(clock.Now().Add(2 * time.Hour))
+		notify("backup-4")
+		Expect(history()).To(HaveExactElements(HaveField("Trigger", "backup-4")))
+	})
+
+	It("does not send rerouted notifications again once the quiet hours end", func() {
+		rule.Spec.Notifications[0].QuietHours = &notificationv1alpha1.QuietHours{
+			TimeZone: "UTC",
+			Windows:  []notificationv1alpha1.TimeWindow{{Start: "09:00", End: "18:00"}},
+			Action:   QuietHoursActionReroute,
+			Channel:  "#low-priority",
+		}
+		build(slackConfig()...)
+		notify("backup-1")
+		Expect(slackFk.sent).To(HaveLen(1))
+		Expect(history()).To(ConsistOf(HaveField("Channel", "#team-a")))
+
+		clock.SetTime(clock.Now().Add(9 * time.Hour))
+		notify("backup-1")
+		Expect(slackFk.sent).To(HaveLen(1))
+	})
+
+	It("marks sent notifications on the Job", func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace, UID: "uid-backup-1"}}
+		build(append(slackConfig(), job)...)
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+
+		var got batchv1.Job
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(job), &got)).To(Succeed())
+		Expect(got.Annotations).To(HaveKeyWithValue(AnnotationSentStatuses, "backups/Failed"))
+	})
+
+	It("does not send notifications marked on the Job after their records are evicted", func() {
+		build(slackConfig()...)
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:        "backup-1",
+			Namespace:   namespace,
+			UID:         "uid-backup-1",
+			Annotations: map[string]string{AnnotationSentStatuses: "other/Succeeded,backups/Failed"},
+		}}
+		_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slackFk.sent).To(BeEmpty())
+		Expect(history()).To(BeEmpty())
+	})
+})
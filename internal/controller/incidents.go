@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+// statusSucceeded is the status of a successful run, which resolves the
+// incidents opened for earlier runs.
+const statusSucceeded = "Succeeded"
+
+// incidentKey is the dedup key of the incidents rule opens for the runs of
+// targetObj that end with status. It is stable across runs, so that a failing
+// CronJob has a single open incident per rule and status.
+func incidentKey(rule notificationv1alpha1.SlackNotificationRule, targetObj client.Object, status string) string {
+	return incidentKeyPrefix(rule, targetObj) + status
+}
+
+// incidentKeyPrefix is the prefix of the dedup keys of all incidents rule
+// opens for targetObj.
+func incidentKeyPrefix(rule notificationv1alpha1.SlackNotificationRule, targetObj client.Object) string {
+	return fmt.Sprintf("slack-notifier/%s/%s/%s/", rule.Namespace, rule.Name, targetObj.GetUID())
+}
+
+// opensIncident reports whether notifications for status open incidents.
+// Running and successful runs do not need anyone's attention.
+func opensIncident(status string) bool {
+	return !strings.EqualFold(status, "Running") && !strings.EqualFold(status, statusSucceeded)
+}
+
+// recordIncident stores the incident triggered by triggerObj in the status of
+// rule, so that it can be resolved by a later run. Failing to store it does
+// not fail the notification.
+func (n *Notifier) recordIncident(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, sinkName string, msg *sink.Message) {
+	key := msg.DedupKey
+	incident := notificationv1alpha1.IncidentStatus{
+		Sink:            sinkName,
+		Target:          targetObj.GetName(),
+		DedupKey:        key,
+		Status:          msg.Status,
+		Run:             triggerObj.GetName(),
+		RunCreationTime: triggerObj.GetCreationTimestamp(),
+		LastTriggerTime: metav1.NewTime(n.now()),
+	}
+	err := n.updateRuleStatus(ctx, rule, func(status *notificationv1alpha1.SlackNotificationRuleStatus) {
+		if i := findIncident(status.Incidents, sinkName, key); i >= 0 {
+			status.Incidents[i] = incident
+			return
+		}
+		status.Incidents = append(status.Incidents, incident)
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record incident in rule status", "rule", rule.Name, "sink", sinkName)
+	}
+}
+
+// resolveIncidents resolves the incidents of every status rule opened for
+// earlier runs of targetObj now that triggerObj succeeded, and removes them
+// from its status. Incidents whose SinkConfig was deleted are removed without
+// being resolved.
+func (n *Notifier) resolveIncidents(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule) {
+	logger := log.FromContext(ctx)
+	prefix := incidentKeyPrefix(rule, targetObj)
+	created := triggerObj.GetCreationTimestamp()
+	for _, incident := range rule.Status.Incidents {
+		if !strings.HasPrefix(incident.DedupKey, prefix) || incident.Run == triggerObj.GetName() || created.Before(&incident.RunCreationTime) {
+			continue
+		}
+		err := n.resolveIncident(ctx, rule, incident)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to resolve incident", "rule", rule.Name, "sink", incident.Sink, "dedupKey", incident.DedupKey)
+			continue
+		}
+		err = n.updateRuleStatus(ctx, rule, func(status *notificationv1alpha1.SlackNotificationRuleStatus) {
+			if i := findIncident(status.Incidents, incident.Sink, incident.DedupKey); i >= 0 {
+				status.Incidents = append(status.Incidents[:i], status.Incidents[i+1:]...)
+			}
+		})
+		if err != nil {
+			logger.Error(err, "Failed to remove resolved incident from rule status", "rule", rule.Name, "sink", incident.Sink)
+		}
+	}
+}
+
+// resolveIncident resolves an incident of rule with its SinkConfig.
+func (n *Notifier) resolveIncident(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, incident notificationv1alpha1.IncidentStatus) error {
+	var config notificationv1alpha1.SinkConfig
+	if err := n.Client.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: incident.Sink}, &config); err != nil {
+		return err
+	}
+	s, err := n.newSink(ctx, &config)
+	if err != nil {
+		return err
+	}
+	incidents, ok := s.(sink.IncidentSink)
+	if !ok {
+		return fmt.Errorf("SinkConfig %s of type %s cannot resolve incidents", config.Name, config.Spec.Type)
+	}
+	return incidents.Resolve(ctx, &sink.Message{
+		Status:   incident.Status,
+		Source:   rule.Namespace + "/" + incident.Target,
+		Rule:     rule.Name,
+		DedupKey: incident.DedupKey,
+	})
+}
+
+func findIncident(incidents []notificationv1alpha1.IncidentStatus, sinkName, key string) int {
+	for i, incident := range incidents {
+		if incident.Sink == sinkName && incident.DedupKey == key {
+			return i
+		}
+	}
+	return -1
+}
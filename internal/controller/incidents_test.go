@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+var _ = Describe("Incidents", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx      context.Context
+		c        client.Client
+		notifier *Notifier
+		rule     *notificationv1alpha1.SlackNotificationRule
+		cronJob  *batchv1.CronJob
+		events   chan map[string]any
+		start    time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		start = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+		events = make(chan map[string]any, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var event map[string]any
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &event)
+			events <- event
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		DeferCleanup(srv.Close)
+
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, UID: "0b5c"}}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications: []notificationv1alpha1.NotificationRule{{
+					Status:       "Failed",
+					Title:        "{{ .metadata.name }} failed",
+					Destinations: []notificationv1alpha1.NotificationDestination{{SinkRef: &corev1.LocalObjectReference{Name: "pagerduty"}}},
+				}},
+			},
+		}
+		c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(rule, &notificationv1alpha1.SinkConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace},
+				Spec: notificationv1alpha1.SinkConfigSpec{
+					Type: sink.TypePagerDuty,
+					Incident: &notificationv1alpha1.IncidentSink{
+						KeySecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pagerduty"}, Key: "routing-key"},
+						APIURL:       srv.URL,
+					},
+				},
+			}, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace},
+				Data:       map[string][]byte{"routing-key": []byte("R0UT1NG")},
+			}).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		notifier = &Notifier{Client: c, SlackClient: &fakeSlackClient{}}
+	})
+
+	run := func(name string, created time.Time, status string) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created)}}
+		_, err := notifier.Notify(ctx, job, cronJob, status)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	incidents := func() []notificationv1alpha1.IncidentStatus {
+		var got notificationv1alpha1.SlackNotificationRule
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+		return got.Status.Incidents
+	}
+
+	It("triggers an incident on failure and resolves it when a later run succeeds", func() {
+		run("backup-1", start, "Failed")
+		var event map[string]any
+		Eventually(events).Should(Receive(&event))
+		Expect(event).To(HaveKeyWithValue("event_action", "trigger"))
+		Expect(event).To(HaveKeyWithValue("dedup_key", "slack-notifier/team-a/backups/0b5c/Failed"))
+		Expect(event["payload"]).To(HaveKeyWithValue("summary", "backup-1 failed"))
+		Expect(incidents()).To(ConsistOf(And(
+			HaveField("Sink", "pagerduty"),
+			HaveField("Target", "backup"),
+			HaveField("Run", "backup-1"),
+			HaveField("DedupKey", "slack-notifier/team-a/backups/0b5c/Failed"),
+		)))
+
+		By("deduplicating the failure of the next run")
+		run("backup-2", start.Add(time.Hour), "Failed")
+		Eventually(events).Should(Receive(HaveKeyWithValue("dedup_key", "slack-notifier/team-a/backups/0b5c/Failed")))
+		Expect(incidents()).To(ConsistOf(HaveField("Run", "backup-2")))
+
+		By("resolving it when a later run succeeds")
+		run("backup-3", start.Add(2*time.Hour), "Succeeded")
+		Eventually(events).Should(Receive(Equal(map[string]any{
+			"routing_key": "R0UT1NG", "event_action": "resolve", "dedup_key": "slack-notifier/team-a/backups/0b5c/Failed",
+		})))
+		Expect(incidents()).To(BeEmpty())
+	})
+
+	It("keeps an incident per status and resolves all of them", func() {
+		rule.Spec.Notifications = append(rule.Spec.Notifications,
+			notificationv1alpha1.NotificationRule{Status: "Missed", Title: "{{ .metadata.name }} missed", Destinations: rule.Spec.Notifications[0].Destinations},
+			notificationv1alpha1.NotificationRule{Status: "Running", Title: "{{ .metadata.name }} started", Destinations: rule.Spec.Notifications[0].Destinations},
+		)
+		Expect(c.Update(ctx, rule)).To(Succeed())
+
+		run("backup-1", start, "Failed")
+		Eventually(events).Should(Receive(HaveKeyWithValue("dedup_key", "slack-notifier/team-a/backups/0b5c/Failed")))
+		run("backup-2", start.Add(time.Hour), "Missed")
+		Eventually(events).Should(Receive(HaveKeyWithValue("dedup_key", "slack-notifier/team-a/backups/0b5c/Missed")))
+
+		By("not opening an incident for a running Job")
+		run("backup-3", start.Add(2*time.Hour), "Running")
+		Consistently(events, 100*time.Millisecond).ShouldNot(Receive())
+		Expect(incidents()).To(ConsistOf(HaveField("Status", "Failed"), HaveField("Status", "Missed")))
+
+		run("backup-3", start.Add(2*time.Hour), "Succeeded")
+		Eventually(events).Should(Receive(HaveKeyWithValue("event_action", "resolve")))
+		Eventually(events).Should(Receive(HaveKeyWithValue("event_action", "resolve")))
+		Expect(incidents()).To(BeEmpty())
+	})
+
+	It("does not resolve incidents when an earlier run succeeds", func() {
+		run("backup-2", start, "Failed")
+		Eventually(events).Should(Receive())
+
+		run("backup-1", start.Add(-time.Hour), "Succeeded")
+		Consistently(events, 100*time.Millisecond).ShouldNot(Receive())
+		Expect(incidents()).To(HaveLen(1))
+	})
+
+	It("drops incidents whose SinkConfig was deleted", func() {
+		run("backup-1", start, "Failed")
+		Eventually(events).Should(Receive())
+		Expect(c.Delete(ctx, &notificationv1alpha1.SinkConfig{ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace}})).To(Succeed())
+
+		run("backup-2", start.Add(time.Hour), "Succeeded")
+		Expect(incidents()).To(BeEmpty())
+	})
+
+	It("fires Alertmanager alerts and ends them when a later run succeeds", func() {
+		alerts := make(chan []map[string]any, 10)
+		am := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var posted []map[string]any
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &posted)
+			alerts <- posted
+		}))
+		DeferCleanup(am.Close)
+		var config notificationv1alpha1.SinkConfig
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "pagerduty"}, &config)).To(Succeed())
+		config.Spec = notificationv1alpha1.SinkConfigSpec{
+			Type:         sink.TypeAlertmanager,
+			URLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pagerduty"}, Key: "url"},
+			Alertmanager: &notificationv1alpha1.AlertmanagerSink{Labels: map[string]string{"severity": "page"}},
+		}
+		Expect(c.Update(ctx, &config)).To(Succeed())
+		Expect(c.Update(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: namespace},
+			Data:       map[string][]byte{"url": []byte(am.URL)},
+		})).To(Succeed())
+		notifier.Clock = clocktesting.NewFakePassiveClock(start)
+		labels := map[string]any{
+			"alertname": "CronJobNotification", "severity": "page",
+			"rule": "backups", "namespace": namespace, "target": "backup", "status": "Failed",
+		}
+
+		run("backup-1", start, "Failed")
+		var posted []map[string]any
+		Eventually(alerts).Should(Receive(&posted))
+		Expect(posted).To(ConsistOf(And(
+			HaveKeyWithValue("labels", labels),
+			HaveKeyWithValue("annotations", HaveKeyWithValue("summary", "backup-1 failed")),
+			HaveKeyWithValue("endsAt", "2025-01-16T10:00:00Z"),
+		)))
+		Expect(incidents()).To(ConsistOf(HaveField("Status", "Failed")))
+
+		run("backup-2", start.Add(time.Hour), "Succeeded")
+		Eventually(alerts).Should(Receive(&posted))
+		Expect(posted).To(Equal([]map[string]any{{"labels": labels, "endsAt": "2025-01-15T10:00:00Z"}}))
+		Expect(incidents()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/interactivity"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// NotificationRef identifies a posted notification. It is carried as the value
+// of the buttons attached to the notification.
+type NotificationRef struct {
+	// Kind is the kind of the trigger object (Job or Workflow).
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Target is the name of the CronJob or CronWorkflow owning the trigger object.
+	Target string `json:"target"`
+	Rule   string `json:"rule"`
+	Status string `json:"status"`
+	// ClusterRule is set when Rule names a ClusterSlackNotificationRule.
+	ClusterRule bool `json:"clusterRule,omitempty"`
+	// Config is set to the configuration the notification was sent through
+	// when it was sent to one of the destinations of the notification.
+	Config *notificationv1alpha1.SlackConfigReference `json:"config,omitempty"`
+}
+
+func newNotificationRef(triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) NotificationRef {
+	var config *notificationv1alpha1.SlackConfigReference
+	if len(note.Destinations) > 0 {
+		config = &rule.Spec.SlackConfigRef
+	}
+	return NotificationRef{
+		Kind:        triggerKind(triggerObj),
+		Namespace:   triggerObj.GetNamespace(),
+		Name:        triggerObj.GetName(),
+		Target:      targetObj.GetName(),
+		Rule:        rule.Name,
+		Status:      note.Status,
+		ClusterRule: isClusterRule(rule),
+		Config:      config,
+	}
+}
+
+func (r NotificationRef) key() string {
+	if r.ClusterRule {
+		return "cluster/" + r.Rule + "/" + r.Status
+	}
+	return r.Rule + "/" + r.Status
+}
+
+// targetKind returns the kind of the object owning the trigger object.
+func (r NotificationRef) targetKind() string {
+	switch r.Kind {
+	case "Job":
+		return "CronJob"
+	case "Workflow":
+		return "CronWorkflow"
+	}
+	return ""
+}
+
+func (r NotificationRef) encode() (string, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode notification reference: %w", err)
+	}
+	return string(b), nil
+}
+
+func decodeNotificationRef(value string) (NotificationRef, error) {
+	var ref NotificationRef
+	if err := json.Unmarshal([]byte(value), &ref); err != nil {
+		return NotificationRef{}, fmt.Errorf("invalid notification reference: %w", err)
+	}
+	if ref.Namespace == "" || ref.Name == "" || ref.Rule == "" {
+		return NotificationRef{}, fmt.Errorf("incomplete notification reference")
+	}
+	return ref, nil
+}
+
+func triggerKind(obj client.Object) string {
+	switch obj.(type) {
+	case *batchv1.Job:
+		return "Job"
+	case *argov1alpha1.Workflow:
+		return "Workflow"
+	}
+	return ""
+}
+
+func newTriggerObject(kind string) (client.Object, error) {
+	switch kind {
+	case "Job":
+		return &batchv1.Job{}, nil
+	case "Workflow":
+		return &argov1alpha1.Workflow{}, nil
+	}
+	return nil, fmt.Errorf("unsupported trigger kind %q", kind)
+}
+
+// interactive reports whether buttons can be attached to notifications sent
+// to the destination.
+func (d *destination) interactive() bool {
+	return d.token != "" && d.config.Spec.Interactivity != nil
+}
+
+var actionButtons = map[notificationv1alpha1.NotificationAction]slack.Button{
+	notificationv1alpha1.ActionRerun:    {ActionID: slack.ActionRerun, Text: "Rerun"},
+	notificationv1alpha1.ActionSuspend:  {ActionID: slack.ActionSuspend, Text: "Suspend", Style: goslack.StyleDanger},
+	notificationv1alpha1.ActionResume:   {ActionID: slack.ActionResume, Text: "Resume"},
+	notificationv1alpha1.ActionResubmit: {ActionID: slack.ActionResubmit, Text: "Resubmit"},
+}
+
+// notificationButtons returns the buttons attached to a notification.
+func notificationButtons(note notificationv1alpha1.NotificationRule, ref NotificationRef, value string, ack bool) []slack.Button {
+	var buttons []slack.Button
+	if ack {
+		buttons = append(buttons, slack.Button{ActionID: slack.ActionAcknowledge, Text: "Acknowledge", Value: value, Style: goslack.StylePrimary})
+	}
+	for _, action := range note.Actions {
+		if !actionSupported(action, ref.targetKind()) {
+			continue
+		}
+		button := actionButtons[action]
+		button.Value = value
+		buttons = append(buttons, button)
+	}
+	return buttons
+}
+
+// Credentials returns the credentials of the Slack App configured in the
+// SlackConfig that posted the notification an action refers to.
+func (n *Notifier) Credentials(ctx context.Context, action *goslack.BlockAction) (interactivity.AppCredentials, error) {
+	ref, err := decodeNotificationRef(action.Value)
+	if err != nil {
+		return interactivity.AppCredentials{}, err
+	}
+	_, config, err := n.getNotificationRule(ctx, ref)
+	if err != nil {
+		return interactivity.AppCredentials{}, err
+	}
+	return n.appCredentials(ctx, config)
+}
+
+// appCredentials reads the credentials of the Slack App configured for interactivity in config.
+func (n *Notifier) appCredentials(ctx context.Context, config *notificationv1alpha1.SlackConfig) (interactivity.AppCredentials, error) {
+	var creds interactivity.AppCredentials
+	spec := config.Spec.Interactivity
+	if spec == nil {
+		return creds, fmt.Errorf("SlackConfig %s/%s has no interactivity", config.Namespace, config.Name)
+	}
+	if spec.SigningSecretRef != nil {
+		secret, err := n.getSecretValue(ctx, config, spec.SigningSecretRef)
+		if err != nil {
+			return creds, fmt.Errorf("failed to get signing secret: %w", err)
+		}
+		creds.SigningSecret = secret
+	}
+	if spec.AppTokenSecretRef != nil {
+		token, err := n.getSecretValue(ctx, config, spec.AppTokenSecretRef)
+		if err != nil {
+			return creds, fmt.Errorf("failed to get app-level token: %w", err)
+		}
+		creds.AppToken = token
+	}
+	return creds, nil
+}
+
+// AppTokens returns the distinct app-level tokens of the SlackConfigs and
+// ClusterSlackConfigs that receive interactivity requests over Socket Mode.
+func (n *Notifier) AppTokens(ctx context.Context) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	configs, err := n.listSlackConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for _, config := range configs {
+		if config.Spec.Interactivity == nil || config.Spec.Interactivity.AppTokenSecretRef == nil {
+			continue
+		}
+		token, err := n.getSecretValue(ctx, &config, config.Spec.Interactivity.AppTokenSecretRef)
+		if err != nil {
+			logger.Error(err, "Failed to get app-level token", "slackConfig", client.ObjectKeyFromObject(&config))
+			continue
+		}
+		if !slices.Contains(tokens, token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+// HandleAction handles a button clicked on a notification.
+func (n *Notifier) HandleAction(ctx context.Context, callback *goslack.InteractionCallback, action *goslack.BlockAction) error {
+	switch action.ActionID {
+	case slack.ActionAcknowledge:
+		return n.acknowledge(ctx, callback, action)
+	case slack.ActionRerun, slack.ActionSuspend, slack.ActionResume, slack.ActionResubmit:
+		return n.performAction(ctx, callback, action)
+	}
+	return fmt.Errorf("unsupported action %q", action.ActionID)
+}
+
+// getNotificationRule returns the rule that posted a notification, as a view
+// if it is a ClusterSlackNotificationRule, and its SlackConfig.
+func (n *Notifier) getNotificationRule(ctx context.Context, ref NotificationRef) (*notificationv1alpha1.SlackNotificationRule, *notificationv1alpha1.SlackConfig, error) {
+	var rule notificationv1alpha1.SlackNotificationRule
+	if ref.ClusterRule {
+		var clusterRule notificationv1alpha1.ClusterSlackNotificationRule
+		if err := n.Client.Get(ctx, types.NamespacedName{Name: ref.Rule}, &clusterRule); err != nil {
+			return nil, nil, fmt.Errorf("failed to get cluster rule: %w", err)
+		}
+		rule = clusterRuleView(&clusterRule)
+	} else if err := n.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Rule}, &rule); err != nil {
+		return nil, nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+	if ref.Config != nil {
+		if !hasDestination(rule, ref.Status, *ref.Config) {
+			return nil, nil, fmt.Errorf("rule %s does not send %s notifications through %s %s", ref.Rule, ref.Status, ref.Config.Kind, ref.Config.Name)
+		}
+		rule.Spec.SlackConfigRef = *ref.Config
+	}
+	config, err := n.getSlackConfig(ctx, rule)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rule, config, nil
+}
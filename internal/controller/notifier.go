@@ -2,7 +2,9 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,126 +16,542 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/metrics"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
 	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
 )
 
 const (
+	// AnnotationSentStatuses lists the notifications sent for a Job or
+	// Workflow, as comma-separated "<rule>/<status>", so that they are not sent
+	// again once their records have left the history of the rule.
 	AnnotationSentStatuses = "notification.murasame29.com/sent-statuses"
 )
 
 type Notifier struct {
 	Client      client.Client
 	SlackClient slack.Client
+	// Clock is used to evaluate quiet hours. Defaults to the real clock.
+	Clock clock.PassiveClock
+	// ClusterResourceNamespace is the namespace the Secrets of
+	// ClusterSlackConfigs are read from. ClusterSlackConfigs cannot be used
+	// when it is empty.
+	ClusterResourceNamespace string
+	// CredentialResolver reads the credentials of SlackConfigs. Defaults to
+	// reading Secrets with Client, without caching.
+	CredentialResolver *credentials.Resolver
+	// Recorder records events on triggers and rules for notifications that
+	// are sent, fail, are rendered by a dry run or are blocked by a channel
+	// policy. Events are not recorded if it is nil.
+	Recorder record.EventRecorder
+	// EventInterval is the minimum time between two NotificationSent,
+	// NotificationFailed or NotificationDryRun events on an object for the
+	// same destination.
+	// Defaults to a minute.
+	EventInterval time.Duration
+	// Events publishes a CloudEvent for every notification that is
+	// delivered, fails or is suppressed. Nothing is published if it is nil.
+	Events EventPublisher
+	// DryRun renders notifications without sending them, as if every rule
+	// set dryRun.
+	DryRun bool
+
+	eventLimiter eventLimiter
+	deferrals    deferrals
 }
 
 // Notify checks rules and sends notifications.
 // triggerObj: The object that triggered the event (e.g., Job, Workflow)
 // targetObj: The object that rules target (e.g., CronJob, CronWorkflow)
-// Notify checks rules and sends notifications.
-// triggerObj: The object that triggered the event (e.g., Job, Workflow)
-// targetObj: The object that rules target (e.g., CronJob, CronWorkflow)
-func (n *Notifier) Notify(ctx context.Context, triggerObj client.Object, targetObj client.Object, status string) error {
-	logger := log.FromContext(ctx)
+// It returns how long to wait before calling Notify again for notifications
+// held back by quiet hours, waiting for escalation or to be retried after
+// failing, or zero if none are.
+func (n *Notifier) Notify(ctx context.Context, triggerObj client.Object, targetObj client.Object, status string) (requeueAfter time.Duration, err error) {
+	ctx, span := tracing.Start(ctx, "Notify",
+		tracing.TriggerKey.String(client.ObjectKeyFromObject(triggerObj).String()),
+		tracing.TargetKey.String(client.ObjectKeyFromObject(targetObj).String()),
+		tracing.TargetKindKey.String(targetKind(targetObj)),
+		tracing.StatusKey.String(status),
+	)
+	defer func() { tracing.End(span, err) }()
 
-	// List Rules in the target object's namespace
-	var rules notificationv1alpha1.SlackNotificationRuleList
-	if err := n.Client.List(ctx, &rules, client.InNamespace(targetObj.GetNamespace())); err != nil {
-		return fmt.Errorf("failed to list rules: %w", err)
+	rules, err := n.matchingRules(ctx, targetObj)
+	if err != nil {
+		return 0, err
 	}
 
-	for _, rule := range rules.Items {
-		// Check Target Resource
-		targetRes := rule.Spec.TargetResource
-		if targetRes == "CronJob" {
-			if _, ok := targetObj.(*batchv1.CronJob); !ok {
-				continue
-			}
-		} else if targetRes == "CronWorkflow" {
-			if _, ok := targetObj.(*argov1alpha1.CronWorkflow); !ok {
-				continue
-			}
+	n.observeRun(triggerObj, targetObj, status)
+	defer func() { metrics.SetPending(runKey(triggerObj), requeueAfter > 0) }()
+
+	for _, rule := range rules {
+		if due := n.notifyRule(ctx, triggerObj, targetObj, rule, status); due > 0 && (requeueAfter == 0 || due < requeueAfter) {
+			requeueAfter = due
 		}
+	}
+	return requeueAfter, nil
+}
+
+// matchingRules returns the rules in the namespace of targetObj and the cluster
+// rules selecting it whose target resource and labels match targetObj.
+func (n *Notifier) matchingRules(ctx context.Context, targetObj client.Object) (matched []notificationv1alpha1.SlackNotificationRule, err error) {
+	ctx, span := tracing.Start(ctx, "MatchRules")
+	defer func() { tracing.End(span, err) }()
+	logger := log.FromContext(ctx)
 
-		// Check Labels
-		selector, err := metav1.LabelSelectorAsSelector(&rule.Spec.LabelSelector)
+	// List Rules in the target object's namespace and cluster rules selecting it
+	rules, err := effectiveRules(ctx, n.Client, targetObj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		ok, err := ruleMatches(rule, targetObj)
 		if err != nil {
 			logger.Error(err, "Invalid label selector", "rule", rule.Name)
 			continue
 		}
-		if !selector.Matches(labels.Set(targetObj.GetLabels())) {
-			continue
+		if ok {
+			matched = append(matched, rule)
 		}
+	}
+	span.SetAttributes(tracing.MatchedRulesKey.Int(len(matched)))
+	return matched, nil
+}
+
+// notifyRule sends the notifications of rule for status. It returns how long
+// to wait before notifications held back by quiet hours, waiting for
+// escalation or to be retried after failing are due, or zero if none are.
+func (n *Notifier) notifyRule(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, status string) time.Duration {
+	ctx, span := tracing.Start(ctx, "NotifyRule", tracing.RuleKey.String(client.ObjectKeyFromObject(&rule).String()))
+	defer span.End()
+	logger := log.FromContext(ctx)
 
-		// Check Notification Config
-		for _, note := range rule.Spec.Notifications {
-			if strings.EqualFold(note.Status, status) {
-				if err := n.ResolveAndSend(ctx, triggerObj, targetObj, rule, note); err != nil {
-					logger.Error(err, "Failed to send notification", "rule", rule.Name)
+	if strings.EqualFold(status, statusSucceeded) && !n.dryRun(rule) {
+		n.resolveIncidents(ctx, triggerObj, targetObj, rule)
+	}
+	marker := sentMarker(rule, status)
+	if slices.Contains(sentMarkers(triggerObj), marker) {
+		return 0
+	}
+
+	var requeueAfter time.Duration
+	// Only notifications all of whose deliveries were sent are marked as sent.
+	matched, allSent := false, !n.dryRun(rule)
+	// Check Notification Config
+	for _, note := range rule.Spec.Notifications {
+		if !strings.EqualFold(note.Status, status) {
+			continue
+		}
+		matched = true
+		configured := note
+		held, err := n.applyQuietHours(&note)
+		if err != nil {
+			logger.Error(err, "Invalid quiet hours", "rule", rule.Name)
+			allSent = false
+			continue
+		}
+		if held > 0 {
+			allSent = false
+			// Reconciles until the quiet hours end report the deferral once.
+			now := n.now()
+			key := runKey(triggerObj) + "/" + client.ObjectKeyFromObject(&rule).String() + "/" + note.Status
+			if n.deferrals.first(key, now, now.Add(held)) {
+				logger.Info("Deferring notification during quiet hours", "rule", rule.Name, "status", note.Status, "after", held)
+				n.reportDecision(ctx, triggerObj, targetObj, rule, note, nil, SuppressedQuietHours, nil)
+			}
+			if requeueAfter == 0 || held < requeueAfter {
+				requeueAfter = held
+			}
+			continue
+		}
+		if note.Escalation != nil && !n.dryRun(rule) {
+			// Escalations keep their own state on the Job or Workflow.
+			allSent = false
+			due, err := n.sendWithEscalation(ctx, triggerObj, targetObj, rule, note)
+			if err != nil {
+				logger.Error(err, "Failed to send notification", "rule", rule.Name)
+			}
+			if due > 0 && (requeueAfter == 0 || due < requeueAfter) {
+				requeueAfter = due
+			}
+			continue
+		}
+		for _, d := range deliveries(rule, note) {
+			// Notifications are recorded with the channel they are configured
+			// with, not the one quiet hours reroute them to, so that they are
+			// not sent again once the quiet hours end.
+			recorded := d.note
+			if d.destination == nil {
+				recorded.Channel = configured.Channel
+			}
+			if n.delivered(triggerObj, rule, recorded, d.destination) {
+				continue
+			}
+			var posted postedMessage
+			var err error
+			if d.sink != "" {
+				err = n.SendToSink(ctx, triggerObj, targetObj, d.rule, d.note, d.sink)
+				if err != nil {
+					logger.Error(err, "Failed to send notification", "rule", rule.Name, "sink", d.sink)
+				}
+			} else {
+				posted, err = n.resolveAndPost(ctx, triggerObj, targetObj, d.rule, d.note)
+				if err != nil {
+					logger.Error(err, "Failed to send notification", "rule", rule.Name, "slackConfig", d.rule.Spec.SlackConfigRef.Name, "channel", d.note.Channel)
+				}
+			}
+			n.recordDecision(ctx, triggerObj, targetObj, rule, recorded, d.destination, posted, err)
+			if err == nil {
+				continue
+			}
+			allSent = false
+			// Notifications blocked by a policy stay blocked until it changes.
+			if !errors.Is(err, policy.ErrViolation) {
+				backoff := retryBackoff(attempts(triggerObj, rule, recorded, d.destination) + 1)
+				if requeueAfter == 0 || backoff < requeueAfter {
+					requeueAfter = backoff
 				}
 			}
 		}
 	}
+	if matched && allSent {
+		n.markSent(ctx, triggerObj, marker)
+	}
+	return requeueAfter
+}
+
+// observeRun records the outcome and duration of a finished run in the job
+// metrics, with the duration shown in its notifications and the time it
+// finished, or the current time if that is unknown.
+func (n *Notifier) observeRun(triggerObj client.Object, targetObj client.Object, status string) {
+	if strings.EqualFold(status, "Running") || strings.EqualFold(status, "Pending") || status == "" {
+		return
+	}
+	run := metrics.Run{
+		UID:       runKey(triggerObj),
+		Namespace: targetObj.GetNamespace(),
+		Kind:      targetKind(targetObj),
+		Name:      targetObj.GetName(),
+		Status:    status,
+		Time:      n.now(),
+	}
+	if finished := runFinishTime(triggerObj); finished != nil {
+		run.Time = finished.Time
+	}
+	for _, f := range n.buildFields(triggerObj, targetObj, status) {
+		if f.Title == "Duration" && f.Value != "" {
+			run.Duration, _ = time.ParseDuration(f.Value)
+		}
+	}
+	metrics.ObserveRun(run)
+}
+
+// runFinishTime returns when a Job or Workflow finished, or nil if it has not
+// or the time is unknown.
+func runFinishTime(triggerObj client.Object) *metav1.Time {
+	switch obj := triggerObj.(type) {
+	case *batchv1.Job:
+		if obj.Status.CompletionTime != nil {
+			return obj.Status.CompletionTime
+		}
+		for _, cond := range obj.Status.Conditions {
+			if (cond.Type == batchv1.JobFailed || cond.Type == batchv1.JobComplete) && cond.Status == corev1.ConditionTrue {
+				return &cond.LastTransitionTime
+			}
+		}
+	case *argov1alpha1.Workflow:
+		if !obj.Status.FinishedAt.IsZero() {
+			return &obj.Status.FinishedAt
+		}
+	}
 	return nil
 }
 
+// targetKind returns CronWorkflow for a CronWorkflow and CronJob otherwise.
+func targetKind(targetObj client.Object) string {
+	if _, ok := targetObj.(*argov1alpha1.CronWorkflow); ok {
+		return "CronWorkflow"
+	}
+	return "CronJob"
+}
+
+// runKey identifies a Job or Workflow by its UID, or its namespace and name without one.
+func runKey(triggerObj client.Object) string {
+	if uid := triggerObj.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return triggerObj.GetNamespace() + "/" + triggerObj.GetName()
+}
+
+// ruleMatches reports whether the rule targets targetObj by kind and labels.
+func ruleMatches(rule notificationv1alpha1.SlackNotificationRule, targetObj client.Object) (bool, error) {
+	// Check Target Resource
+	switch rule.Spec.TargetResource {
+	case "CronJob":
+		if _, ok := targetObj.(*batchv1.CronJob); !ok {
+			return false, nil
+		}
+	case "CronWorkflow":
+		if _, ok := targetObj.(*argov1alpha1.CronWorkflow); !ok {
+			return false, nil
+		}
+	}
+
+	// Check Labels
+	selector, err := metav1.LabelSelectorAsSelector(&rule.Spec.LabelSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(targetObj.GetLabels())), nil
+}
+
+// applyQuietHours evaluates the quiet hours of a notification. Rerouted
+// notifications get their channel replaced; deferred notifications return how
+// long to hold them back. Critical notifications are never held or rerouted.
+func (n *Notifier) applyQuietHours(note *notificationv1alpha1.NotificationRule) (time.Duration, error) {
+	if note.Critical || note.QuietHours == nil {
+		return 0, nil
+	}
+
+	now := n.now()
+	until, quiet, err := quietUntil(note.QuietHours, now)
+	if err != nil || !quiet {
+		return 0, err
+	}
+
+	if note.QuietHours.Action == QuietHoursActionReroute {
+		note.Channel = note.QuietHours.Channel
+		return 0, nil
+	}
+	return until.Sub(now), nil
+}
+
+// recordDelivery counts a notification of rule in its status as sent, or as
+// failed if sendErr is set, and for dest as well unless it is nil. record is
+// added to the history of the rule unless it is nil. Failing to record it
+// does not fail the notification.
+func (n *Notifier) recordDelivery(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, dest *notificationv1alpha1.DestinationStatus, record *notificationv1alpha1.NotificationRecord, sendErr error) {
+	now := metav1.NewTime(n.now())
+	err := n.updateRuleStatus(ctx, rule, func(status *notificationv1alpha1.SlackNotificationRuleStatus) {
+		if sendErr != nil {
+			status.FailedCount++
+		} else {
+			status.SentCount++
+			status.LastNotificationTime = &now
+		}
+		if dest != nil {
+			recordDestination(status, *dest, now, sendErr)
+		}
+		if record != nil {
+			addRecord(status, rule.Spec.History, *record)
+		}
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record notification in rule status", "rule", rule.Name)
+	}
+}
+
+// updateRuleStatus applies update to the latest status of rule, which may be
+// the view of a ClusterSlackNotificationRule, retrying on conflicts.
+func (n *Notifier) updateRuleStatus(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, update func(*notificationv1alpha1.SlackNotificationRuleStatus)) error {
+	key := client.ObjectKeyFromObject(&rule)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if isClusterRule(rule) {
+			var latest notificationv1alpha1.ClusterSlackNotificationRule
+			if err := n.Client.Get(ctx, key, &latest); err != nil {
+				return err
+			}
+			update(&latest.Status)
+			return n.Client.Status().Update(ctx, &latest)
+		}
+		var latest notificationv1alpha1.SlackNotificationRule
+		if err := n.Client.Get(ctx, key, &latest); err != nil {
+			return err
+		}
+		update(&latest.Status)
+		return n.Client.Status().Update(ctx, &latest)
+	})
+}
+
+func (n *Notifier) now() time.Time {
+	if n.Clock == nil {
+		return time.Now()
+	}
+	return n.Clock.Now()
+}
+
 func (n *Notifier) ResolveAndSend(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) error {
-	// Get SlackConfig
-	var config notificationv1alpha1.SlackConfig
-	// SlackConfigRef is a LocalObjectReference, so it must be in the same namespace as the Rule
-	ns := rule.Namespace
+	_, err := n.resolveAndPost(ctx, triggerObj, targetObj, rule, note)
+	return err
+}
+
+// resolveAndPost sends like ResolveAndSend and returns the Slack message it
+// posted, which is unknown for webhooks.
+func (n *Notifier) resolveAndPost(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) (_ postedMessage, err error) {
+	ref := qualifiedConfigRef(rule)
+	ctx, span := tracing.Start(ctx, "SendSlack", tracing.SlackConfigKey.String(ref.Kind+"/"+client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}.String()))
+	defer func() { tracing.End(span, err) }()
+
+	dest, err := n.resolveDestination(ctx, rule, note)
+	if err != nil {
+		return postedMessage{}, err
+	}
+	span.SetAttributes(tracing.ChannelKey.String(dest.channel))
+
+	unstructuredData, err := toTemplateData(triggerObj)
+	if err != nil {
+		return postedMessage{}, err
+	}
+	fields := n.buildFields(triggerObj, targetObj, note.Status)
+	if err := n.checkPolicy(ctx, rule, note.Status, dest.channel, messageTexts(note.Title, unstructuredData, fields)...); err != nil {
+		return postedMessage{}, err
+	}
 
-	if err := n.Client.Get(ctx, types.NamespacedName{Name: rule.Spec.SlackConfigRef.Name, Namespace: ns}, &config); err != nil {
-		return fmt.Errorf("failed to get SlackConfig: %w", err)
+	var posted postedMessage
+	if n.dryRun(rule) {
+		title, err := slack.RenderTitle(note.Title, unstructuredData)
+		if err != nil {
+			return postedMessage{}, err
+		}
+		log.FromContext(ctx).Info("Dry run: notification not sent", "rule", rule.Name, "status", note.Status, "channel", dest.channel, "title", title, "fields", dryRunFields(fields))
+		return postedMessage{}, nil
+	}
+	if len(note.Actions) > 0 && dest.interactive() {
+		ref := newNotificationRef(triggerObj, targetObj, rule, note)
+		value, err := ref.encode()
+		if err != nil {
+			return postedMessage{}, err
+		}
+		posted.channelID, posted.ts, err = n.SlackClient.SendWithActions(ctx, dest.token, dest.channel, note.Title, statusColor(note.Status), fields, unstructuredData, notificationButtons(note, ref, value, false))
+		return posted, err
+	}
+	posted.channelID, posted.ts, err = n.SlackClient.Send(ctx, dest.webhookURL, dest.token, dest.channel, note.Title, statusColor(note.Status), fields, unstructuredData)
+	return posted, err
+}
+
+// dryRun reports whether the notifications of rule are rendered without
+// being sent.
+func (n *Notifier) dryRun(rule notificationv1alpha1.SlackNotificationRule) bool {
+	return n.DryRun || rule.Spec.DryRun
+}
+
+// dryRunFields returns fields as the map logged for a dry run.
+func dryRunFields(fields []goslack.AttachmentField) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[f.Title] = f.Value
+	}
+	return m
+}
+
+// checkPolicy returns an error if the channel policies of a rule forbid
+// posting a notification with the rendered texts to channel, and records a
+// PolicyViolation event on the rule. Cluster rules are not subject to
+// channel policies.
+func (n *Notifier) checkPolicy(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, status, channel string, texts ...string) error {
+	if isClusterRule(rule) {
+		return nil
+	}
+	p, err := policy.ForRule(ctx, n.Client, &rule)
+	if err != nil {
+		return err
+	}
+	err = p.CheckChannel(channel)
+	if err == nil {
+		err = p.CheckMentions(texts...)
+	}
+	if err != nil && n.Recorder != nil {
+		n.Recorder.Eventf(&rule, corev1.EventTypeWarning, ReasonPolicyViolation, "%s notification not sent: %v", status, err)
+	}
+	return err
+}
+
+// messageTexts returns the texts of a Slack message titled titleTmpl with
+// fields, as checked for broadcast mentions. A title that does not render is
+// left out, since it fails the notification when it is sent.
+func messageTexts(titleTmpl string, data any, fields []goslack.AttachmentField) []string {
+	texts := make([]string, 0, len(fields)+1)
+	if title, err := slack.RenderTitle(titleTmpl, data); err == nil {
+		texts = append(texts, title)
+	}
+	for _, f := range fields {
+		texts = append(texts, f.Value)
 	}
+	return texts
+}
 
+// destination is a SlackConfig resolved for a single notification.
+type destination struct {
+	config     notificationv1alpha1.SlackConfig
+	webhookURL string
+	token      string
+	channel    string
+}
+
+func (n *Notifier) resolveDestination(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) (*destination, error) {
+	config, err := n.getSlackConfig(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
 	// Resolve Credentials
-	webhookURL := ""
-	token := ""
+	dest := &destination{config: *config}
 	if config.Spec.AuthType == "Webhook" {
 		if config.Spec.WebhookURLSecretRef != nil {
-			val, err := n.getSecretValue(ctx, ns, config.Spec.WebhookURLSecretRef)
+			val, err := n.getSecretValue(ctx, config, config.Spec.WebhookURLSecretRef)
 			if err != nil {
-				return fmt.Errorf("failed to get webhook secret: %w", err)
+				return nil, fmt.Errorf("failed to get webhook secret: %w", err)
 			}
-			webhookURL = val
+			dest.webhookURL = val
 		}
 	} else if config.Spec.AuthType == "Token" {
 		if config.Spec.TokenSecretRef != nil {
-			val, err := n.getSecretValue(ctx, ns, config.Spec.TokenSecretRef)
+			val, err := n.getSecretValue(ctx, config, config.Spec.TokenSecretRef)
 			if err != nil {
-				return fmt.Errorf("failed to get token secret: %w", err)
+				return nil, fmt.Errorf("failed to get token secret: %w", err)
 			}
-			token = val
+			dest.token = val
 		}
 	}
 
-	channel := config.Spec.Channel
+	dest.channel = config.Spec.Channel
 	if note.Channel != "" {
-		channel = note.Channel
+		dest.channel = note.Channel
 	}
+	return dest, nil
+}
 
-	// Convert to Unstructured map for template
+// getSlackConfig returns the SlackConfig a rule references. A SlackConfig in
+// another namespace must be granted to the namespace of the rule; a
+// ClusterSlackConfig is returned as a SlackConfig in ClusterResourceNamespace.
+func (n *Notifier) getSlackConfig(ctx context.Context, rule notificationv1alpha1.SlackNotificationRule) (*notificationv1alpha1.SlackConfig, error) {
+	return resolveSlackConfig(ctx, n.Client, n.ClusterResourceNamespace, rule)
+}
+
+// toTemplateData converts the trigger object to the unstructured map templates are rendered against.
+func toTemplateData(triggerObj client.Object) (map[string]any, error) {
 	unstructuredData, err := runtime.DefaultUnstructuredConverter.ToUnstructured(triggerObj)
 	if err != nil {
-		return fmt.Errorf("failed to convert object to unstructured: %w", err)
+		return nil, fmt.Errorf("failed to convert object to unstructured: %w", err)
 	}
+	return unstructuredData, nil
+}
 
-	// Determine Color
-	color := "warning"
-	switch strings.ToLower(note.Status) {
+func statusColor(status string) string {
+	switch strings.ToLower(status) {
 	case "succeeded", "running":
-		color = "good" // Green
+		return "good" // Green
 	case "failed", "error":
-		color = "danger" // Red
+		return "danger" // Red
 	}
-
-	fields := n.buildFields(triggerObj, targetObj, note.Status)
-	return n.SlackClient.Send(ctx, webhookURL, token, channel, note.Title, color, fields, unstructuredData)
+	return "warning"
 }
 
 func (n *Notifier) buildFields(triggerObj client.Object, targetObj client.Object, status string) []goslack.AttachmentField {
@@ -149,15 +567,8 @@ func (n *Notifier) buildFields(triggerObj client.Object, targetObj client.Object
 	if job, ok := triggerObj.(*batchv1.Job); ok {
 		if job.Status.StartTime != nil {
 			endTime := metav1.Now()
-			if job.Status.CompletionTime != nil {
-				endTime = *job.Status.CompletionTime
-			} else {
-				for _, cond := range job.Status.Conditions {
-					if (cond.Type == batchv1.JobFailed || cond.Type == batchv1.JobComplete) && cond.Status == corev1.ConditionTrue {
-						endTime = cond.LastTransitionTime
-						break
-					}
-				}
+			if finished := runFinishTime(job); finished != nil {
+				endTime = *finished
 			}
 			d := endTime.Time.Sub(job.Status.StartTime.Time)
 			duration = d.Round(time.Second).String()
@@ -173,8 +584,8 @@ func (n *Notifier) buildFields(triggerObj client.Object, targetObj client.Object
 	} else if wf, ok := triggerObj.(*argov1alpha1.Workflow); ok {
 		if !wf.Status.StartedAt.IsZero() {
 			endTime := metav1.Now()
-			if !wf.Status.FinishedAt.IsZero() {
-				endTime = wf.Status.FinishedAt
+			if finished := runFinishTime(wf); finished != nil {
+				endTime = *finished
 			}
 			d := endTime.Time.Sub(wf.Status.StartedAt.Time)
 			duration = d.Round(time.Second).String()
@@ -223,14 +634,11 @@ func (n *Notifier) buildFields(triggerObj client.Object, targetObj client.Object
 	return fields
 }
 
-func (n *Notifier) getSecretValue(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error) {
-	var secret corev1.Secret
-	if err := n.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret); err != nil {
-		return "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
-	}
-	val, ok := secret.Data[ref.Key]
-	if !ok {
-		return "", fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+// getSecretValue reads a credential of config with the provider it selects.
+func (n *Notifier) getSecretValue(ctx context.Context, config *notificationv1alpha1.SlackConfig, ref *corev1.SecretKeySelector) (string, error) {
+	provider, err := credentialProvider(config, n.ClusterResourceNamespace)
+	if err != nil {
+		return "", err
 	}
-	return string(val), nil
+	return credentialResolver(n.CredentialResolver, n.Client).Get(ctx, provider, config.Namespace, ref)
 }
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// Kinds of the objects triggering notifications.
+const (
+	KindJob      = "Job"
+	KindWorkflow = "Workflow"
+)
+
+// Preview is a notification rendered as it would be sent, without sending it.
+type Preview struct {
+	// Rule is the namespace and name of a SlackNotificationRule, or the name
+	// of a ClusterSlackNotificationRule.
+	Rule string
+	// Status is the status the notification is sent for.
+	Status string
+	// Destination names the channel and Slack configuration, or the
+	// SinkConfig, the notification is sent to.
+	Destination string
+	// Slack is the message posted to Slack, with the channel it is posted
+	// to, or nil for a SinkConfig.
+	Slack *goslack.WebhookMessage
+	// Sink is the message delivered to a SinkConfig, without the object it
+	// is about.
+	Sink *sink.Message
+	// Err is why the notification would not be sent, e.g. a channel policy
+	// forbidding it. The message is still rendered if it can be.
+	Err error
+}
+
+// LoadTrigger returns the Job or Workflow named by key, the CronJob or
+// CronWorkflow owning it and the status notifications are sent for. Their
+// kinds are set like on the objects the controller reads from its cache.
+func LoadTrigger(ctx context.Context, c client.Reader, kind string, key client.ObjectKey) (triggerObj client.Object, targetObj client.Object, status string, err error) {
+	switch kind {
+	case KindJob:
+		var job batchv1.Job
+		if err := c.Get(ctx, key, &job); err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get Job: %w", err)
+		}
+		var cronJob batchv1.CronJob
+		if err := getOwner(ctx, c, &job, "CronJob", &cronJob); err != nil {
+			return nil, nil, "", err
+		}
+		job.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+		cronJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("CronJob"))
+		return &job, &cronJob, jobStatus(&job), nil
+	case KindWorkflow:
+		var wf argov1alpha1.Workflow
+		if err := c.Get(ctx, key, &wf); err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get Workflow: %w", err)
+		}
+		var cronWf argov1alpha1.CronWorkflow
+		if err := getOwner(ctx, c, &wf, "CronWorkflow", &cronWf); err != nil {
+			return nil, nil, "", err
+		}
+		wf.SetGroupVersionKind(argov1alpha1.SchemeGroupVersion.WithKind("Workflow"))
+		cronWf.SetGroupVersionKind(argov1alpha1.SchemeGroupVersion.WithKind("CronWorkflow"))
+		return &wf, &cronWf, string(wf.Status.Phase), nil
+	}
+	return nil, nil, "", fmt.Errorf("unknown kind %q: must be %s or %s", kind, KindJob, KindWorkflow)
+}
+
+// getOwner gets the owner of obj of the given kind into owner.
+func getOwner(ctx context.Context, c client.Reader, obj client.Object, kind string, owner client.Object) error {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind != kind {
+			continue
+		}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}, owner); err != nil {
+			return fmt.Errorf("failed to get %s: %w", kind, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not owned by a %s", obj.GetName(), kind)
+}
+
+// Preview renders the notifications the rules matching targetObj would send
+// about triggerObj in status, without reading credentials or sending them.
+// Quiet hours are not applied.
+func (n *Notifier) Preview(ctx context.Context, triggerObj client.Object, targetObj client.Object, status string) ([]Preview, error) {
+	rules, err := n.matchingRules(ctx, targetObj)
+	if err != nil {
+		return nil, err
+	}
+	var previews []Preview
+	for _, rule := range rules {
+		for _, note := range rule.Spec.Notifications {
+			if !strings.EqualFold(note.Status, status) {
+				continue
+			}
+			ds := deliveries(rule, note)
+			if note.Escalation != nil {
+				// Escalated notifications ignore their destinations.
+				ds = []delivery{{rule: rule, note: note}}
+			}
+			for _, d := range ds {
+				previews = append(previews, n.preview(ctx, triggerObj, targetObj, rule, d))
+			}
+		}
+	}
+	return previews, nil
+}
+
+// preview renders a single delivery of a notification of rule.
+func (n *Notifier) preview(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, d delivery) Preview {
+	p := Preview{
+		Rule:        client.ObjectKeyFromObject(&rule).String(),
+		Status:      d.note.Status,
+		Destination: describeDestination(d.rule, d.note, d.destination),
+	}
+	if isClusterRule(rule) {
+		p.Rule = rule.Name
+	}
+	if d.sink != "" {
+		p.Sink, p.Err = n.sinkMessage(triggerObj, targetObj, d.rule, d.note)
+		if p.Sink != nil {
+			p.Sink.Object = nil
+		}
+		return p
+	}
+
+	config, err := n.getSlackConfig(ctx, d.rule)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	channel := config.Spec.Channel
+	if d.note.Channel != "" {
+		channel = d.note.Channel
+	}
+	named := d.note
+	named.Channel = channel
+	p.Destination = describeDestination(d.rule, named, d.destination)
+	data, err := toTemplateData(triggerObj)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	title, err := slack.RenderTitle(d.note.Title, data)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	fields := n.buildFields(triggerObj, targetObj, d.note.Status)
+	p.Err = n.checkPolicy(ctx, d.rule, d.note.Status, channel, messageTexts(d.note.Title, data, fields)...)
+
+	color := statusColor(d.note.Status)
+	ack := d.note.Escalation != nil
+	// Whether the token is set is only known once it is read.
+	interactive := config.Spec.AuthType == "Token" && config.Spec.Interactivity != nil
+	if (ack || len(d.note.Actions) > 0) && interactive {
+		ref := newNotificationRef(triggerObj, targetObj, d.rule, d.note)
+		value, err := ref.encode()
+		if err != nil {
+			p.Err = errors.Join(p.Err, err)
+			return p
+		}
+		p.Slack = slack.NewMessageWithActions(channel, title, color, fields, notificationButtons(d.note, ref, value, ack))
+		return p
+	}
+	p.Slack = slack.NewMessage(channel, title, color, fields)
+	return p
+}
+
+// RuleMatch explains whether a rule selects a CronJob or CronWorkflow.
+type RuleMatch struct {
+	// Rule is the namespace and name of a SlackNotificationRule, or the name
+	// of a ClusterSlackNotificationRule.
+	Rule string
+	// Cluster is set for a ClusterSlackNotificationRule.
+	Cluster bool
+	// Matched is set if the rule selects the CronJob or CronWorkflow.
+	Matched bool
+	// Reason is why the rule does not select it.
+	Reason string
+	// Statuses are the statuses the rule sends notifications for.
+	Statuses []string
+}
+
+// Explain returns for every rule in the namespace of targetObj and every
+// cluster rule whether it selects targetObj, and why not.
+func (n *Notifier) Explain(ctx context.Context, targetObj client.Object) ([]RuleMatch, error) {
+	var rules notificationv1alpha1.SlackNotificationRuleList
+	if err := n.Client.List(ctx, &rules, client.InNamespace(targetObj.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	var clusterRules notificationv1alpha1.ClusterSlackNotificationRuleList
+	if err := n.Client.List(ctx, &clusterRules); err != nil {
+		return nil, fmt.Errorf("failed to list cluster rules: %w", err)
+	}
+
+	matches := make([]RuleMatch, 0, len(rules.Items)+len(clusterRules.Items))
+	for _, rule := range rules.Items {
+		m := RuleMatch{Rule: client.ObjectKeyFromObject(&rule).String(), Statuses: ruleStatuses(rule)}
+		m.Reason = ruleMismatch(rule, targetObj)
+		m.Matched = m.Reason == ""
+		matches = append(matches, m)
+	}
+	if len(clusterRules.Items) == 0 {
+		return matches, nil
+	}
+
+	var ns corev1.Namespace
+	nsErr := n.Client.Get(ctx, client.ObjectKey{Name: targetObj.GetNamespace()}, &ns)
+	if nsErr != nil && !apierrors.IsNotFound(nsErr) {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", targetObj.GetNamespace(), nsErr)
+	}
+	for i := range clusterRules.Items {
+		rule := clusterRuleView(&clusterRules.Items[i])
+		m := RuleMatch{Rule: rule.Name, Cluster: true, Statuses: ruleStatuses(rule)}
+		selector, err := metav1.LabelSelectorAsSelector(&clusterRules.Items[i].Spec.NamespaceSelector)
+		switch {
+		case apierrors.IsNotFound(nsErr):
+			m.Reason = fmt.Sprintf("namespace %s does not exist", targetObj.GetNamespace())
+		case err != nil:
+			m.Reason = fmt.Sprintf("invalid namespace selector: %v", err)
+		case !selector.Matches(labels.Set(ns.Labels)):
+			m.Reason = fmt.Sprintf("namespace %s with labels {%s} does not match the namespace selector {%s}", ns.Name, labels.Set(ns.Labels), selector)
+		default:
+			m.Reason = ruleMismatch(rule, targetObj)
+		}
+		m.Matched = m.Reason == ""
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// ruleStatuses returns the statuses rule sends notifications for.
+func ruleStatuses(rule notificationv1alpha1.SlackNotificationRule) []string {
+	statuses := make([]string, 0, len(rule.Spec.Notifications))
+	for _, note := range rule.Spec.Notifications {
+		statuses = append(statuses, note.Status)
+	}
+	return statuses
+}
+
+// ruleMismatch returns why rule does not target targetObj by kind and
+// labels, or an empty string if it does.
+func ruleMismatch(rule notificationv1alpha1.SlackNotificationRule, targetObj client.Object) string {
+	ok, err := ruleMatches(rule, targetObj)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("invalid label selector: %v", err)
+	case ok:
+		return ""
+	case rule.Spec.TargetResource != targetKind(targetObj):
+		return fmt.Sprintf("targets %ss, not %ss", rule.Spec.TargetResource, targetKind(targetObj))
+	}
+	selector, _ := metav1.LabelSelectorAsSelector(&rule.Spec.LabelSelector)
+	return fmt.Sprintf("labels {%s} do not match the label selector {%s}", labels.Set(targetObj.GetLabels()), selector)
+}
+
+// SendTest posts a sample notification through the SlackConfig or
+// ClusterSlackConfig ref, referenced from namespace, to channel or the
+// default channel of the configuration. It returns the channel ID and
+// timestamp of the message posted with token authentication.
+func (n *Notifier) SendTest(ctx context.Context, namespace string, ref notificationv1alpha1.SlackConfigReference, channel string) (string, string, error) {
+	rule := notificationv1alpha1.SlackNotificationRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec:       notificationv1alpha1.SlackNotificationRuleSpec{SlackConfigRef: ref},
+	}
+	note := notificationv1alpha1.NotificationRule{Status: statusSucceeded, Channel: channel}
+	dest, err := n.resolveDestination(ctx, rule, note)
+	if err != nil {
+		return "", "", err
+	}
+	fields := []goslack.AttachmentField{
+		{Title: "Namespace", Value: namespace, Short: true},
+		{Title: "SlackConfig", Value: ref.Name, Short: true},
+	}
+	return n.SlackClient.Send(ctx, dest.webhookURL, dest.token, dest.channel,
+		"Test notification from kubectl slack-notify", statusColor(statusSucceeded), fields, nil)
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+var _ = Describe("Preview", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx     context.Context
+		cronJob *batchv1.CronJob
+		job     *batchv1.Job
+		config  *notificationv1alpha1.SlackConfig
+		rule    *notificationv1alpha1.SlackNotificationRule
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cronJob = &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace, Labels: map[string]string{"app": "backup"}}}
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "backup-1", Namespace: namespace,
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "CronJob", Name: "backup", UID: "0b5c"}},
+			},
+			Status: batchv1.JobStatus{Failed: 1},
+		}
+		config = &notificationv1alpha1.SlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Spec:       notificationv1alpha1.SlackConfigSpec{AuthType: "Token", Channel: "#alerts"},
+		}
+		rule = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronJob",
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				LabelSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"app": "backup"}},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed"}},
+			},
+		}
+	})
+
+	newNotifier := func(objects ...client.Object) *Notifier {
+		c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(append(objects, cronJob, job, config)...).
+			Build()
+		return &Notifier{Client: c}
+	}
+
+	preview := func(notifier *Notifier) []Preview {
+		triggerObj, targetObj, status, err := LoadTrigger(ctx, notifier.Client, KindJob, client.ObjectKeyFromObject(job))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal("Failed"))
+		previews, err := notifier.Preview(ctx, triggerObj, targetObj, status)
+		Expect(err).NotTo(HaveOccurred())
+		return previews
+	}
+
+	It("renders the message a rule would send without reading its token", func() {
+		previews := preview(newNotifier(rule))
+		Expect(previews).To(HaveLen(1))
+		Expect(previews[0].Rule).To(Equal("team-a/backups"))
+		Expect(previews[0].Destination).To(Equal("#alerts via SlackConfig team-a/slack"))
+		Expect(previews[0].Err).NotTo(HaveOccurred())
+		Expect(previews[0].Slack.Channel).To(Equal("#alerts"))
+		Expect(previews[0].Slack.Text).To(Equal("backup-1 failed"))
+		Expect(previews[0].Slack.Blocks).To(BeNil())
+		Expect(previews[0].Slack.Attachments).To(HaveExactElements(HaveField("Color", "danger")))
+		Expect(previews[0].Slack.Attachments[0].Fields).To(ContainElement(goslack.AttachmentField{Title: "CronJob", Value: "backup", Short: true}))
+	})
+
+	It("renders the buttons of interactive notifications and the messages to sinks", func() {
+		config.Spec.Interactivity = &notificationv1alpha1.SlackInteractivity{}
+		rule.Spec.Notifications[0].Actions = []notificationv1alpha1.NotificationAction{notificationv1alpha1.ActionRerun}
+		rule.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+			{Channel: "#team-a"},
+			{SinkRef: &corev1.LocalObjectReference{Name: "teams"}},
+			{SlackConfigRef: &notificationv1alpha1.SlackConfigReference{Name: "slack"}},
+		}
+		previews := preview(newNotifier(rule))
+		Expect(previews).To(HaveLen(3))
+		Expect(previews[2].Destination).To(Equal("#alerts via SlackConfig team-a/slack"))
+
+		Expect(previews[0].Destination).To(Equal("#team-a via SlackConfig team-a/slack"))
+		Expect(previews[0].Slack.Blocks).NotTo(BeNil())
+		actions, ok := previews[0].Slack.Blocks.BlockSet[1].(*goslack.ActionBlock)
+		Expect(ok).To(BeTrue())
+		Expect(actions.Elements.ElementSet).To(HaveExactElements(HaveField("ActionID", slack.ActionRerun)))
+
+		Expect(previews[1].Destination).To(Equal("SinkConfig teams"))
+		Expect(previews[1].Slack).To(BeNil())
+		Expect(previews[1].Sink.Title).To(Equal("backup-1 failed"))
+		Expect(previews[1].Sink.Object).To(BeNil())
+	})
+
+	It("reports notifications channel policies forbid", func() {
+		config.Spec.ChannelPolicies = []notificationv1alpha1.ChannelPolicy{{AllowedChannels: []string{"#team-a-*"}}}
+		previews := preview(newNotifier(rule))
+		Expect(previews).To(HaveLen(1))
+		Expect(previews[0].Err).To(MatchError(ContainSubstring("#alerts")))
+		Expect(previews[0].Slack.Text).To(Equal("backup-1 failed"))
+	})
+
+	It("reports notifications that do not render", func() {
+		rule.Spec.Notifications[0].Title = "{{ .metadata.name"
+		previews := preview(newNotifier(rule))
+		Expect(previews).To(HaveLen(1))
+		Expect(previews[0].Err).To(HaveOccurred())
+		Expect(previews[0].Slack).To(BeNil())
+	})
+
+	It("explains why rules do not select a CronJob", func() {
+		other := rule.DeepCopy()
+		other.Name = "etl"
+		other.Spec.TargetResource = "CronWorkflow"
+		unlabeled := rule.DeepCopy()
+		unlabeled.Name = "reports"
+		unlabeled.Spec.LabelSelector.MatchLabels = map[string]string{"app": "reports"}
+		clusterRule := &notificationv1alpha1.ClusterSlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "production"},
+			Spec: notificationv1alpha1.ClusterSlackNotificationRuleSpec{
+				NamespaceSelector:         metav1.LabelSelector{MatchLabels: map[string]string{"env": "production"}},
+				SlackNotificationRuleSpec: rule.Spec,
+			},
+		}
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: map[string]string{"env": "staging"}}}
+		notifier := newNotifier(rule, other, unlabeled, clusterRule, ns)
+
+		matches, err := notifier.Explain(ctx, cronJob)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(matches).To(ConsistOf(
+			RuleMatch{Rule: "team-a/backups", Matched: true, Statuses: []string{"Failed"}},
+			RuleMatch{Rule: "team-a/etl", Reason: "targets CronWorkflows, not CronJobs", Statuses: []string{"Failed"}},
+			RuleMatch{Rule: "team-a/reports", Reason: "labels {app=backup} do not match the label selector {app=reports}", Statuses: []string{"Failed"}},
+			RuleMatch{Rule: "production", Cluster: true, Reason: "namespace team-a with labels {env=staging} does not match the namespace selector {env=production}", Statuses: []string{"Failed"}},
+		))
+	})
+
+	It("sends a test notification through a SlackConfig", func() {
+		config.Spec.TokenSecretRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"}
+		notifier := newNotifier(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Data:       map[string][]byte{"token": []byte("xoxb-test")},
+		})
+		slackFk := &fakeSlackClient{}
+		notifier.SlackClient = slackFk
+
+		channelID, ts, err := notifier.SendTest(ctx, namespace, notificationv1alpha1.SlackConfigReference{Name: "slack"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(channelID).To(Equal("C#alerts"))
+		Expect(ts).NotTo(BeEmpty())
+		Expect(slackFk.sent).To(ConsistOf(sentMessage{Token: "xoxb-test", Channel: "#alerts", Title: "Test notification from kubectl slack-notify"}))
+	})
+})
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+const (
+	QuietHoursActionDefer   = "Defer"
+	QuietHoursActionReroute = "Reroute"
+)
+
+// maxChainedWindows bounds how many back-to-back windows quietUntil follows
+// when looking for the end of a quiet period.
+const maxChainedWindows = 32
+
+var weekdays = map[notificationv1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// quietUntil reports whether now falls into one of the quiet windows and, if so,
+// the first time after now at which no window is active. Overlapping or
+// back-to-back windows (e.g. weeknights followed by a weekend) are merged.
+func quietUntil(qh *notificationv1alpha1.QuietHours, now time.Time) (time.Time, bool, error) {
+	loc := time.UTC
+	if qh.TimeZone != "" {
+		l, err := time.LoadLocation(qh.TimeZone)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time zone %q: %w", qh.TimeZone, err)
+		}
+		loc = l
+	}
+
+	t := now.In(loc)
+	quiet := false
+	for range maxChainedWindows {
+		end, active, err := activeWindowEnd(qh.Windows, t)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if !active {
+			break
+		}
+		quiet = true
+		t = end
+	}
+	return t, quiet, nil
+}
+
+// activeWindowEnd returns the latest end of the windows active at t.
+func activeWindowEnd(windows []notificationv1alpha1.TimeWindow, t time.Time) (time.Time, bool, error) {
+	var latest time.Time
+	active := false
+	for _, w := range windows {
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		length := end - start
+		if length <= 0 {
+			length += 24 * time.Hour
+		}
+
+		// A window active at t started either today or, if it spans midnight, yesterday.
+		for _, offset := range []int{0, -1} {
+			day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+			if !windowOnDay(w, day.Weekday()) {
+				continue
+			}
+			// Wall-clock times, so that windows keep their hours on days
+			// daylight saving time starts or ends.
+			from := clockOn(day, start)
+			to := clockOn(day, start+length)
+			if !t.Before(from) && t.Before(to) && to.After(latest) {
+				latest = to
+				active = true
+			}
+		}
+	}
+	return latest, active, nil
+}
+
+// clockOn returns the time offset from the midnight of day on the clock.
+func clockOn(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+}
+
+func windowOnDay(w notificationv1alpha1.TimeWindow, day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses an HH:MM string into the offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// deferrals remembers the notifications held back by quiet hours that were
+// reported, until their quiet hours end.
+type deferrals struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// first reports whether the notification key held back until until has not
+// been reported yet, and if so remembers it.
+func (d *deferrals) first(key string, now time.Time, until time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if u, ok := d.until[key]; ok && now.Before(u) {
+		return false
+	}
+	if d.until == nil {
+		d.until = map[string]time.Time{}
+	}
+	if len(d.until) >= maxLimitedEvents {
+		for k, u := range d.until {
+			if !now.Before(u) {
+				delete(d.until, k)
+			}
+		}
+	}
+	d.until[key] = until
+	return true
+}
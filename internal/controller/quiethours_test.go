@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("Quiet hours", func() {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	// Weeknights from 19:00 to 09:00 and whole weekends in Asia/Tokyo.
+	quietHours := &notificationv1alpha1.QuietHours{
+		TimeZone: "Asia/Tokyo",
+		Windows: []notificationv1alpha1.TimeWindow{
+			{Days: []notificationv1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "19:00", End: "09:00"},
+			{Days: []notificationv1alpha1.Weekday{"Sat", "Sun"}, Start: "00:00", End: "00:00"},
+		},
+	}
+
+	It("is not quiet during business hours", func() {
+		// Wednesday 10:30 JST
+		_, quiet, err := quietUntil(quietHours, time.Date(2025, 1, 15, 10, 30, 0, 0, tokyo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quiet).To(BeFalse())
+	})
+
+	It("is quiet on a weeknight until the next morning", func() {
+		// Wednesday 23:00 JST
+		until, quiet, err := quietUntil(quietHours, time.Date(2025, 1, 15, 23, 0, 0, 0, tokyo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quiet).To(BeTrue())
+		Expect(until).To(BeTemporally("==", time.Date(2025, 1, 16, 9, 0, 0, 0, tokyo)))
+	})
+
+	It("merges a Friday night into the weekend", func() {
+		// Friday 20:00 JST is quiet until Monday 00:00, which starts no window.
+		until, quiet, err := quietUntil(quietHours, time.Date(2025, 1, 17, 20, 0, 0, 0, tokyo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quiet).To(BeTrue())
+		Expect(until).To(BeTemporally("==", time.Date(2025, 1, 20, 0, 0, 0, 0, tokyo)))
+	})
+
+	It("evaluates windows in the configured time zone", func() {
+		// Wednesday 12:00 UTC is 21:00 JST.
+		_, quiet, err := quietUntil(quietHours, time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quiet).To(BeTrue())
+	})
+
+	It("keeps the hours of windows on the day daylight saving time starts", func() {
+		newYork, err := time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+		nights := &notificationv1alpha1.QuietHours{
+			TimeZone: "America/New_York",
+			Windows:  []notificationv1alpha1.TimeWindow{{Start: "22:00", End: "07:00"}},
+		}
+		// Clocks go forward at 02:00 on Sunday, March 9 2025.
+		until, quiet, err := quietUntil(nights, time.Date(2025, 3, 8, 23, 0, 0, 0, newYork))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quiet).To(BeTrue())
+		Expect(until).To(BeTemporally("==", time.Date(2025, 3, 9, 7, 0, 0, 0, newYork)))
+	})
+
+	It("rejects unknown time zones", func() {
+		_, _, err := quietUntil(&notificationv1alpha1.QuietHours{TimeZone: "Mars/Olympus"}, time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("when applied to a notification", func() {
+		notifier := &Notifier{
+			// Wednesday 23:00 JST
+			Clock: clocktesting.NewFakePassiveClock(time.Date(2025, 1, 15, 23, 0, 0, 0, tokyo)),
+		}
+
+		It("defers non-critical notifications", func() {
+			note := notificationv1alpha1.NotificationRule{Status: "Failed", QuietHours: quietHours}
+			held, err := notifier.applyQuietHours(&note)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(Equal(10 * time.Hour))
+		})
+
+		It("reroutes notifications to the low-priority channel", func() {
+			reroute := quietHours.DeepCopy()
+			reroute.Action = QuietHoursActionReroute
+			reroute.Channel = "#low-priority"
+			note := notificationv1alpha1.NotificationRule{Status: "Failed", Channel: "#team", QuietHours: reroute}
+			held, err := notifier.applyQuietHours(&note)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeZero())
+			Expect(note.Channel).To(Equal("#low-priority"))
+		})
+
+		It("lets critical notifications through", func() {
+			note := notificationv1alpha1.NotificationRule{Status: "Failed", Critical: true, QuietHours: quietHours}
+			held, err := notifier.applyQuietHours(&note)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeZero())
+		})
+	})
+})
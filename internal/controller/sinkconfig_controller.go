@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// sinkConfigSecretIndex indexes SinkConfigs by the names of the Secrets they reference.
+const sinkConfigSecretIndex = "spec.secretRefs"
+
+// SinkConfigReconciler reconciles a SinkConfig object
+type SinkConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// CredentialResolver reads the Secrets of SinkConfigs. Defaults to
+	// reading Secrets, without caching.
+	CredentialResolver *credentials.Resolver
+}
+
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=sinkconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=sinkconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=sinkconfigs/finalizers,verbs=update
+
+// Reconcile verifies that the Secrets referenced by a SinkConfig exist, that
+// its URL Secret contains an http or https URL, or its key Secret a key for an
+// incident sink, that the password of an email sink can be read and that its
+// body template parses. The outcome is reported with the Ready and Degraded conditions.
+// Nothing is posted to the sink, which may not tell verification requests
+// from notifications.
+func (r *SinkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var config notificationv1alpha1.SinkConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := config.Status.DeepCopy()
+
+	message, err := r.verify(ctx, &config)
+	err = setVerifyConditions(ctx, &config.Status.Conditions, config.Generation, message, err)
+
+	if !equality.Semantic.DeepEqual(status, &config.Status) {
+		if updateErr := r.Status().Update(ctx, &config); updateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update SinkConfig status: %w", updateErr)
+		}
+	}
+	return ctrl.Result{}, err
+}
+
+// verify checks the SinkConfig and describes it when it is usable. Problems
+// with the SinkConfig itself are returned as *configError.
+func (r *SinkConfigReconciler) verify(ctx context.Context, config *notificationv1alpha1.SinkConfig) (string, error) {
+	if errs := validation.ValidateSinkConfigSpec(&config.Spec, field.NewPath("spec")); len(errs) > 0 {
+		return "", invalidConfig(ReasonInvalidSpec, "%s", errs.ToAggregate().Error())
+	}
+	if webhook := config.Spec.Webhook; webhook != nil && webhook.SigningSecretRef != nil {
+		if _, err := r.readSecret(ctx, config, "webhook.signingSecretRef", webhook.SigningSecretRef); err != nil {
+			return "", err
+		}
+	}
+	if incident := config.Spec.Incident; incident != nil {
+		if _, err := r.readSecret(ctx, config, "incident.keySecretRef", &incident.KeySecretRef); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s key found", config.Spec.Type), nil
+	}
+	if email := config.Spec.Email; email != nil {
+		if email.PasswordSecretRef != nil {
+			if _, err := r.readSecret(ctx, config, "email.passwordSecretRef", email.PasswordSecretRef); err != nil {
+				return "", err
+			}
+		}
+		port := int(email.Port)
+		if port == 0 {
+			port = sink.DefaultSMTPPort
+		}
+		return "Email via " + net.JoinHostPort(email.Host, strconv.Itoa(port)), nil
+	}
+	sinkURL, err := r.readSecret(ctx, config, "urlSecretRef", config.Spec.URLSecretRef)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(strings.TrimSpace(sinkURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", invalidConfig(ReasonInvalidCredentials, "secret %s does not contain an http or https URL", config.Spec.URLSecretRef.Name)
+	}
+	return fmt.Sprintf("%s URL found", config.Spec.Type), nil
+}
+
+// readSecret reads a Secret referenced by the SinkConfig field name,
+// bypassing the cache of the CredentialResolver.
+func (r *SinkConfigReconciler) readSecret(ctx context.Context, config *notificationv1alpha1.SinkConfig, name string, ref *corev1.SecretKeySelector) (string, error) {
+	resolver := credentialResolver(r.CredentialResolver, r.Client)
+	val, err := resolver.Refresh(ctx, credentials.ProviderSecret, config.Namespace, ref)
+	switch {
+	case errors.Is(err, credentials.ErrNotFound):
+		return "", invalidConfig(ReasonSecretNotFound, "secret %s referenced by %s was not found", ref.Name, name)
+	case errors.Is(err, credentials.ErrKeyNotFound):
+		return "", invalidConfig(ReasonSecretKeyNotFound, "secret %s referenced by %s has no key %s", ref.Name, name, ref.Key)
+	}
+	return val, err
+}
+
+// sinkConfigSecretNames returns the names of the Secrets a SinkConfig references.
+func sinkConfigSecretNames(obj client.Object) []string {
+	config, ok := obj.(*notificationv1alpha1.SinkConfig)
+	if !ok {
+		return nil
+	}
+	refs := []*corev1.SecretKeySelector{config.Spec.URLSecretRef}
+	if webhook := config.Spec.Webhook; webhook != nil {
+		refs = append(refs, webhook.SigningSecretRef)
+	}
+	if incident := config.Spec.Incident; incident != nil {
+		refs = append(refs, &incident.KeySecretRef)
+	}
+	if email := config.Spec.Email; email != nil {
+		refs = append(refs, email.PasswordSecretRef)
+	}
+	var names []string
+	for _, ref := range refs {
+		if ref != nil && ref.Name != "" {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// configsForSecret maps a Secret to the SinkConfigs referencing it.
+func (r *SinkConfigReconciler) configsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var configs notificationv1alpha1.SinkConfigList
+	if err := r.List(ctx, &configs, client.InNamespace(secret.GetNamespace()), client.MatchingFields{sinkConfigSecretIndex: secret.GetName()}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SinkConfigs referencing secret", "secret", secret.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(configs.Items))
+	for _, config := range configs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: config.Namespace, Name: config.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SinkConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &notificationv1alpha1.SinkConfig{}, sinkConfigSecretIndex, sinkConfigSecretNames); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&notificationv1alpha1.SinkConfig{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.configsForSecret), builder.OnlyMetadata).
+		Named("sinkconfig").
+		Complete(r)
+}
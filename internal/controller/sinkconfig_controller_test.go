@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+var _ = Describe("SinkConfig Controller", func() {
+	var (
+		ctx     context.Context
+		config  *notificationv1alpha1.SinkConfig
+		secret  *corev1.Secret
+		objects []client.Object
+		c       client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		config = &notificationv1alpha1.SinkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "team-a"},
+			Spec: notificationv1alpha1.SinkConfigSpec{
+				Type:         sink.TypeWebhook,
+				URLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "alerts"}, Key: "url"},
+				Webhook: &notificationv1alpha1.WebhookSink{
+					BodyTemplate:     `{"text": {{ json .Title }}, "status": {{ json .Status }}}`,
+					SigningSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "alerts"}, Key: "signing-secret"},
+				},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "team-a"},
+			Data:       map[string][]byte{"url": []byte("https://alerts.example.com/hook"), "signing-secret": []byte("s3cret")},
+		}
+		objects = []client.Object{config}
+	})
+
+	build := func() {
+		c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(objects...).
+			WithStatusSubresource(&notificationv1alpha1.SinkConfig{}, &notificationv1alpha1.SlackNotificationRule{}).
+			WithIndex(&notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+			Build()
+	}
+
+	reconcileConfig := func() *metav1.Condition {
+		r := &SinkConfigReconciler{Client: c, Scheme: c.Scheme()}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+		Expect(err).NotTo(HaveOccurred())
+
+		var got notificationv1alpha1.SinkConfig
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+		return meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+	}
+
+	Context("When verifying a SinkConfig", func() {
+		It("is Ready when its Secrets contain a URL and a signing secret", func() {
+			objects = append(objects, secret)
+			build()
+			cond := reconcileConfig()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(Equal("Webhook URL found"))
+		})
+
+		It("reports missing Secrets and keys", func() {
+			build()
+			Expect(reconcileConfig().Reason).To(Equal(ReasonSecretNotFound))
+
+			delete(secret.Data, "signing-secret")
+			Expect(c.Create(ctx, secret)).To(Succeed())
+			cond := reconcileConfig()
+			Expect(cond.Reason).To(Equal(ReasonSecretKeyNotFound))
+			Expect(cond.Message).To(ContainSubstring("webhook.signingSecretRef"))
+		})
+
+		It("rejects Secrets without a URL and invalid specs", func() {
+			secret.Data["url"] = []byte("not a url")
+			objects = append(objects, secret)
+			build()
+			Expect(reconcileConfig().Reason).To(Equal(ReasonInvalidCredentials))
+
+			config.Spec.Type = sink.TypeDiscord
+			build()
+			Expect(reconcileConfig().Reason).To(Equal(ReasonInvalidSpec))
+		})
+
+		It("reads the SMTP password of an email sink", func() {
+			config.Spec = notificationv1alpha1.SinkConfigSpec{
+				Type: sink.TypeEmail,
+				Email: &notificationv1alpha1.EmailSink{
+					Host:              "smtp.example.com",
+					Username:          "notifier",
+					PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "alerts"}, Key: "password"},
+					From:              "notifier@example.com",
+					To:                []string{"oncall@example.com"},
+				},
+			}
+			Expect(sinkConfigSecretNames(config)).To(ConsistOf("alerts"))
+			objects = append(objects, secret)
+			build()
+			cond := reconcileConfig()
+			Expect(cond.Reason).To(Equal(ReasonSecretKeyNotFound))
+			Expect(cond.Message).To(ContainSubstring("email.passwordSecretRef"))
+
+			secret.Data["password"] = []byte("s3cret\n")
+			Expect(c.Update(ctx, secret)).To(Succeed())
+			cond = reconcileConfig()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(Equal("Email via smtp.example.com:587"))
+
+			n := &Notifier{Client: c}
+			s, err := n.newSink(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeAssignableToTypeOf(&sink.EmailSink{}))
+			Expect(s.(*sink.EmailSink).Password).To(Equal("s3cret"))
+		})
+	})
+
+	Context("When a rule delivers to a sink", func() {
+		var (
+			rule     *notificationv1alpha1.SlackNotificationRule
+			received chan *http.Request
+			bodies   chan []byte
+		)
+
+		BeforeEach(func() {
+			received = make(chan *http.Request, 1)
+			bodies = make(chan []byte, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received <- r
+				bodies <- body
+			}))
+			DeferCleanup(srv.Close)
+			secret.Data["url"] = []byte(srv.URL)
+
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+					Notifications: []notificationv1alpha1.NotificationRule{{
+						Status:       "Failed",
+						Title:        "{{ .metadata.name }} failed",
+						Destinations: []notificationv1alpha1.NotificationDestination{{SinkRef: &corev1.LocalObjectReference{Name: "alerts"}}},
+					}},
+				},
+			}
+			objects = append(objects, rule, secret, &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+				Spec:       notificationv1alpha1.SlackConfigSpec{AuthType: "Webhook"},
+				Status: notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+					Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+				}}},
+			})
+		})
+
+		reconcileRule := func() (*notificationv1alpha1.SlackNotificationRule, *metav1.Condition) {
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			return &got, meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+		}
+
+		It("is not Ready until the SinkConfig is", func() {
+			build()
+			_, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonConfigNotReady))
+			Expect(cond.Message).To(Equal("SinkConfig alerts has not been verified yet"))
+
+			Expect(reconcileConfig().Status).To(Equal(metav1.ConditionTrue))
+			Expect(slackConfigRefKey(rule)).To(ContainElement(configRefKey(kindSinkConfig, "team-a", "alerts")))
+			_, cond = reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("posts the rendered body signed with the signing secret and tracks the sink", func() {
+			build()
+			notifier := &Notifier{Client: c, SlackClient: &fakeSlackClient{}}
+			cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "team-a"}}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"}}
+			_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+			Expect(err).NotTo(HaveOccurred())
+
+			var req *http.Request
+			var body []byte
+			Eventually(received).Should(Receive(&req))
+			Eventually(bodies).Should(Receive(&body))
+			var payload map[string]string
+			Expect(json.Unmarshal(body, &payload)).To(Succeed())
+			Expect(payload).To(Equal(map[string]string{"text": "backup-1 failed", "status": "Failed"}))
+			Expect(req.Header.Get(sink.SignatureHeader)).To(Equal(sink.Sign("s3cret", req.Header.Get(sink.TimestampHeader), body)))
+
+			got, _ := reconcileRule()
+			Expect(got.Status.SentCount).To(Equal(int64(1)))
+			Expect(got.Status.Destinations).To(ConsistOf(HaveField("Sink", "alerts")))
+			Expect(got.Status.Destinations[0].SentCount).To(Equal(int64(1)))
+		})
+	})
+})
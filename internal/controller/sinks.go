@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
+)
+
+// SendToSink delivers a notification of rule to the SinkConfig name in the
+// namespace of the rule, with the same title and fields as on Slack. Incidents
+// opened in PagerDuty or Opsgenie are recorded in the status of the rule until
+// a later run of targetObj succeeds. Running and Succeeded notifications open
+// no incidents. Broadcast mentions are subject to the channel policies of the
+// rule as on Slack.
+func (n *Notifier) SendToSink(ctx context.Context, triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "SendToSink", tracing.SinkKey.String(name))
+	defer func() { tracing.End(span, err) }()
+
+	var config notificationv1alpha1.SinkConfig
+	if err := n.Client.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: name}, &config); err != nil {
+		return fmt.Errorf("failed to get SinkConfig: %w", err)
+	}
+	s, err := n.newSink(ctx, &config)
+	if err != nil {
+		return err
+	}
+
+	msg, err := n.sinkMessage(triggerObj, targetObj, rule, note)
+	if err != nil {
+		return err
+	}
+	texts := []string{msg.Title}
+	for _, f := range msg.Fields {
+		texts = append(texts, f.Value)
+	}
+	if err := n.checkPolicy(ctx, rule, note.Status, "", texts...); err != nil {
+		return err
+	}
+	if n.dryRun(rule) {
+		fields := make(map[string]string, len(msg.Fields))
+		for _, f := range msg.Fields {
+			fields[f.Title] = f.Value
+		}
+		log.FromContext(ctx).Info("Dry run: notification not sent", "rule", rule.Name, "status", note.Status, "sink", name, "title", msg.Title, "fields", fields)
+		return nil
+	}
+	if _, ok := s.(sink.IncidentSink); !ok {
+		return s.Send(ctx, msg)
+	}
+	if !opensIncident(note.Status) {
+		log.FromContext(ctx).Info("Not opening an incident for a run that did not fail", "rule", rule.Name, "status", note.Status, "sink", name)
+		return nil
+	}
+	msg.DedupKey = incidentKey(rule, targetObj, note.Status)
+	if err := s.Send(ctx, msg); err != nil {
+		return err
+	}
+	n.recordIncident(ctx, triggerObj, targetObj, rule, name, msg)
+	return nil
+}
+
+// sinkMessage renders the message a notification of rule is delivered to
+// sinks as.
+func (n *Notifier) sinkMessage(triggerObj client.Object, targetObj client.Object, rule notificationv1alpha1.SlackNotificationRule, note notificationv1alpha1.NotificationRule) (*sink.Message, error) {
+	data, err := toTemplateData(triggerObj)
+	if err != nil {
+		return nil, err
+	}
+	title, err := slack.RenderTitle(note.Title, data)
+	if err != nil {
+		return nil, err
+	}
+	msg := &sink.Message{
+		Title:  title,
+		Status: note.Status,
+		Source: targetObj.GetNamespace() + "/" + targetObj.GetName(),
+		Rule:   rule.Name,
+		Color:  statusColor(note.Status),
+		Object: data,
+	}
+	for _, f := range n.buildFields(triggerObj, targetObj, note.Status) {
+		msg.Fields = append(msg.Fields, sink.Field{Title: f.Title, Value: f.Value})
+	}
+	return msg, nil
+}
+
+// newSink returns the Sink of config with the URL, keys, password and signing secret read from its Secrets.
+func (n *Notifier) newSink(ctx context.Context, config *notificationv1alpha1.SinkConfig) (sink.Sink, error) {
+	resolver := credentialResolver(n.CredentialResolver, n.Client)
+	if email := config.Spec.Email; email != nil {
+		s := &sink.EmailSink{
+			Host:          email.Host,
+			Port:          int(email.Port),
+			TLS:           email.TLS,
+			Username:      email.Username,
+			From:          email.From,
+			To:            email.To,
+			MaxRecipients: int(email.MaxRecipientsPerMessage),
+			Now:           n.now,
+		}
+		if email.PasswordSecretRef != nil {
+			password, err := resolver.Get(ctx, credentials.ProviderSecret, config.Namespace, email.PasswordSecretRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get SMTP password secret: %w", err)
+			}
+			// Secrets written with a trailing newline would fail authentication.
+			s.Password = strings.TrimRight(password, "\r\n")
+		}
+		return s, nil
+	}
+	if incident := config.Spec.Incident; incident != nil {
+		key, err := resolver.Get(ctx, credentials.ProviderSecret, config.Namespace, &incident.KeySecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get incident key secret: %w", err)
+		}
+		key = strings.TrimSpace(key)
+		switch config.Spec.Type {
+		case sink.TypePagerDuty:
+			return &sink.PagerDutySink{URL: incident.APIURL, RoutingKey: key, Severity: incident.Severity}, nil
+		case sink.TypeOpsgenie:
+			return &sink.OpsgenieSink{URL: incident.APIURL, APIKey: key, Severity: incident.Severity}, nil
+		}
+		return nil, fmt.Errorf("sink type %s does not support incidents", config.Spec.Type)
+	}
+	if config.Spec.URLSecretRef == nil {
+		return nil, fmt.Errorf("SinkConfig %s has no urlSecretRef", config.Name)
+	}
+	sinkURL, err := resolver.Get(ctx, credentials.ProviderSecret, config.Namespace, config.Spec.URLSecretRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sink URL secret: %w", err)
+	}
+	if config.Spec.Type == sink.TypeAlertmanager {
+		s := &sink.AlertmanagerSink{URL: strings.TrimSpace(sinkURL), Now: n.now}
+		if am := config.Spec.Alertmanager; am != nil {
+			s.Labels = am.Labels
+			if am.Expiry != nil {
+				s.Expiry = am.Expiry.Duration
+			}
+		}
+		return s, nil
+	}
+	var bodyTemplate string
+	s := &sink.HTTPSink{URL: strings.TrimSpace(sinkURL), Now: n.now}
+	if webhook := config.Spec.Webhook; webhook != nil {
+		bodyTemplate = webhook.BodyTemplate
+		s.Headers = webhook.Headers
+		if webhook.SigningSecretRef != nil {
+			s.SigningSecret, err = resolver.Get(ctx, credentials.ProviderSecret, config.Namespace, webhook.SigningSecretRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get signing secret: %w", err)
+			}
+		}
+	}
+	s.Renderer, err = sink.NewRenderer(config.Spec.Type, bodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
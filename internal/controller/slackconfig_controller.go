@@ -18,46 +18,374 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
+	goslack "github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
 )
 
+// Reasons of the Ready and Degraded conditions of SlackConfig.
+const (
+	ReasonVerified           = "Verified"
+	ReasonSecretNotFound     = "SecretNotFound"
+	ReasonSecretKeyNotFound  = "SecretKeyNotFound"
+	ReasonInvalidCredentials = "InvalidCredentials"
+	ReasonChannelNotFound    = "ChannelNotFound"
+	ReasonChannelArchived    = "ChannelArchived"
+	ReasonNotInChannel       = "NotInChannel"
+	ReasonMissingScope       = "MissingScope"
+	ReasonVerificationFailed = "VerificationFailed"
+	// ReasonCredentialProviderNotAllowed is reported for a SlackConfig outside
+	// of the namespace of the controller reading its credentials from files or
+	// environment variables of the controller pod.
+	ReasonCredentialProviderNotAllowed = "CredentialProviderNotAllowed"
+)
+
+// slackConfigSecretIndex indexes SlackConfigs and ClusterSlackConfigs by the names of the Secrets they reference.
+const slackConfigSecretIndex = "spec.secretRefs"
+
+// defaultRecheckInterval is how often a SlackConfig is verified again.
+const defaultRecheckInterval = 10 * time.Minute
+
 // SlackConfigReconciler reconciles a SlackConfig object
 type SlackConfigReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme      *runtime.Scheme
+	SlackClient slack.Client
+	// RecheckInterval is how often a SlackConfig is verified again, so that
+	// revoked tokens or archived channels are noticed. Defaults to 10 minutes.
+	RecheckInterval time.Duration
+	// CredentialResolver reads the credentials of SlackConfigs. Defaults to
+	// reading Secrets, without caching.
+	CredentialResolver *credentials.Resolver
+	// ClusterResourceNamespace is the namespace of the controller, the only
+	// one whose SlackConfigs may read credentials from files or environment
+	// variables.
+	ClusterResourceNamespace string
+	// Clock is used to schedule the refresh of rotating tokens. Defaults to the real clock.
+	Clock clock.PassiveClock
+	// APIReader reads the Secrets of rotating tokens from the API server, so
+	// that a token is never refreshed based on a stale copy. Defaults to the
+	// client.
+	APIReader client.Reader
+}
+
+// configError is a problem with a SlackConfig that persists until the
+// SlackConfig or the Secrets it references are changed.
+type configError struct {
+	reason  string
+	message string
+}
+
+func (e *configError) Error() string {
+	return e.message
+}
+
+func invalidConfig(reason, format string, args ...any) error {
+	return &configError{reason: reason, message: fmt.Sprintf(format, args...)}
 }
 
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the SlackConfig object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+// Reconcile verifies that the Secrets referenced by a SlackConfig exist and
+// match its AuthType and, for token authentication, that Slack accepts the
+// token and the bot can post to the channel. Rotating tokens are refreshed
+// before they expire. The outcome is reported with the
+// Ready and Degraded conditions, and the SlackConfig is verified again
+// periodically.
 func (r *SlackConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	var config notificationv1alpha1.SlackConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := config.Status.DeepCopy()
+
+	message, err := r.verify(ctx, &config)
+	err = setVerifyConditions(ctx, &config.Status.Conditions, config.Generation, message, err)
+
+	if !equality.Semantic.DeepEqual(status, &config.Status) {
+		if updateErr := r.Status().Update(ctx, &config); updateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update SlackConfig status: %w", updateErr)
+		}
+	}
+	if err != nil {
+		// Slack or the API server could not be reached; retry with backoff.
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.requeueAfter(&config.Status)}, nil
+}
+
+func (r *SlackConfigReconciler) recheckInterval() time.Duration {
+	if r.RecheckInterval == 0 {
+		return defaultRecheckInterval
+	}
+	return r.RecheckInterval
+}
+
+// requeueAfter returns when a SlackConfig is verified again: after the recheck
+// interval, or earlier when its rotating token has to be refreshed.
+func (r *SlackConfigReconciler) requeueAfter(status *notificationv1alpha1.SlackConfigStatus) time.Duration {
+	if due := r.tokenRefreshDue(status); due > 0 && due < r.recheckInterval() {
+		return due
+	}
+	return r.recheckInterval()
+}
+
+// setConfigConditions sets the Ready and Degraded conditions of a
+// SlackConfig, ClusterSlackConfig or SinkConfig status.
+func setConfigConditions(conditions *[]metav1.Condition, generation int64, ready metav1.ConditionStatus, reason, message string) {
+	degraded := metav1.ConditionFalse
+	if ready != metav1.ConditionTrue {
+		degraded = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type: notificationv1alpha1.ConditionReady, Status: ready, Reason: reason, Message: message, ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type: notificationv1alpha1.ConditionDegraded, Status: degraded, Reason: reason, Message: message, ObservedGeneration: generation,
+	})
+}
+
+// setVerifyConditions sets the conditions of a SlackConfig, ClusterSlackConfig
+// or SinkConfig status from the outcome of verify. Problems with the
+// configuration itself are reported and swallowed; other errors are returned
+// so that they are retried.
+func setVerifyConditions(ctx context.Context, conditions *[]metav1.Condition, generation int64, message string, err error) error {
+	var invalid *configError
+	switch {
+	case err == nil:
+		setConfigConditions(conditions, generation, metav1.ConditionTrue, ReasonVerified, message)
+	case errors.As(err, &invalid):
+		logf.FromContext(ctx).Info("Configuration is not usable", "reason", invalid.reason, "message", invalid.message)
+		setConfigConditions(conditions, generation, metav1.ConditionFalse, invalid.reason, invalid.message)
+		return nil
+	default:
+		setConfigConditions(conditions, generation, metav1.ConditionFalse, ReasonVerificationFailed, err.Error())
+	}
+	return err
+}
+
+// verify checks the SlackConfig and describes it when it is usable. Problems
+// with the SlackConfig itself are returned as *configError.
+func (r *SlackConfigReconciler) verify(ctx context.Context, config *notificationv1alpha1.SlackConfig) (string, error) {
+	spec := config.Spec
+	if errs := validation.ValidateConfigSpec(&spec, field.NewPath("spec")); len(errs) > 0 {
+		return "", invalidConfig(ReasonInvalidSpec, "%s", errs.ToAggregate().Error())
+	}
+	if err := r.rotateToken(ctx, config); err != nil {
+		return "", err
+	}
+	if spec.Interactivity != nil {
+		if ref := spec.Interactivity.SigningSecretRef; ref != nil {
+			if _, err := r.readSecret(ctx, config, "interactivity.signingSecretRef", ref); err != nil {
+				return "", err
+			}
+		}
+		if ref := spec.Interactivity.AppTokenSecretRef; ref != nil {
+			if _, err := r.readSecret(ctx, config, "interactivity.appTokenSecretRef", ref); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	switch spec.AuthType {
+	case "Webhook":
+		webhookURL, err := r.readSecret(ctx, config, "webhookUrlSecretRef", spec.WebhookURLSecretRef)
+		if err != nil {
+			return "", err
+		}
+		u, err := url.Parse(strings.TrimSpace(webhookURL))
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return "", invalidConfig(ReasonInvalidCredentials, "%s does not contain an https webhook URL", r.describeCredential(config, spec.WebhookURLSecretRef))
+		}
+		return "Webhook URL found", nil
+
+	case "Token":
+		token, err := r.readSecret(ctx, config, "tokenSecretRef", spec.TokenSecretRef)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(token, "xox") {
+			return "", invalidConfig(ReasonInvalidCredentials, "%s does not contain a Slack bot or user token", r.describeCredential(config, spec.TokenSecretRef))
+		}
+		return r.verifyToken(ctx, config, token)
+	}
+	return "", invalidConfig(ReasonInvalidCredentials, "unsupported authType %q", spec.AuthType)
+}
+
+// verifyToken checks the token with auth.test and that the bot can post to the
+// channel of config.
+func (r *SlackConfigReconciler) verifyToken(ctx context.Context, config *notificationv1alpha1.SlackConfig, token string) (string, error) {
+	channel := config.Spec.Channel
+	auth, err := r.SlackClient.AuthTest(ctx, token)
+	if err != nil {
+		var slackErr goslack.SlackErrorResponse
+		if errors.As(err, &slackErr) {
+			config.Status.TeamID = ""
+			return "", invalidConfig(ReasonInvalidCredentials, "Slack rejected the token: %s", slackErr.Err)
+		}
+		return "", err
+	}
+	config.Status.TeamID = auth.TeamID
+
+	info, err := r.lookupChannel(ctx, &config.Status, token, channel)
+	var slackErr goslack.SlackErrorResponse
+	switch {
+	case errors.Is(err, slack.ErrChannelNotFound):
+		return "", invalidConfig(ReasonChannelNotFound, "channel %s was not found or is not visible to the bot", channel)
+	case errors.As(err, &slackErr) && slackErr.Err == "missing_scope":
+		return "", invalidConfig(ReasonMissingScope, "the token lacks the channels:read or groups:read scope needed to look up channel %s", channel)
+	case errors.As(err, &slackErr):
+		return "", invalidConfig(ReasonVerificationFailed, "failed to look up channel %s: %s", channel, slackErr.Err)
+	case err != nil:
+		return "", err
+	}
+	if info.IsArchived {
+		return "", invalidConfig(ReasonChannelArchived, "channel %s is archived", channel)
+	}
+	if !info.IsMember && info.IsPrivate {
+		return "", invalidConfig(ReasonNotInChannel, "bot %s is not a member of private channel %s", auth.User, channel)
+	}
+	if !info.IsMember {
+		return fmt.Sprintf("Authenticated as %s in %s; not a member of %s, posting relies on the chat:write.public scope", auth.User, auth.Team, channel), nil
+	}
+	return fmt.Sprintf("Authenticated as %s in %s; member of %s", auth.User, auth.Team, channel), nil
+}
+
+// lookupChannel returns the channel messages to channel are posted to, by the
+// ID remembered in status when it is still named channel, and remembers its ID.
+func (r *SlackConfigReconciler) lookupChannel(ctx context.Context, status *notificationv1alpha1.SlackConfigStatus, token, channel string) (*goslack.Channel, error) {
+	if status.Channel == channel && status.ChannelID != "" {
+		info, err := r.SlackClient.ConversationInfo(ctx, token, status.ChannelID)
+		renamed := err == nil && !slack.IsConversationID(channel) && info.Name != strings.TrimPrefix(channel, "#")
+		if err == nil && !renamed {
+			return info, nil
+		}
+		if err != nil && !errors.Is(err, slack.ErrChannelNotFound) {
+			return nil, err
+		}
+		// The channel was deleted or renamed; look it up by name again.
+	}
 
-	// TODO(user): your logic here
+	status.Channel, status.ChannelID = "", ""
+	info, err := r.SlackClient.ConversationInfo(ctx, token, channel)
+	if err != nil {
+		return nil, err
+	}
+	if info.ID != "" {
+		status.Channel, status.ChannelID = channel, info.ID
+	}
+	return info, nil
+}
+
+// readSecret reads a credential referenced by the SlackConfig field name. It
+// bypasses the cache of the CredentialResolver, so that rotated credentials are
+// used from the next notification on.
+func (r *SlackConfigReconciler) readSecret(ctx context.Context, config *notificationv1alpha1.SlackConfig, name string, ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", invalidConfig(ReasonInvalidSpec, "%s is required", name)
+	}
+	provider, err := credentialProvider(config, r.ClusterResourceNamespace)
+	if err != nil {
+		return "", invalidConfig(ReasonCredentialProviderNotAllowed, "%s", err.Error())
+	}
+	resolver := credentialResolver(r.CredentialResolver, r.Client)
+	val, err := resolver.Refresh(ctx, provider, config.Namespace, ref)
+	switch {
+	case errors.Is(err, credentials.ErrNotFound):
+		return "", invalidConfig(ReasonSecretNotFound, "%s referenced by %s was not found", resolver.Describe(provider, ref), name)
+	case errors.Is(err, credentials.ErrKeyNotFound):
+		return "", invalidConfig(ReasonSecretKeyNotFound, "secret %s referenced by %s has no key %s", ref.Name, name, ref.Key)
+	}
+	return val, err
+}
+
+// describeCredential names the source of a credential of config in messages.
+func (r *SlackConfigReconciler) describeCredential(config *notificationv1alpha1.SlackConfig, ref *corev1.SecretKeySelector) string {
+	provider, _ := credentialProvider(config, r.ClusterResourceNamespace)
+	return credentialResolver(r.CredentialResolver, r.Client).Describe(provider, ref)
+}
+
+// slackConfigSecretNames returns the names of the Secrets a SlackConfig or ClusterSlackConfig references.
+func slackConfigSecretNames(obj client.Object) []string {
+	var spec notificationv1alpha1.SlackConfigSpec
+	switch config := obj.(type) {
+	case *notificationv1alpha1.SlackConfig:
+		spec = config.Spec
+	case *notificationv1alpha1.ClusterSlackConfig:
+		spec = config.Spec
+	default:
+		return nil
+	}
+	if spec.CredentialProvider != "" && spec.CredentialProvider != credentials.ProviderSecret {
+		return nil
+	}
+	refs := []*corev1.SecretKeySelector{spec.WebhookURLSecretRef, spec.TokenSecretRef}
+	if spec.Interactivity != nil {
+		refs = append(refs, spec.Interactivity.SigningSecretRef, spec.Interactivity.AppTokenSecretRef)
+	}
+	if spec.TokenRotation != nil {
+		refs = append(refs, &spec.TokenRotation.ClientSecretRef, &spec.TokenRotation.RefreshTokenSecretRef)
+	}
+	var names []string
+	for _, ref := range refs {
+		if ref != nil && ref.Name != "" {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
 
-	return ctrl.Result{}, nil
+// configsForSecret maps a Secret to the SlackConfigs referencing it.
+func (r *SlackConfigReconciler) configsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var configs notificationv1alpha1.SlackConfigList
+	if err := r.List(ctx, &configs, client.InNamespace(secret.GetNamespace()), client.MatchingFields{slackConfigSecretIndex: secret.GetName()}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SlackConfigs referencing secret", "secret", secret.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(configs.Items))
+	for _, config := range configs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: config.Namespace, Name: config.Name}})
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SlackConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.SlackClient == nil {
+		r.SlackClient = slack.NewClient()
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &notificationv1alpha1.SlackConfig{}, slackConfigSecretIndex, slackConfigSecretNames); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&notificationv1alpha1.SlackConfig{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.configsForSecret), builder.OnlyMetadata).
 		Named("slackconfig").
 		Complete(r)
 }
@@ -18,16 +18,30 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	goslack "github.com/slack-go/slack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
 )
 
 var _ = Describe("SlackConfig Controller", func() {
@@ -43,6 +57,15 @@ var _ = Describe("SlackConfig Controller", func() {
 		slackconfig := &notificationv1alpha1.SlackConfig{}
 
 		BeforeEach(func() {
+			By("creating the webhook Secret referenced by the SlackConfig")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Data:       map[string][]byte{"url": []byte("https://hooks.slack.com/services/T000/B000/XXXX")},
+			}
+			if err := k8sClient.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
 			By("creating the custom resource for the Kind SlackConfig")
 			err := k8sClient.Get(ctx, typeNamespacedName, slackconfig)
 			if err != nil && errors.IsNotFound(err) {
@@ -51,7 +74,13 @@ var _ = Describe("SlackConfig Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType: "Webhook",
+						WebhookURLSecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: resourceName},
+							Key:                  "url",
+						},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -77,8 +106,529 @@ var _ = Describe("SlackConfig Controller", func() {
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			resource := &notificationv1alpha1.SlackConfig{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, notificationv1alpha1.ConditionDegraded)).To(BeTrue())
+		})
+	})
+
+	Context("When verifying a token", func() {
+		var (
+			ctx     context.Context
+			slackFk *fakeSlackClient
+			secret  *corev1.Secret
+			config  *notificationv1alpha1.SlackConfig
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			slackFk = &fakeSlackClient{channels: map[string]*goslack.Channel{}}
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack-token", Namespace: "team-a"},
+				Data:       map[string][]byte{"token": []byte("xoxb-test")},
+			}
+			config = &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-a", Generation: 2},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType: "Token",
+					Channel:  "#alerts",
+					TokenSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "slack-token"},
+						Key:                  "token",
+					},
+				},
+			}
+		})
+
+		// reconcileConfig reconciles config with the given objects and returns its Degraded condition.
+		reconcileConfig := func(objs ...client.Object) *metav1.Condition {
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(append(objs, config)...).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r := &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk}
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(defaultRecheckInterval))
+
+			var got notificationv1alpha1.SlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			ready := meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+			degraded := meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionDegraded)
+			Expect(ready).NotTo(BeNil())
+			Expect(degraded).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal(degraded.Reason))
+			Expect(ready.Status == metav1.ConditionTrue).To(Equal(degraded.Status == metav1.ConditionFalse))
+			Expect(degraded.ObservedGeneration).To(Equal(int64(2)))
+			return degraded
+		}
+
+		It("is ready when the bot is a member of the channel", func() {
+			slackFk.channels["#alerts"] = &goslack.Channel{IsMember: true}
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+			Expect(degraded.Reason).To(Equal(ReasonVerified))
+		})
+
+		It("looks up a channel given by name only until its ID is known", func() {
+			channel := &goslack.Channel{IsMember: true}
+			channel.ID = "C0ALERTS"
+			channel.Name = "alerts"
+			slackFk.channels["#alerts"] = channel
+			slackFk.channels["C0ALERTS"] = channel
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(secret, config).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r := &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk}
+			for range 2 {
+				_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(slackFk.lookups).To(Equal([]string{"#alerts", "C0ALERTS"}))
+
+			var got notificationv1alpha1.SlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			Expect(got.Status.Channel).To(Equal("#alerts"))
+			Expect(got.Status.ChannelID).To(Equal("C0ALERTS"))
+			Expect(got.Status.TeamID).To(Equal("T0EXAMPLE"))
+
+			By("renaming the channel")
+			channel.Name = "alerts-old"
+			slackFk.lookups = nil
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slackFk.lookups).To(Equal([]string{"C0ALERTS", "#alerts"}))
+		})
+
+		It("looks up a channel given by a bare name by its name", func() {
+			// A fake Slack API that only knows the channel #alerts.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.ParseForm()).To(Succeed())
+				w.Header().Set("Content-Type", "application/json")
+				switch req.URL.Path {
+				case "/auth.test":
+					_, _ = fmt.Fprint(w, `{"ok":true,"user":"notifier","team":"Example"}`)
+				case "/conversations.list":
+					_, _ = fmt.Fprint(w, `{"ok":true,"channels":[{"id":"C0ALERTS","name":"alerts"}]}`)
+				case "/conversations.info":
+					if req.Form.Get("channel") != "C0ALERTS" {
+						_, _ = fmt.Fprint(w, `{"ok":false,"error":"channel_not_found"}`)
+						return
+					}
+					_, _ = fmt.Fprint(w, `{"ok":true,"channel":{"id":"C0ALERTS","name":"alerts","is_member":true}}`)
+				default:
+					Fail("unexpected request to " + req.URL.Path)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			config.Spec.Channel = "alerts"
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(secret, config).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r := &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slack.NewClient(slack.WithAPIURL(server.URL + "/"))}
+			for range 2 {
+				_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			var got notificationv1alpha1.SlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(got.Status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(got.Status.Channel).To(Equal("alerts"))
+			Expect(got.Status.ChannelID).To(Equal("C0ALERTS"))
+		})
+
+		It("is degraded when the Secret does not exist", func() {
+			degraded := reconcileConfig()
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal(ReasonSecretNotFound))
+		})
+
+		It("is degraded when the Secret has no such key", func() {
+			secret.Data = map[string][]byte{"other": []byte("xoxb-test")}
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonSecretKeyNotFound))
+		})
+
+		It("is degraded when the Secret holds a webhook URL instead of a token", func() {
+			secret.Data["token"] = []byte("https://hooks.slack.com/services/T000/B000/XXXX")
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonInvalidCredentials))
+		})
+
+		It("is degraded when Slack rejects the token", func() {
+			slackFk.authErr = goslack.SlackErrorResponse{Err: "invalid_auth"}
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonInvalidCredentials))
+			Expect(degraded.Message).To(ContainSubstring("invalid_auth"))
+		})
+
+		It("is degraded when the channel does not exist", func() {
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonChannelNotFound))
+		})
+
+		It("is degraded when the channel is archived", func() {
+			channel := &goslack.Channel{IsMember: true}
+			channel.IsArchived = true
+			slackFk.channels["#alerts"] = channel
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonChannelArchived))
+		})
+
+		It("is degraded when the bot is not a member of a private channel", func() {
+			channel := &goslack.Channel{}
+			channel.IsPrivate = true
+			slackFk.channels["#alerts"] = channel
+			degraded := reconcileConfig(secret)
+			Expect(degraded.Reason).To(Equal(ReasonNotInChannel))
+		})
+
+		It("reconciles the SlackConfigs referencing a changed Secret", func() {
+			other := config.DeepCopy()
+			other.Name = "other"
+			other.Spec.TokenSecretRef.Name = "other-token"
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, other).
+				WithIndex(&notificationv1alpha1.SlackConfig{}, slackConfigSecretIndex, slackConfigSecretNames).
+				Build()
+			r := &SlackConfigReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.configsForSecret(ctx, secret)).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "config"},
+			}))
+		})
+	})
+
+	Context("When reading credentials from the controller pod", func() {
+		const controllerNamespace = "slack-notifier-system"
+
+		var (
+			ctx      context.Context
+			slackFk  *fakeSlackClient
+			env      map[string]string
+			resolver *credentials.Resolver
+			config   *notificationv1alpha1.SlackConfig
+			rule     *notificationv1alpha1.SlackNotificationRule
+			c        client.Client
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			slackFk = &fakeSlackClient{channels: map[string]*goslack.Channel{"#alerts": {IsMember: true}}}
+			env = map[string]string{"SLACK_TOKEN": "xoxb-env-1"}
+			resolver = &credentials.Resolver{
+				Providers: map[string]credentials.Provider{
+					credentials.ProviderSecret: &credentials.SecretProvider{},
+					credentials.ProviderEnv: &credentials.EnvProvider{LookupEnv: func(key string) (string, bool) {
+						val, ok := env[key]
+						return val, ok
+					}},
+				},
+				TTL: time.Hour,
+			}
+			config = &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: controllerNamespace},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:           "Token",
+					CredentialProvider: credentials.ProviderEnv,
+					Channel:            "#alerts",
+					TokenSecretRef:     &corev1.SecretKeySelector{Key: "SLACK_TOKEN"},
+				},
+			}
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: controllerNamespace},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "config"},
+					Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "failed"}},
+				},
+			}
+		})
+
+		reconcileConfig := func() *metav1.Condition {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r := &SlackConfigReconciler{
+				Client: c, Scheme: c.Scheme(), SlackClient: slackFk,
+				CredentialResolver: resolver, ClusterResourceNamespace: controllerNamespace,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			return meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+		}
+
+		It("sends with the cached token and picks up a rotated one when verified", func() {
+			Expect(reconcileConfig().Status).To(Equal(metav1.ConditionTrue))
+
+			notifier := &Notifier{Client: c, SlackClient: slackFk, CredentialResolver: resolver, ClusterResourceNamespace: controllerNamespace}
+			send := func() {
+				Expect(notifier.ResolveAndSend(ctx, &batchv1.Job{}, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])).To(Succeed())
+			}
+			send()
+			env["SLACK_TOKEN"] = "xoxb-env-2"
+			send()
+			reconcileConfig()
+			send()
+			Expect(slackFk.sent).To(HaveLen(3))
+			Expect(slackFk.sent[0].Token).To(Equal("xoxb-env-1"))
+			Expect(slackFk.sent[1].Token).To(Equal("xoxb-env-1"))
+			Expect(slackFk.sent[2].Token).To(Equal("xoxb-env-2"))
+		})
+
+		It("is degraded when the environment variable is not set", func() {
+			delete(env, "SLACK_TOKEN")
+			cond := reconcileConfig()
+			Expect(cond.Reason).To(Equal(ReasonSecretNotFound))
+			Expect(cond.Message).To(Equal("environment variable SLACK_TOKEN referenced by tokenSecretRef was not found"))
+		})
+
+		It("is not available to SlackConfigs in other namespaces", func() {
+			config.Namespace = "team-a"
+			rule.Namespace = "team-a"
+			Expect(reconcileConfig().Reason).To(Equal(ReasonCredentialProviderNotAllowed))
+
+			notifier := &Notifier{Client: c, SlackClient: slackFk, CredentialResolver: resolver, ClusterResourceNamespace: controllerNamespace}
+			err := notifier.ResolveAndSend(ctx, &batchv1.Job{}, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])
+			Expect(err).To(MatchError(errCredentialProviderNotAllowed))
+			Expect(slackFk.sent).To(BeEmpty())
+		})
+	})
+	Context("When rotating a token", func() {
+		var (
+			ctx           context.Context
+			clock         *clocktesting.FakeClock
+			slackFk       *fakeSlackClient
+			refreshTokens []string
+			config        *notificationv1alpha1.SlackConfig
+			oauthSecret   *corev1.Secret
+			c             client.Client
+			r             *SlackConfigReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			clock = clocktesting.NewFakeClock(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+			refreshTokens = nil
+
+			// A fake oauth.v2.access endpoint that accepts the last refresh token it issued.
+			valid := "xoxe-0"
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/oauth.v2.access"))
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("grant_type")).To(Equal("refresh_token"))
+				Expect(req.PostForm.Get("client_id")).To(Equal("123.456"))
+				Expect(req.PostForm.Get("client_secret")).To(Equal("client-secret"))
+				token := req.PostForm.Get("refresh_token")
+				refreshTokens = append(refreshTokens, token)
+				w.Header().Set("Content-Type", "application/json")
+				if token != valid {
+					_, _ = fmt.Fprint(w, `{"ok":false,"error":"invalid_refresh_token"}`)
+					return
+				}
+				n := len(refreshTokens)
+				valid = fmt.Sprintf("xoxe-%d", n)
+				_, _ = fmt.Fprintf(w, `{"ok":true,"access_token":"xoxe.xoxb-%d","refresh_token":%q,"expires_in":43200,"token_type":"bot"}`, n, valid)
+			}))
+			DeferCleanup(server.Close)
+
+			slackFk = &fakeSlackClient{
+				channels: map[string]*goslack.Channel{"#alerts": {IsMember: true}},
+				oauth:    slack.NewClient(slack.WithAPIURL(server.URL + "/")),
+			}
+			oauthSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack-oauth", Namespace: "team-a"},
+				Data: map[string][]byte{
+					"client-secret": []byte("client-secret"),
+					"refresh-token": []byte("xoxe-0"),
+				},
+			}
+			config = &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType: "Token",
+					Channel:  "#alerts",
+					TokenSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "slack-token"},
+						Key:                  "token",
+					},
+					TokenRotation: &notificationv1alpha1.SlackTokenRotation{
+						ClientID: "123.456",
+						ClientSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "slack-oauth"},
+							Key:                  "client-secret",
+						},
+						RefreshTokenSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "slack-oauth"},
+							Key:                  "refresh-token",
+						},
+					},
+				},
+			}
+		})
+
+		build := func() {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, oauthSecret).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r = &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk, Clock: clock, RecheckInterval: 24 * time.Hour}
+		}
+
+		// reconcileConfig reconciles config and returns its status and when it is requeued.
+		reconcileConfig := func() (notificationv1alpha1.SlackConfigStatus, time.Duration) {
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+			Expect(err).NotTo(HaveOccurred())
+			var got notificationv1alpha1.SlackConfig
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(config), &got)).To(Succeed())
+			return got.Status, result.RequeueAfter
+		}
+
+		managedSecret := func() *corev1.Secret {
+			var secret corev1.Secret
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "slack-token"}, &secret)).To(Succeed())
+			return &secret
+		}
+
+		It("stores the refreshed token and refreshes it again before it expires", func() {
+			build()
+			status, requeueAfter := reconcileConfig()
+			Expect(meta.IsStatusConditionTrue(status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(status.TokenExpirationTime.Time).To(BeTemporally("==", clock.Now().Add(12*time.Hour)))
+			Expect(requeueAfter).To(Equal(11 * time.Hour))
+			secret := managedSecret()
+			Expect(string(secret.Data["token"])).To(Equal("xoxe.xoxb-1"))
+			Expect(string(secret.Data[notificationv1alpha1.RotatedRefreshTokenKey])).To(Equal("xoxe-1"))
+			Expect(secret.Annotations).To(HaveKeyWithValue(AnnotationTokenExpiresAt, "2025-01-15T22:00:00Z"))
+
+			By("keeping the token while it is valid for longer than the margin")
+			clock.Step(10 * time.Hour)
+			_, requeueAfter = reconcileConfig()
+			Expect(refreshTokens).To(HaveLen(1))
+			Expect(requeueAfter).To(Equal(time.Hour))
+
+			By("refreshing it with the stored refresh token once it is due")
+			clock.Step(time.Hour)
+			status, _ = reconcileConfig()
+			Expect(refreshTokens).To(Equal([]string{"xoxe-0", "xoxe-1"}))
+			Expect(meta.IsStatusConditionTrue(status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(status.TokenExpirationTime.Time).To(BeTemporally("==", clock.Now().Add(12*time.Hour)))
+			Expect(string(managedSecret().Data["token"])).To(Equal("xoxe.xoxb-2"))
+		})
+
+		It("keeps the refreshed token when the Secret changes while it is stored", func() {
+			stale := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "slack-token",
+					Namespace: "team-a",
+					Labels:    map[string]string{LabelManagedBy: ManagedByController},
+				},
+				Data: map[string][]byte{"token": []byte("xoxe.xoxb-0")},
+			}
+			updates := 0
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, oauthSecret, stale).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if updates++; updates == 1 {
+							// Someone else changes the Secret after it was read.
+							var current corev1.Secret
+							Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), &current)).To(Succeed())
+							current.Labels["team"] = "a"
+							Expect(cl.Update(ctx, &current)).To(Succeed())
+						}
+						return cl.Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r = &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk, Clock: clock, RecheckInterval: 24 * time.Hour}
+
+			status, _ := reconcileConfig()
+			Expect(meta.IsStatusConditionTrue(status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(refreshTokens).To(HaveLen(1))
+			Expect(updates).To(Equal(2))
+			secret := managedSecret()
+			Expect(string(secret.Data["token"])).To(Equal("xoxe.xoxb-1"))
+			Expect(string(secret.Data[notificationv1alpha1.RotatedRefreshTokenKey])).To(Equal("xoxe-1"))
+			Expect(secret.Labels).To(HaveKeyWithValue("team", "a"))
+		})
+
+		It("does not write to a Secret it does not manage", func() {
+			other := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack-token", Namespace: "team-a"},
+				Data:       map[string][]byte{"token": []byte("someone else's")},
+			}
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, oauthSecret, other).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				Build()
+			r = &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk, Clock: clock, RecheckInterval: 24 * time.Hour}
+
+			status, _ := reconcileConfig()
+			degraded := meta.FindStatusCondition(status.Conditions, notificationv1alpha1.ConditionDegraded)
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal(ReasonTokenSecretNotManaged))
+			Expect(refreshTokens).To(BeEmpty())
+			Expect(string(managedSecret().Data["token"])).To(Equal("someone else's"))
+		})
+
+		It("retries storing the refreshed token and reports when it keeps failing", func() {
+			creates := 0
+			failures := 1
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(config, oauthSecret).
+				WithStatusSubresource(&notificationv1alpha1.SlackConfig{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if _, ok := obj.(*corev1.Secret); ok {
+							if creates++; creates <= failures {
+								return errors.NewServiceUnavailable("etcd is unavailable")
+							}
+						}
+						return cl.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r = &SlackConfigReconciler{Client: c, Scheme: c.Scheme(), SlackClient: slackFk, Clock: clock, RecheckInterval: 24 * time.Hour}
+
+			status, _ := reconcileConfig()
+			Expect(meta.IsStatusConditionTrue(status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(creates).To(Equal(2))
+			secret := managedSecret()
+			Expect(string(secret.Data[notificationv1alpha1.RotatedRefreshTokenKey])).To(Equal("xoxe-1"))
+			Expect(secret.Labels).To(HaveKeyWithValue(LabelManagedBy, ManagedByController))
+
+			By("reporting a condition once the retries are exhausted")
+			Expect(c.Delete(ctx, secret)).To(Succeed())
+			oauthSecret.Data["refresh-token"] = []byte("xoxe-1")
+			Expect(c.Update(ctx, oauthSecret)).To(Succeed())
+			creates, failures = 0, 100
+			status, _ = reconcileConfig()
+			degraded := meta.FindStatusCondition(status.Conditions, notificationv1alpha1.ConditionDegraded)
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal(ReasonTokenStoreFailed))
+			Expect(degraded.Message).To(ContainSubstring("etcd is unavailable"))
+		})
+
+		It("is degraded when Slack refuses the refresh token", func() {
+			oauthSecret.Data["refresh-token"] = []byte("xoxe-revoked")
+			build()
+			status, _ := reconcileConfig()
+			degraded := meta.FindStatusCondition(status.Conditions, notificationv1alpha1.ConditionDegraded)
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal(ReasonTokenRefreshFailed))
+			Expect(degraded.Message).To(ContainSubstring("invalid_refresh_token"))
+			Expect(status.TokenExpirationTime).To(BeNil())
 		})
 	})
 })
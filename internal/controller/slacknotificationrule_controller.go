@@ -18,46 +18,401 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
+	argov1alpha1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
 )
 
+// Reasons of the Ready condition of SlackNotificationRule.
+const (
+	ReasonRuleReady      = "Ready"
+	ReasonInvalidSpec    = "InvalidSpec"
+	ReasonConfigNotFound = "SlackConfigNotFound"
+	ReasonConfigNotReady = "SlackConfigNotReady"
+	// ReasonReferenceNotGranted is reported for a rule referencing a
+	// SlackConfig in another namespace without a SlackConfigGrant allowing it.
+	ReasonReferenceNotGranted = "ReferenceNotGranted"
+	// ReasonPolicyViolation is reported for a rule posting to a channel or
+	// with a mention forbidden by a channel policy. It is also the reason of
+	// the events recorded for such rules.
+	ReasonPolicyViolation = "PolicyViolation"
+)
+
+// slackConfigRefIndex indexes SlackNotificationRules and
+// ClusterSlackNotificationRules by the kind and name of their SlackConfig, as
+// returned by configRefKey, and of the SinkConfigs of their destinations.
+const slackConfigRefIndex = "spec.slackConfigRef"
+
+// maxListedMatchedTargets bounds the matched targets listed in the status.
+const maxListedMatchedTargets = 50
+
 // SlackNotificationRuleReconciler reconciles a SlackNotificationRule object
 type SlackNotificationRuleReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder records an event when a rule starts violating a channel
+	// policy. Events are not recorded if it is nil.
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slacknotificationrules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slacknotificationrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=notification.murasame29.com,resources=slacknotificationrules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=clusterslackconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=sinkconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackconfiggrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=notification.murasame29.com,resources=slackchannelpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=cronworkflows,verbs=get;list;watch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the SlackNotificationRule object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+// Reconcile validates a SlackNotificationRule, checks that its SlackConfig or
+// ClusterSlackConfig is Ready and that its channels and titles comply with the
+// channel policies, and reports the result with the Ready condition, along with the
+// CronJobs or CronWorkflows the rule currently matches. The sent and failed
+// counts in the status are maintained by the Notifier.
 func (r *SlackNotificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	logger := logf.FromContext(ctx)
+
+	var rule notificationv1alpha1.SlackNotificationRule
+	if err := r.Get(ctx, req.NamespacedName, &rule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	status := rule.Status.DeepCopy()
 
-	// TODO(user): your logic here
+	reason, message, err := r.check(ctx, &rule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if reason != ReasonRuleReady {
+		logger.Info("SlackNotificationRule is not ready", "reason", reason, "message", message)
+	}
+	if reason == ReasonPolicyViolation && r.Recorder != nil {
+		if cond := meta.FindStatusCondition(status.Conditions, notificationv1alpha1.ConditionReady); cond == nil || cond.Reason != reason || cond.Message != message {
+			r.Recorder.Event(&rule, corev1.EventTypeWarning, ReasonPolicyViolation, message)
+		}
+	}
+	setRuleReadyCondition(&rule.Status, rule.Generation, reason, message)
 
+	if !equality.Semantic.DeepEqual(status, &rule.Status) {
+		if err := r.Status().Update(ctx, &rule); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update SlackNotificationRule status: %w", err)
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
+// check validates the rule and records the targets it matches in its status.
+// It returns the reason and message of the Ready condition.
+func (r *SlackNotificationRuleReconciler) check(ctx context.Context, rule *notificationv1alpha1.SlackNotificationRule) (string, string, error) {
+	if errs := validation.ValidateRuleSpec(&rule.Spec, field.NewPath("spec")); len(errs) > 0 {
+		rule.Status.MatchedTargets = nil
+		rule.Status.MatchedTargetCount = 0
+		return ReasonInvalidSpec, errs.ToAggregate().Error(), nil
+	}
+
+	targets, err := r.matchedTargets(ctx, rule)
+	if err != nil {
+		return "", "", err
+	}
+	rule.Status.MatchedTargetCount = int32(len(targets))
+	rule.Status.MatchedTargets = targets[:min(len(targets), maxListedMatchedTargets)]
+
+	rule.Status.Destinations = pruneDestinationStatuses(*rule)
+
+	if reason, message, err := checkSlackConfigs(ctx, r.Client, rule); err != nil || reason != ReasonRuleReady {
+		return reason, message, err
+	}
+	p, err := policy.ForRule(ctx, r.Client, rule)
+	if err != nil {
+		return "", "", err
+	}
+	errs := p.CheckRule(&rule.Spec, field.NewPath("spec"))
+	destErrs, err := policy.CheckDestinations(ctx, r.Client, rule, field.NewPath("spec"))
+	if err != nil {
+		return "", "", err
+	}
+	if errs = append(errs, destErrs...); len(errs) > 0 {
+		return ReasonPolicyViolation, errs.ToAggregate().Error(), nil
+	}
+	return ReasonRuleReady, fmt.Sprintf("Matches %d %s(s)", len(targets), rule.Spec.TargetResource), nil
+}
+
+// checkSlackConfig checks that the SlackConfig or ClusterSlackConfig a rule
+// references exists, is granted to the rule if it is in another namespace,
+// and is Ready. It returns ReasonRuleReady if it is, or the reason and message
+// of the Ready condition of the rule otherwise.
+func checkSlackConfig(ctx context.Context, c client.Reader, rule *notificationv1alpha1.SlackNotificationRule) (string, string, error) {
+	kind, name := configRefKind(*rule), rule.Spec.SlackConfigRef.Name
+	var conditions []metav1.Condition
+	var err error
+	if kind == notificationv1alpha1.KindClusterSlackConfig {
+		var config notificationv1alpha1.ClusterSlackConfig
+		err = c.Get(ctx, types.NamespacedName{Name: name}, &config)
+		conditions = config.Status.Conditions
+	} else {
+		namespace := slackConfigNamespace(*rule)
+		if namespace != rule.Namespace {
+			err := checkReferenceGrant(ctx, c, rule.Namespace, namespace, name)
+			if errors.Is(err, errReferenceNotGranted) {
+				return ReasonReferenceNotGranted, err.Error(), nil
+			}
+			if err != nil {
+				return "", "", err
+			}
+			name = namespace + "/" + name
+		}
+		var config notificationv1alpha1.SlackConfig
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: rule.Spec.SlackConfigRef.Name}, &config)
+		conditions = config.Status.Conditions
+	}
+	return configReadiness(kind, name, conditions, err)
+}
+
+// configReadiness returns ReasonRuleReady if the configuration kind name
+// with conditions, read with err, is Ready, or the reason and message of the
+// Ready condition of the rule referencing it otherwise.
+func configReadiness(kind, name string, conditions []metav1.Condition, err error) (string, string, error) {
+	if apierrors.IsNotFound(err) {
+		return ReasonConfigNotFound, fmt.Sprintf("%s %s not found", kind, name), nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+	cond := meta.FindStatusCondition(conditions, notificationv1alpha1.ConditionReady)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		message := fmt.Sprintf("%s %s has not been verified yet", kind, name)
+		if cond != nil {
+			message = fmt.Sprintf("%s %s is not ready: %s", kind, name, cond.Message)
+		}
+		return ReasonConfigNotReady, message, nil
+	}
+	return ReasonRuleReady, "", nil
+}
+
+// checkSlackConfigs checks the configuration a rule references and those of
+// the destinations of its notifications like checkSlackConfig does, and that
+// the SinkConfigs of its destinations exist and are Ready.
+func checkSlackConfigs(ctx context.Context, c client.Reader, rule *notificationv1alpha1.SlackNotificationRule) (string, string, error) {
+	for _, view := range configViews(*rule) {
+		if reason, message, err := checkSlackConfig(ctx, c, &view); err != nil || reason != ReasonRuleReady {
+			return reason, message, err
+		}
+	}
+	for _, name := range sinkNames(*rule) {
+		var config notificationv1alpha1.SinkConfig
+		err := c.Get(ctx, types.NamespacedName{Namespace: rule.Namespace, Name: name}, &config)
+		if reason, message, err := configReadiness(kindSinkConfig, name, config.Status.Conditions, err); err != nil || reason != ReasonRuleReady {
+			return reason, message, err
+		}
+	}
+	return ReasonRuleReady, "", nil
+}
+
+// setRuleReadyCondition sets the Ready condition of a rule from the reason and message returned by its check.
+func setRuleReadyCondition(status *notificationv1alpha1.SlackNotificationRuleStatus, generation int64, reason, message string) {
+	ready := metav1.ConditionFalse
+	if reason == ReasonRuleReady {
+		ready = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               notificationv1alpha1.ConditionReady,
+		Status:             ready,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// matchedTargets returns the sorted names of the CronJobs or CronWorkflows the rule selects.
+func (r *SlackNotificationRuleReconciler) matchedTargets(ctx context.Context, rule *notificationv1alpha1.SlackNotificationRule) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&rule.Spec.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := listTargets(ctx, r.Client, rule.Spec.TargetResource, client.InNamespace(rule.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// listTargets lists the CronJobs or CronWorkflows matching opts.
+func listTargets(ctx context.Context, c client.Reader, targetResource string, opts ...client.ListOption) ([]types.NamespacedName, error) {
+	var targets []types.NamespacedName
+	switch targetResource {
+	case "CronJob":
+		var cronJobs batchv1.CronJobList
+		if err := c.List(ctx, &cronJobs, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list CronJobs: %w", err)
+		}
+		for _, cronJob := range cronJobs.Items {
+			targets = append(targets, client.ObjectKeyFromObject(&cronJob))
+		}
+	case "CronWorkflow":
+		var cronWfs argov1alpha1.CronWorkflowList
+		if err := c.List(ctx, &cronWfs, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list CronWorkflows: %w", err)
+		}
+		for _, cronWf := range cronWfs.Items {
+			targets = append(targets, client.ObjectKeyFromObject(&cronWf))
+		}
+	}
+	return targets, nil
+}
+
+// rulesForConfig maps a SlackConfig to the rules referencing it from any namespace.
+func (r *SlackNotificationRuleReconciler) rulesForConfig(ctx context.Context, config client.Object) []reconcile.Request {
+	key := configRefKey(notificationv1alpha1.KindSlackConfig, config.GetNamespace(), config.GetName())
+	return r.rulesInNamespace(ctx, metav1.NamespaceAll, client.MatchingFields{slackConfigRefIndex: key})
+}
+
+// rulesForGrant maps a SlackConfigGrant to the rules in other namespaces
+// referencing SlackConfigs in its namespace.
+func (r *SlackNotificationRuleReconciler) rulesForGrant(ctx context.Context, grant client.Object) []reconcile.Request {
+	var rules notificationv1alpha1.SlackNotificationRuleList
+	if err := r.List(ctx, &rules); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SlackNotificationRules")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, rule := range rules.Items {
+		if rule.Namespace == grant.GetNamespace() {
+			continue
+		}
+		for _, view := range configViews(rule) {
+			if slackConfigNamespace(view) == grant.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: rule.Namespace, Name: rule.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// rulesForSink maps a SinkConfig to the rules in its namespace delivering to it.
+func (r *SlackNotificationRuleReconciler) rulesForSink(ctx context.Context, config client.Object) []reconcile.Request {
+	key := configRefKey(kindSinkConfig, config.GetNamespace(), config.GetName())
+	return r.rulesInNamespace(ctx, config.GetNamespace(), client.MatchingFields{slackConfigRefIndex: key})
+}
+
+// rulesForClusterConfig maps a ClusterSlackConfig to the rules referencing it in any namespace.
+func (r *SlackNotificationRuleReconciler) rulesForClusterConfig(ctx context.Context, config client.Object) []reconcile.Request {
+	key := configRefKey(notificationv1alpha1.KindClusterSlackConfig, "", config.GetName())
+	return r.rulesInNamespace(ctx, metav1.NamespaceAll, client.MatchingFields{slackConfigRefIndex: key})
+}
+
+// rulesForPolicy maps a SlackChannelPolicy to all rules, as a change of its
+// namespace selector may add any of them to or remove any from it.
+func (r *SlackNotificationRuleReconciler) rulesForPolicy(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.rulesInNamespace(ctx, metav1.NamespaceAll)
+}
+
+// rulesForNamespace maps a Namespace to its rules, as a label change may make
+// channel policies select it or stop selecting it.
+func (r *SlackNotificationRuleReconciler) rulesForNamespace(ctx context.Context, ns client.Object) []reconcile.Request {
+	return r.rulesInNamespace(ctx, ns.GetName())
+}
+
+// rulesForTarget maps a CronJob or CronWorkflow to the rules in its namespace,
+// as a label change may add it to or remove it from any of them.
+func (r *SlackNotificationRuleReconciler) rulesForTarget(ctx context.Context, target client.Object) []reconcile.Request {
+	return r.rulesInNamespace(ctx, target.GetNamespace())
+}
+
+func (r *SlackNotificationRuleReconciler) rulesInNamespace(ctx context.Context, namespace string, opts ...client.ListOption) []reconcile.Request {
+	var rules notificationv1alpha1.SlackNotificationRuleList
+	if err := r.List(ctx, &rules, append(opts, client.InNamespace(namespace))...); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SlackNotificationRules", "namespace", namespace)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(rules.Items))
+	for _, rule := range rules.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: rule.Namespace, Name: rule.Name}})
+	}
+	return requests
+}
+
+// configRefKey is the value rules are indexed by for the configuration they
+// reference. namespace is empty for a ClusterSlackConfig.
+func configRefKey(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+func slackConfigRefKey(obj client.Object) []string {
+	var rule notificationv1alpha1.SlackNotificationRule
+	switch obj := obj.(type) {
+	case *notificationv1alpha1.SlackNotificationRule:
+		rule = *obj
+	case *notificationv1alpha1.ClusterSlackNotificationRule:
+		rule = clusterRuleView(obj)
+	default:
+		return nil
+	}
+	var keys []string
+	for _, view := range configViews(rule) {
+		if view.Spec.SlackConfigRef.Name == "" {
+			continue
+		}
+		kind := configRefKind(view)
+		namespace := ""
+		if kind == notificationv1alpha1.KindSlackConfig {
+			namespace = slackConfigNamespace(view)
+		}
+		keys = append(keys, configRefKey(kind, namespace, view.Spec.SlackConfigRef.Name))
+	}
+	for _, name := range sinkNames(rule) {
+		keys = append(keys, configRefKey(kindSinkConfig, rule.Namespace, name))
+	}
+	return keys
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SlackNotificationRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&notificationv1alpha1.SlackNotificationRule{}).
+		Watches(&notificationv1alpha1.SlackConfig{}, handler.EnqueueRequestsFromMapFunc(r.rulesForConfig)).
+		Watches(&notificationv1alpha1.ClusterSlackConfig{}, handler.EnqueueRequestsFromMapFunc(r.rulesForClusterConfig)).
+		Watches(&notificationv1alpha1.SinkConfig{}, handler.EnqueueRequestsFromMapFunc(r.rulesForSink)).
+		Watches(&notificationv1alpha1.SlackConfigGrant{}, handler.EnqueueRequestsFromMapFunc(r.rulesForGrant)).
+		Watches(&notificationv1alpha1.SlackChannelPolicy{}, handler.EnqueueRequestsFromMapFunc(r.rulesForPolicy)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.rulesForNamespace)).
+		Watches(&batchv1.CronJob{}, handler.EnqueueRequestsFromMapFunc(r.rulesForTarget)).
+		Watches(&argov1alpha1.CronWorkflow{}, handler.EnqueueRequestsFromMapFunc(r.rulesForTarget)).
 		Named("slacknotificationrule").
 		Complete(r)
 }
@@ -18,16 +18,27 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
 )
 
 var _ = Describe("SlackNotificationRule Controller", func() {
@@ -45,13 +56,17 @@ var _ = Describe("SlackNotificationRule Controller", func() {
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind SlackNotificationRule")
 			err := k8sClient.Get(ctx, typeNamespacedName, slacknotificationrule)
-			if err != nil && errors.IsNotFound(err) {
+			if err != nil && apierrors.IsNotFound(err) {
 				resource := &notificationv1alpha1.SlackNotificationRule{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+						TargetResource: "CronJob",
+						SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "missing"},
+						Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed"}},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -77,8 +92,580 @@ var _ = Describe("SlackNotificationRule Controller", func() {
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			resource := &notificationv1alpha1.SlackNotificationRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			cond := meta.FindStatusCondition(resource.Status.Conditions, notificationv1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(ReasonConfigNotFound))
+		})
+	})
+
+	Context("When checking a rule", func() {
+		const namespace = "team-a"
+
+		var (
+			ctx    context.Context
+			rule   *notificationv1alpha1.SlackNotificationRule
+			config *notificationv1alpha1.SlackConfig
+			c      client.Client
+		)
+
+		cronJob := func(name string, labels map[string]string) *batchv1.CronJob {
+			return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace, Generation: 3},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					LabelSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+					Notifications:  []notificationv1alpha1.NotificationRule{{Status: "failed", Title: "{{ .metadata.name }} failed"}},
+				},
+			}
+			config = &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+				Status: notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+					Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+				}}},
+			}
+		})
+
+		// reconcileRule reconciles rule and returns it with its Ready condition.
+		reconcileRule := func() (*notificationv1alpha1.SlackNotificationRule, *metav1.Condition) {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(rule, config, cronJob("backup-b", map[string]string{"team": "a"}), cronJob("backup-a", map[string]string{"team": "a"}), cronJob("cleanup", nil)).
+				WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+				WithIndex(&notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+				Build()
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			cond := meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.ObservedGeneration).To(Equal(int64(3)))
+			return &got, cond
+		}
+
+		It("is ready and lists the matched CronJobs", func() {
+			got, cond := reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(ReasonRuleReady))
+			Expect(got.Status.MatchedTargets).To(Equal([]string{"backup-a", "backup-b"}))
+			Expect(got.Status.MatchedTargetCount).To(Equal(int32(2)))
+		})
+
+		It("rejects statuses the target resource never reports", func() {
+			rule.Spec.Notifications[0].Status = "Error"
+			got, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonInvalidSpec))
+			Expect(cond.Message).To(ContainSubstring("spec.notifications[0].status"))
+			Expect(got.Status.MatchedTargets).To(BeEmpty())
+		})
+
+		It("rejects invalid label selectors", func() {
+			rule.Spec.LabelSelector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}}}
+			_, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonInvalidSpec))
+			Expect(cond.Message).To(ContainSubstring("spec.labelSelector"))
+		})
+
+		It("rejects title templates that do not parse", func() {
+			rule.Spec.Notifications[0].Title = "{{ .metadata.name"
+			_, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonInvalidSpec))
+			Expect(cond.Message).To(ContainSubstring("spec.notifications[0].title"))
+		})
+
+		It("is not ready while its SlackConfig is degraded", func() {
+			config.Status.Conditions[0].Status = metav1.ConditionFalse
+			config.Status.Conditions[0].Message = "channel #alerts is archived"
+			got, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonConfigNotReady))
+			Expect(cond.Message).To(ContainSubstring("archived"))
+			Expect(got.Status.MatchedTargetCount).To(Equal(int32(2)))
+		})
+
+		It("reconciles the rules referencing a changed SlackConfig", func() {
+			reconcileRule()
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.rulesForConfig(ctx, config)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+			other := config.DeepCopy()
+			other.Name = "other"
+			Expect(r.rulesForConfig(ctx, other)).To(BeEmpty())
+		})
+
+		It("counts sent and failed notifications", func() {
+			reconcileRule()
+			notifier := &Notifier{Client: c}
+			notifier.recordDelivery(ctx, *rule, nil, nil, nil)
+			notifier.recordDelivery(ctx, *rule, nil, nil, nil)
+			notifier.recordDelivery(ctx, *rule, nil, nil, errors.New("channel_not_found"))
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			Expect(got.Status.SentCount).To(Equal(int64(2)))
+			Expect(got.Status.FailedCount).To(Equal(int64(1)))
+			Expect(got.Status.LastNotificationTime).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(got.Status.Conditions, notificationv1alpha1.ConditionReady)).To(BeTrue())
+		})
+	})
+
+	Context("When referencing a SlackConfig in another namespace", func() {
+		const sharedNamespace = "shared-notify"
+
+		var (
+			ctx     context.Context
+			rule    *notificationv1alpha1.SlackNotificationRule
+			grant   *notificationv1alpha1.SlackConfigGrant
+			objects []client.Object
+			c       client.Client
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "shared", Namespace: sharedNamespace},
+					Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed"}},
+				},
+			}
+			grant = &notificationv1alpha1.SlackConfigGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: sharedNamespace},
+				Spec: notificationv1alpha1.SlackConfigGrantSpec{
+					From: []notificationv1alpha1.SlackConfigGrantFrom{{Namespace: "team-a"}},
+					To:   []notificationv1alpha1.SlackConfigGrantTo{{Name: "shared"}},
+				},
+			}
+			objects = []client.Object{
+				rule,
+				&notificationv1alpha1.SlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: sharedNamespace},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType:       "Token",
+						Channel:        "#shared",
+						TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					},
+					Status: notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+						Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+					}}},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: sharedNamespace},
+					Data:       map[string][]byte{"token": []byte("xoxb-shared")},
+				},
+			}
+		})
+
+		reconcileRule := func() *metav1.Condition {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(objects...).
+				WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+				WithIndex(&notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+				Build()
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			return meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+		}
+
+		It("uses the SlackConfig and its Secrets when a grant allows the namespace", func() {
+			objects = append(objects, grant)
+			cond := reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+			slackFk := &fakeSlackClient{}
+			notifier := &Notifier{Client: c, SlackClient: slackFk}
+			cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "team-a"}}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"}}
+			_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slackFk.sent).To(ConsistOf(sentMessage{Token: "xoxb-shared", Channel: "#shared", Title: "backup-1 failed"}))
+
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.rulesForGrant(ctx, grant)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+			Expect(r.rulesForConfig(ctx, objects[1])).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+		})
+
+		It("is not ready without a grant", func() {
+			cond := reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(ReasonReferenceNotGranted))
+			Expect(cond.Message).To(ContainSubstring("namespace team-a"))
+
+			slackFk := &fakeSlackClient{}
+			notifier := &Notifier{Client: c, SlackClient: slackFk}
+			Expect(notifier.ResolveAndSend(ctx, &batchv1.Job{}, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])).To(MatchError(errReferenceNotGranted))
+			Expect(slackFk.sent).To(BeEmpty())
+		})
+
+		It("is not ready when the grant is for other namespaces or SlackConfigs", func() {
+			grant.Spec.From[0].Namespace = "team-b"
+			other := grant.DeepCopy()
+			other.Name = "other-config"
+			other.Spec.From[0].Namespace = "team-a"
+			other.Spec.To[0].Name = "private"
+			objects = append(objects, grant, other)
+			Expect(reconcileRule().Reason).To(Equal(ReasonReferenceNotGranted))
+		})
+
+		It("allows all SlackConfigs of the namespace when the grant lists none", func() {
+			grant.Spec.To = nil
+			objects = append(objects, grant)
+			Expect(reconcileRule().Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When channel policies apply to a rule", func() {
+		var (
+			ctx      context.Context
+			rule     *notificationv1alpha1.SlackNotificationRule
+			config   *notificationv1alpha1.SlackConfig
+			objects  []client.Object
+			c        client.Client
+			recorder *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			recorder = record.NewFakeRecorder(10)
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+					Notifications: []notificationv1alpha1.NotificationRule{{
+						Status: "Failed", Channel: "#team-a-alerts", Title: "{{ .metadata.name }} failed",
+					}},
+				},
+			}
+			config = &notificationv1alpha1.SlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:       "Token",
+					Channel:        "#team-a",
+					TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					ChannelPolicies: []notificationv1alpha1.ChannelPolicy{{
+						AllowedChannels:           []string{"#team-a", "#team-a-*"},
+						DisallowBroadcastMentions: true,
+					}},
+				},
+				Status: notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+					Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+				}}},
+			}
+			objects = []client.Object{
+				rule,
+				config,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+					Data:       map[string][]byte{"token": []byte("xoxb-team-a")},
+				},
+			}
+		})
+
+		reconcileRule := func() *metav1.Condition {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(objects...).
+				WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+				Build()
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			return meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+		}
+
+		It("is ready and sends notifications to allowed channels", func() {
+			Expect(reconcileRule().Status).To(Equal(metav1.ConditionTrue))
+			Expect(recorder.Events).To(BeEmpty())
+
+			slackFk := &fakeSlackClient{}
+			notifier := &Notifier{Client: c, SlackClient: slackFk, Recorder: recorder}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"}}
+			Expect(notifier.ResolveAndSend(ctx, job, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])).To(Succeed())
+			Expect(slackFk.sent).To(ConsistOf(sentMessage{Token: "xoxb-team-a", Channel: "#team-a-alerts", Title: "backup-1 failed"}))
+		})
+
+		It("reports channels the SlackConfig does not allow", func() {
+			rule.Spec.Notifications[0].Channel = "#general"
+			cond := reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(ReasonPolicyViolation))
+			Expect(cond.Message).To(ContainSubstring("spec.notifications[0].channel"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning PolicyViolation")))
+		})
+
+		It("checks the default channel of the SlackConfig", func() {
+			rule.Spec.Notifications[0].Channel = ""
+			config.Spec.Channel = "#general"
+			Expect(reconcileRule().Reason).To(Equal(ReasonPolicyViolation))
+		})
+
+		It("applies SlackChannelPolicies selecting the namespace", func() {
+			channelPolicy := &notificationv1alpha1.SlackChannelPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "alerts-only"},
+				Spec: notificationv1alpha1.ChannelPolicy{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					AllowedChannels:   []string{"#*-alerts"},
+				},
+			}
+			rule.Spec.Notifications[0].Escalation = &notificationv1alpha1.Escalation{After: metav1.Duration{Duration: time.Hour}, Channel: "#team-a"}
+			objects = append(objects, channelPolicy)
+			cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonPolicyViolation))
+			Expect(cond.Message).To(ContainSubstring("spec.notifications[0].escalation.channel"))
+			Expect(cond.Message).To(ContainSubstring("SlackChannelPolicy alerts-only"))
+
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.rulesForPolicy(ctx, channelPolicy)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+			Expect(r.rulesForNamespace(ctx, objects[2])).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+		})
+
+		It("ignores SlackChannelPolicies selecting other namespaces", func() {
+			objects = append(objects, &notificationv1alpha1.SlackChannelPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-b"},
+				Spec: notificationv1alpha1.ChannelPolicy{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+					AllowedChannels:   []string{"#team-b"},
+				},
+			})
+			Expect(reconcileRule().Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("blocks notifications violating a policy at send time", func() {
+			reconcileRule()
+			rule.Spec.Notifications[0].Title = "<!channel> {{ .metadata.name }} failed"
+
+			slackFk := &fakeSlackClient{}
+			notifier := &Notifier{Client: c, SlackClient: slackFk, Recorder: recorder}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"}}
+			err := notifier.ResolveAndSend(ctx, job, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])
+			Expect(err).To(MatchError(policy.ErrViolation))
+			Expect(err.Error()).To(ContainSubstring("<!channel> mentions are not allowed"))
+
+			rule.Spec.Notifications[0].Title = "{{ .metadata.name }} failed"
+			rule.Spec.Notifications[0].Channel = "#general"
+			Expect(notifier.ResolveAndSend(ctx, job, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])).To(MatchError(policy.ErrViolation))
+			Expect(slackFk.sent).To(BeEmpty())
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Failed notification not sent")))
+		})
+
+		It("blocks broadcast mentions in the fields of notifications and in sink payloads", func() {
+			objects = append(objects,
+				&notificationv1alpha1.SinkConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "discord", Namespace: "team-a"},
+					Spec: notificationv1alpha1.SinkConfigSpec{
+						Type:         sink.TypeDiscord,
+						URLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "discord"}, Key: "url"},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "discord", Namespace: "team-a"},
+					Data:       map[string][]byte{"url": []byte("http://discord.invalid/webhook")},
+				},
+			)
+			reconcileRule()
+
+			slackFk := &fakeSlackClient{}
+			notifier := &Notifier{Client: c, SlackClient: slackFk, Recorder: recorder}
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"},
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+					Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "@here the disk is full",
+				}}},
+			}
+			err := notifier.ResolveAndSend(ctx, job, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0])
+			Expect(err).To(MatchError(policy.ErrViolation))
+			Expect(err.Error()).To(ContainSubstring("@here mentions are not allowed"))
+			Expect(slackFk.sent).To(BeEmpty())
+
+			err = notifier.SendToSink(ctx, job, &batchv1.CronJob{}, *rule, rule.Spec.Notifications[0], "discord")
+			Expect(err).To(MatchError(policy.ErrViolation))
+		})
+	})
+	Context("When a notification has several destinations", func() {
+		const controllerNamespace = "slack-notifier-system"
+
+		var (
+			ctx     context.Context
+			rule    *notificationv1alpha1.SlackNotificationRule
+			sre     *notificationv1alpha1.ClusterSlackConfig
+			objects []client.Object
+			c       client.Client
+			slackFk *fakeSlackClient
+		)
+
+		ready := notificationv1alpha1.SlackConfigStatus{Conditions: []metav1.Condition{{
+			Type: notificationv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonVerified,
+		}}}
+		sreRef := notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "sre"}
+		teamRef := notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindSlackConfig, Name: "slack", Namespace: "team-a"}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			slackFk = &fakeSlackClient{}
+			rule = &notificationv1alpha1.SlackNotificationRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "team-a"},
+				Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+					Notifications: []notificationv1alpha1.NotificationRule{{
+						Status: "Failed",
+						Title:  "{{ .metadata.name }} failed",
+						Destinations: []notificationv1alpha1.NotificationDestination{
+							{Channel: "#team-a-alerts"},
+							{SlackConfigRef: &sreRef},
+						},
+					}},
+				},
+			}
+			sre = &notificationv1alpha1.ClusterSlackConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "sre"},
+				Spec: notificationv1alpha1.SlackConfigSpec{
+					AuthType:       "Token",
+					Channel:        "#sre",
+					TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "sre"}, Key: "token"},
+				},
+				Status: ready,
+			}
+			objects = []client.Object{
+				rule,
+				sre,
+				&notificationv1alpha1.SlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType:       "Token",
+						Channel:        "#team-a",
+						TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					},
+					Status: ready,
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+					Data:       map[string][]byte{"token": []byte("xoxb-team-a")},
+				},
+			}
+		})
+
+		build := func() {
+			c = fake.NewClientBuilder().WithScheme(newTestScheme()).
+				WithObjects(objects...).
+				WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+				WithIndex(&notificationv1alpha1.SlackNotificationRule{}, slackConfigRefIndex, slackConfigRefKey).
+				Build()
+		}
+
+		reconcileRule := func() (*notificationv1alpha1.SlackNotificationRule, *metav1.Condition) {
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+			Expect(err).NotTo(HaveOccurred())
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			return &got, meta.FindStatusCondition(got.Status.Conditions, notificationv1alpha1.ConditionReady)
+		}
+
+		notify := func() {
+			notifier := &Notifier{Client: c, SlackClient: slackFk, ClusterResourceNamespace: controllerNamespace}
+			cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "team-a"}}
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "team-a"}}
+			_, err := notifier.Notify(ctx, job, cronJob, "Failed")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("sends to every destination and tracks each of them", func() {
+			objects = append(objects, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sre", Namespace: controllerNamespace},
+				Data:       map[string][]byte{"token": []byte("xoxb-sre")},
+			})
+			build()
+			_, cond := reconcileRule()
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+			notify()
+			Expect(slackFk.sent).To(ConsistOf(
+				sentMessage{Token: "xoxb-team-a", Channel: "#team-a-alerts", Title: "backup-1 failed"},
+				sentMessage{Token: "xoxb-sre", Channel: "#sre", Title: "backup-1 failed"},
+			))
+
+			got, _ := reconcileRule()
+			Expect(got.Status.SentCount).To(Equal(int64(2)))
+			Expect(got.Status.Destinations).To(HaveLen(2))
+			Expect(got.Status.Destinations[0].SlackConfigRef).To(Equal(teamRef))
+			Expect(got.Status.Destinations[0].Channel).To(Equal("#team-a-alerts"))
+			Expect(got.Status.Destinations[0].SentCount).To(Equal(int64(1)))
+			Expect(got.Status.Destinations[1].SlackConfigRef).To(Equal(sreRef))
+			Expect(got.Status.Destinations[1].SentCount).To(Equal(int64(1)))
+		})
+
+		It("delivers to the other destinations when one fails", func() {
+			build()
+			notify()
+			Expect(slackFk.sent).To(ConsistOf(sentMessage{Token: "xoxb-team-a", Channel: "#team-a-alerts", Title: "backup-1 failed"}))
+
+			var got notificationv1alpha1.SlackNotificationRule
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), &got)).To(Succeed())
+			Expect(got.Status.SentCount).To(Equal(int64(1)))
+			Expect(got.Status.FailedCount).To(Equal(int64(1)))
+			Expect(got.Status.Destinations).To(HaveLen(2))
+			Expect(got.Status.Destinations[1].FailedCount).To(Equal(int64(1)))
+			Expect(got.Status.Destinations[1].LastError).To(ContainSubstring("secret sre"))
+		})
+
+		It("forgets the deliveries of removed destinations", func() {
+			rule.Status.Destinations = []notificationv1alpha1.DestinationStatus{
+				{SlackConfigRef: sreRef, SentCount: 3},
+				{SlackConfigRef: teamRef, Channel: "#old", SentCount: 1},
+			}
+			build()
+			got, _ := reconcileRule()
+			Expect(got.Status.Destinations).To(Equal([]notificationv1alpha1.DestinationStatus{{SlackConfigRef: sreRef, SentCount: 3}}))
+		})
+
+		It("is not ready while the configuration of a destination is missing", func() {
+			objects = slices.DeleteFunc(objects, func(obj client.Object) bool { return obj == sre })
+			build()
+			_, cond := reconcileRule()
+			Expect(cond.Reason).To(Equal(ReasonConfigNotFound))
+			Expect(cond.Message).To(Equal("ClusterSlackConfig sre not found"))
+
+			r := &SlackNotificationRuleReconciler{Client: c, Scheme: c.Scheme()}
+			Expect(r.rulesForClusterConfig(ctx, sre)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}))
+		})
+
+		It("handles buttons with the configuration of the destination they were posted to", func() {
+			build()
+			d := deliveries(*rule, rule.Spec.Notifications[0])[1]
+			ref := newNotificationRef(&batchv1.Job{}, &batchv1.CronJob{}, d.rule, d.note)
+			Expect(ref.Config).To(Equal(&sreRef))
+
+			ref.Namespace = "team-a"
+			notifier := &Notifier{Client: c, ClusterResourceNamespace: controllerNamespace}
+			_, config, err := notifier.getNotificationRule(ctx, ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Name).To(Equal("sre"))
+			Expect(config.Namespace).To(Equal(controllerNamespace))
+
+			ref.Config = &notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "other"}
+			_, _, err = notifier.getNotificationRule(ctx, ref)
+			Expect(err).To(MatchError(ContainSubstring("does not send Failed notifications through ClusterSlackConfig other")))
 		})
 	})
 })
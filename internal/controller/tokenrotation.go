@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	goslack "github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// AnnotationTokenExpiresAt records on the Secret of a rotating token when the token expires.
+const AnnotationTokenExpiresAt = "notification.murasame29.com/token-expires-at"
+
+// LabelManagedBy marks the Secrets of rotating tokens the controller may
+// write to, with the value ManagedByController.
+const (
+	LabelManagedBy      = "app.kubernetes.io/managed-by"
+	ManagedByController = "slack-notifier-controller"
+)
+
+const (
+	// ReasonTokenRefreshFailed is reported for a SlackConfig whose rotating token Slack refused to refresh.
+	ReasonTokenRefreshFailed = "TokenRefreshFailed"
+	// ReasonTokenSecretNotManaged is reported for a SlackConfig whose rotating
+	// token would be written to a Secret the controller does not manage.
+	ReasonTokenSecretNotManaged = "TokenSecretNotManaged"
+	// ReasonTokenStoreFailed is reported for a SlackConfig whose refreshed
+	// token could not be stored.
+	ReasonTokenStoreFailed = "TokenStoreFailed"
+)
+
+// TokenRefreshMargin is how long before it expires a rotating token is
+// refreshed. The credential cache TTL must be shorter, so that cached tokens
+// never expire.
+const TokenRefreshMargin = time.Hour
+
+// rotateToken refreshes the rotating token of config with oauth.v2.access
+// when it expires within TokenRefreshMargin or has never been refreshed, and
+// stores it with the new refresh token in the Secret of TokenSecretRef. That
+// Secret is created by the controller, or has to be labeled as managed by it,
+// so that a SlackConfig cannot overwrite any other Secret of its namespace. The
+// expiry of the current token is recorded in the status. Controllers only run
+// on the leader and a SlackConfig is never reconciled concurrently, so two
+// refreshes of the same token never race.
+func (r *SlackConfigReconciler) rotateToken(ctx context.Context, config *notificationv1alpha1.SlackConfig) error {
+	rotation := config.Spec.TokenRotation
+	if rotation == nil {
+		config.Status.TokenExpirationTime = nil
+		return nil
+	}
+	ref := config.Spec.TokenSecretRef
+
+	key := types.NamespacedName{Namespace: config.Namespace, Name: ref.Name}
+	var secret corev1.Secret
+	exists, err := r.getTokenSecret(ctx, key, &secret)
+	if err != nil {
+		return err
+	}
+	if exists && !managedSecret(&secret) {
+		return invalidConfig(ReasonTokenSecretNotManaged, "secret %s is not labeled %s=%s; the controller only stores rotating tokens in Secrets it manages", ref.Name, LabelManagedBy, ManagedByController)
+	}
+	if expiry := tokenExpiry(&secret); expiry != nil && len(secret.Data[ref.Key]) > 0 && r.now().Add(TokenRefreshMargin).Before(expiry.Time) {
+		config.Status.TokenExpirationTime = expiry
+		return nil
+	}
+
+	refreshToken := string(secret.Data[notificationv1alpha1.RotatedRefreshTokenKey])
+	if refreshToken == "" {
+		refreshToken, err = r.readSecret(ctx, config, "tokenRotation.refreshTokenSecretRef", &rotation.RefreshTokenSecretRef)
+		if err != nil {
+			return err
+		}
+	}
+	clientSecret, err := r.readSecret(ctx, config, "tokenRotation.clientSecretRef", &rotation.ClientSecretRef)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.SlackClient.RefreshToken(ctx, rotation.ClientID, strings.TrimSpace(clientSecret), strings.TrimSpace(refreshToken))
+	var slackErr goslack.SlackErrorResponse
+	if errors.As(err, &slackErr) {
+		return invalidConfig(ReasonTokenRefreshFailed, "Slack refused to refresh the token: %s", slackErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	expiry := metav1.NewTime(r.now().Add(time.Duration(resp.ExpiresIn) * time.Second).Truncate(time.Second))
+
+	// Slack revoked the refresh token just used, so writing the new tokens is
+	// retried on the current Secret as long as the failure may be transient.
+	attempt := 0
+	err = retry.OnError(retry.DefaultBackoff, isTransientWriteError, func() error {
+		if attempt++; attempt > 1 {
+			secret = corev1.Secret{}
+			if exists, err = r.getTokenSecret(ctx, key, &secret); err != nil {
+				return err
+			}
+			if exists && !managedSecret(&secret) {
+				return invalidConfig(ReasonTokenSecretNotManaged, "secret %s is no longer labeled %s=%s", ref.Name, LabelManagedBy, ManagedByController)
+			}
+		}
+		secret.Name, secret.Namespace = key.Name, key.Namespace
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, LabelManagedBy, ManagedByController)
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[ref.Key] = []byte(resp.AccessToken)
+		secret.Data[notificationv1alpha1.RotatedRefreshTokenKey] = []byte(resp.RefreshToken)
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, AnnotationTokenExpiresAt, expiry.UTC().Format(time.RFC3339))
+		if exists {
+			return r.Update(ctx, &secret)
+		}
+		return r.Create(ctx, &secret)
+	})
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to store refreshed Slack token", "secret", ref.Name)
+		return invalidConfig(ReasonTokenStoreFailed, "failed to store the refreshed token in secret %s, which lost the refresh token Slack issued with it: %v", ref.Name, err)
+	}
+	logf.FromContext(ctx).Info("Refreshed rotating Slack token", "secret", ref.Name, "expires", expiry.Time)
+	config.Status.TokenExpirationTime = &expiry
+	return nil
+}
+
+// getTokenSecret reads the Secret of a rotating token from the API server and
+// reports whether it exists.
+func (r *SlackConfigReconciler) getTokenSecret(ctx context.Context, key types.NamespacedName, secret *corev1.Secret) (bool, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	err := reader.Get(ctx, key, secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get secret %s: %w", key.Name, err)
+	}
+	return true, nil
+}
+
+// managedSecret reports whether the controller may write rotating tokens to secret.
+func managedSecret(secret *corev1.Secret) bool {
+	return secret.Labels[LabelManagedBy] == ManagedByController
+}
+
+// isTransientWriteError reports whether writing a Secret may succeed when
+// retried: it was changed or created by someone else since it was read, or
+// the API server could not handle the request.
+func isTransientWriteError(err error) bool {
+	var netErr net.Error
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) ||
+		apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsUnexpectedServerError(err) ||
+		errors.As(err, &netErr)
+}
+
+// tokenExpiry returns the expiry recorded on the Secret of a rotating token, or nil if there is none.
+func tokenExpiry(secret *corev1.Secret) *metav1.Time {
+	expiry, err := time.Parse(time.RFC3339, secret.Annotations[AnnotationTokenExpiresAt])
+	if err != nil {
+		return nil
+	}
+	t := metav1.NewTime(expiry)
+	return &t
+}
+
+// tokenRefreshDue returns how long until the rotating token recorded in status
+// has to be refreshed, or zero if the SlackConfig has no rotating token.
+func (r *SlackConfigReconciler) tokenRefreshDue(status *notificationv1alpha1.SlackConfigStatus) time.Duration {
+	if status.TokenExpirationTime == nil {
+		return 0
+	}
+	return max(status.TokenExpirationTime.Sub(r.now())-TokenRefreshMargin, time.Second)
+}
+
+func (r *SlackConfigReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
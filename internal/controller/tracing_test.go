@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
+)
+
+var _ = Describe("Tracing", func() {
+	const namespace = "team-a"
+
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(func() { otel.SetTracerProvider(previous) })
+	})
+
+	It("traces a notification from the rules to the Slack call", func() {
+		c := fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(
+				&notificationv1alpha1.SlackConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Spec: notificationv1alpha1.SlackConfigSpec{
+						AuthType:       "Token",
+						TokenSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "token"},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+					Data:       map[string][]byte{"token": []byte("xoxb-test")},
+				},
+				&notificationv1alpha1.SlackNotificationRule{
+					ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: namespace},
+					Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+						TargetResource: "CronJob",
+						SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+						Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Channel: "#team-a"}},
+					},
+				},
+			).
+			WithStatusSubresource(&notificationv1alpha1.SlackNotificationRule{}).
+			Build()
+		notifier := &Notifier{Client: c, SlackClient: &fakeSlackClient{}}
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: namespace}}
+		cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace}}
+		_, err := notifier.Notify(context.Background(), job, cronJob, "Failed")
+		Expect(err).NotTo(HaveOccurred())
+
+		spans := map[string]sdktrace.ReadOnlySpan{}
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		Expect(spans).To(HaveKey("Notify"))
+		Expect(spans["Notify"].Attributes()).To(ContainElements(
+			tracing.TriggerKey.String("team-a/backup-1"),
+			tracing.TargetKey.String("team-a/backup"),
+			tracing.TargetKindKey.String("CronJob"),
+			tracing.StatusKey.String("Failed"),
+		))
+		Expect(spans).To(HaveKey("MatchRules"))
+		Expect(spans["MatchRules"].Attributes()).To(ContainElement(tracing.MatchedRulesKey.Int(1)))
+		Expect(spans).To(HaveKey("NotifyRule"))
+		Expect(spans["NotifyRule"].Attributes()).To(ContainElement(tracing.RuleKey.String("team-a/backups")))
+		Expect(spans).To(HaveKey("SendSlack"))
+		Expect(spans["SendSlack"].Attributes()).To(ContainElements(
+			tracing.SlackConfigKey.String("SlackConfig/team-a/slack"),
+			tracing.ChannelKey.String("#team-a"),
+		))
+		Expect(spans["SendSlack"].Parent().SpanID()).To(Equal(spans["NotifyRule"].SpanContext().SpanID()))
+		Expect(spans).To(HaveKey("ResolveCredential"))
+		Expect(spans["ResolveCredential"].Parent().SpanID()).To(Equal(spans["SendSlack"].SpanContext().SpanID()))
+	})
+})
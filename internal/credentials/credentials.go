@@ -0,0 +1,212 @@
+// Package credentials reads the webhook URLs, tokens and signing secrets
+// referenced by SlackConfigs from Kubernetes Secrets, files mounted into the
+// controller pod or its environment.
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
+)
+
+// Names of the providers, as selected by SlackConfigSpec.CredentialProvider.
+const (
+	ProviderSecret = "Secret"
+	ProviderFile   = "File"
+	ProviderEnv    = "Env"
+)
+
+var (
+	// ErrNotFound is wrapped by the errors returned for a Secret, file or
+	// environment variable that does not exist.
+	ErrNotFound = errors.New("credential not found")
+	// ErrKeyNotFound is wrapped by the errors returned for a Secret that
+	// exists but lacks the referenced key.
+	ErrKeyNotFound = errors.New("key not found in secret")
+)
+
+// Provider reads credentials. A reference names a Secret and key for
+// ProviderSecret, a directory and file for ProviderFile and an environment
+// variable (Key) for ProviderEnv.
+type Provider interface {
+	// Get returns the credential ref refers to for a SlackConfig in namespace.
+	Get(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error)
+	// Describe names the source of a credential in messages.
+	Describe(ref *corev1.SecretKeySelector) string
+}
+
+// SecretProvider reads credentials from Secrets in the namespace of the SlackConfig.
+type SecretProvider struct {
+	Client client.Reader
+}
+
+// Get implements Provider.
+func (p *SecretProvider) Get(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	var secret corev1.Secret
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret); apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%w: secret %s: %w", ErrNotFound, ref.Name, err)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+	val, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %s not found in secret %s", ErrKeyNotFound, ref.Key, ref.Name)
+	}
+	return string(val), nil
+}
+
+// Describe implements Provider.
+func (p *SecretProvider) Describe(ref *corev1.SecretKeySelector) string {
+	return fmt.Sprintf("secret %s key %s", ref.Name, ref.Key)
+}
+
+// FileProvider reads credentials from files mounted into the controller pod,
+// e.g. by the Secrets Store CSI driver or a Vault agent. The credential
+// {name, key} is read from Dir/name/key, the layout of a Secret volume.
+type FileProvider struct {
+	Dir string
+}
+
+// Get implements Provider.
+func (p *FileProvider) Get(_ context.Context, _ string, ref *corev1.SecretKeySelector) (string, error) {
+	if !filepath.IsLocal(ref.Name) || !filepath.IsLocal(ref.Key) {
+		return "", fmt.Errorf("%w: %s is outside of %s", ErrNotFound, p.Describe(ref), p.Dir)
+	}
+	val, err := os.ReadFile(p.path(ref))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential: %w", err)
+	}
+	return string(val), nil
+}
+
+// Describe implements Provider.
+func (p *FileProvider) Describe(ref *corev1.SecretKeySelector) string {
+	return "file " + p.path(ref)
+}
+
+func (p *FileProvider) path(ref *corev1.SecretKeySelector) string {
+	return filepath.Join(p.Dir, ref.Name, ref.Key)
+}
+
+// EnvProvider reads credentials from the environment variable named by the key.
+type EnvProvider struct {
+	// LookupEnv defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+}
+
+// Get implements Provider.
+func (p *EnvProvider) Get(_ context.Context, _ string, ref *corev1.SecretKeySelector) (string, error) {
+	lookup := p.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	val, ok := lookup(ref.Key)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNotFound, ref.Key)
+	}
+	return val, nil
+}
+
+// Describe implements Provider.
+func (p *EnvProvider) Describe(ref *corev1.SecretKeySelector) string {
+	return "environment variable " + ref.Key
+}
+
+// Resolver reads credentials with the provider a SlackConfig selects and
+// caches them for TTL, so that Secrets are not read and files are not opened
+// for every notification. Values are not cached if TTL is zero.
+type Resolver struct {
+	// Providers by name. ProviderSecret is required.
+	Providers map[string]Provider
+	TTL       time.Duration
+	// Clock defaults to the real clock.
+	Clock clock.PassiveClock
+
+	mu    sync.Mutex
+	cache map[cacheKey]cachedValue
+}
+
+type cacheKey struct {
+	provider, namespace, name, key string
+}
+
+type cachedValue struct {
+	value   string
+	expires time.Time
+}
+
+// Get returns the credential ref refers to for a SlackConfig in namespace,
+// from the cache unless it has expired.
+func (r *Resolver) Get(ctx context.Context, provider, namespace string, ref *corev1.SecretKeySelector) (value string, err error) {
+	ctx, span := tracing.Start(ctx, "ResolveCredential",
+		tracing.CredentialProviderKey.String(provider),
+		tracing.CredentialKey.String(namespace+"/"+ref.Name+"/"+ref.Key),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	key := cacheKey{provider: provider, namespace: namespace, name: ref.Name, key: ref.Key}
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && r.now().Before(cached.expires) {
+		span.SetAttributes(tracing.CredentialCachedKey.Bool(true))
+		return cached.value, nil
+	}
+	return r.Refresh(ctx, provider, namespace, ref)
+}
+
+// Refresh reads the credential ref refers to, bypassing the cache, and caches
+// it. It is used when a credential is verified so that rotated credentials
+// are picked up before the cached value expires.
+func (r *Resolver) Refresh(ctx context.Context, provider, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	p, ok := r.Providers[provider]
+	if !ok {
+		return "", fmt.Errorf("credential provider %s is not enabled", provider)
+	}
+	key := cacheKey{provider: provider, namespace: namespace, name: ref.Name, key: ref.Key}
+	val, err := p.Get(ctx, namespace, ref)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		delete(r.cache, key)
+		return "", err
+	}
+	if r.TTL > 0 {
+		if r.cache == nil {
+			r.cache = map[cacheKey]cachedValue{}
+		}
+		r.cache[key] = cachedValue{value: val, expires: r.now().Add(r.TTL)}
+	}
+	return val, nil
+}
+
+// Describe names the source of a credential in messages.
+func (r *Resolver) Describe(provider string, ref *corev1.SecretKeySelector) string {
+	if p, ok := r.Providers[provider]; ok {
+		return p.Describe(ref)
+	}
+	return fmt.Sprintf("%s %s key %s", provider, ref.Name, ref.Key)
+}
+
+func (r *Resolver) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
@@ -0,0 +1,170 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func ref(name, key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+}
+
+var _ = Describe("Providers", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Context("SecretProvider", func() {
+		var p *SecretProvider
+
+		BeforeEach(func() {
+			p = &SecretProvider{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+				Data:       map[string][]byte{"token": []byte("xoxb-team-a")},
+			}).Build()}
+		})
+
+		It("reads the key of a Secret in the namespace", func() {
+			Expect(p.Get(ctx, "team-a", ref("slack", "token"))).To(Equal("xoxb-team-a"))
+		})
+
+		It("reports missing Secrets and keys", func() {
+			_, err := p.Get(ctx, "team-b", ref("slack", "token"))
+			Expect(err).To(MatchError(ErrNotFound))
+			_, err = p.Get(ctx, "team-a", ref("slack", "url"))
+			Expect(err).To(MatchError(ErrKeyNotFound))
+		})
+	})
+
+	Context("FileProvider", func() {
+		var p *FileProvider
+
+		BeforeEach(func() {
+			p = &FileProvider{Dir: GinkgoT().TempDir()}
+			Expect(os.Mkdir(filepath.Join(p.Dir, "slack"), 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(p.Dir, "slack", "token"), []byte("xoxb-file"), 0o600)).To(Succeed())
+		})
+
+		It("reads name/key from the directory", func() {
+			Expect(p.Get(ctx, "", ref("slack", "token"))).To(Equal("xoxb-file"))
+			Expect(p.Describe(ref("slack", "token"))).To(Equal("file " + filepath.Join(p.Dir, "slack", "token")))
+		})
+
+		It("reports missing files", func() {
+			_, err := p.Get(ctx, "", ref("slack", "url"))
+			Expect(err).To(MatchError(ErrNotFound))
+		})
+
+		It("does not read outside of the directory", func() {
+			_, err := p.Get(ctx, "", ref("..", "passwd"))
+			Expect(err).To(MatchError(ErrNotFound))
+			_, err = p.Get(ctx, "", ref("slack", "../../token"))
+			Expect(err).To(MatchError(ErrNotFound))
+		})
+	})
+
+	Context("EnvProvider", func() {
+		It("reads the environment variable named by the key", func() {
+			p := &EnvProvider{LookupEnv: func(key string) (string, bool) {
+				if key == "SLACK_TOKEN" {
+					return "xoxb-env", true
+				}
+				return "", false
+			}}
+			Expect(p.Get(ctx, "", ref("", "SLACK_TOKEN"))).To(Equal("xoxb-env"))
+			_, err := p.Get(ctx, "", ref("", "SLACK_WEBHOOK_URL"))
+			Expect(err).To(MatchError(ErrNotFound))
+		})
+	})
+})
+
+// countingProvider returns value and counts how often it is read.
+type countingProvider struct {
+	value string
+	err   error
+	reads int
+}
+
+func (p *countingProvider) Get(context.Context, string, *corev1.SecretKeySelector) (string, error) {
+	p.reads++
+	return p.value, p.err
+}
+
+func (p *countingProvider) Describe(ref *corev1.SecretKeySelector) string {
+	return "counting " + ref.Key
+}
+
+var _ = Describe("Resolver", func() {
+	var (
+		ctx      context.Context
+		provider *countingProvider
+		clock    *clocktesting.FakePassiveClock
+		resolver *Resolver
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		provider = &countingProvider{value: "xoxb-1"}
+		clock = clocktesting.NewFakePassiveClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		resolver = &Resolver{Providers: map[string]Provider{ProviderSecret: provider}, TTL: time.Minute, Clock: clock}
+	})
+
+	It("caches values until they expire", func() {
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		provider.value = "xoxb-2"
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		Expect(provider.reads).To(Equal(1))
+
+		clock.SetTime(clock.Now().Add(time.Minute))
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-2"))
+		Expect(provider.reads).To(Equal(2))
+	})
+
+	It("caches values per namespace and reference", func() {
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		Expect(resolver.Get(ctx, ProviderSecret, "team-b", ref("slack", "token"))).To(Equal("xoxb-1"))
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "app-token"))).To(Equal("xoxb-1"))
+		Expect(provider.reads).To(Equal(3))
+	})
+
+	It("picks up rotated values on refresh", func() {
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		provider.value = "xoxb-2"
+		Expect(resolver.Refresh(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-2"))
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-2"))
+		Expect(provider.reads).To(Equal(2))
+	})
+
+	It("drops values that can no longer be read", func() {
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		provider.err = ErrNotFound
+		_, err := resolver.Refresh(ctx, ProviderSecret, "team-a", ref("slack", "token"))
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("does not cache without a TTL", func() {
+		resolver.TTL = 0
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		Expect(resolver.Get(ctx, ProviderSecret, "team-a", ref("slack", "token"))).To(Equal("xoxb-1"))
+		Expect(provider.reads).To(Equal(2))
+	})
+
+	It("rejects providers that are not enabled", func() {
+		_, err := resolver.Get(ctx, ProviderFile, "team-a", ref("slack", "token"))
+		Expect(err).To(MatchError(ContainSubstring("credential provider File is not enabled")))
+	})
+})
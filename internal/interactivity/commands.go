@@ -0,0 +1,111 @@
+package interactivity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/slack-go/slack"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// errUnauthorized is returned when a command does not come from a Slack App
+// configured for any namespace the channel is allowed to query, including
+// when the channel is not allowed to query any.
+var errUnauthorized = errors.New("command does not come from a Slack App configured for the channel")
+
+// CommandScope permits slash commands from the Slack App identified by
+// AppCredentials to query Namespace.
+type CommandScope struct {
+	Namespace string
+	AppCredentials
+}
+
+// CommandHandler answers slash commands.
+type CommandHandler interface {
+	// CommandScopes returns the namespaces the channel cmd was sent from may
+	// query, along with the Slack App the command must come from.
+	CommandScopes(ctx context.Context, cmd *slack.SlashCommand) ([]CommandScope, error)
+	// HandleCommand answers cmd, querying only the given namespaces.
+	HandleCommand(ctx context.Context, cmd *slack.SlashCommand, namespaces []string) (*slack.Msg, error)
+}
+
+type commandsHandler struct {
+	commands CommandHandler
+}
+
+func (h *commandsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx)
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	cmd, err := slack.SlashCommandParse(r)
+	if err != nil {
+		http.Error(w, "invalid slash command", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := answerCommand(ctx, h.commands, &cmd, func(creds AppCredentials) bool {
+		return creds.SigningSecret != "" && verify(r.Header, body, creds.SigningSecret) == nil
+	})
+	if errors.Is(err, errUnauthorized) {
+		logger.Info("Rejected slash command with invalid signature", "channel", cmd.ChannelID, "user", cmd.UserID)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		logger.Error(err, "Failed to answer slash command", "channel", cmd.ChannelID)
+		http.Error(w, "failed to answer command", http.StatusInternalServerError)
+		return
+	}
+	writeMessage(w, msg)
+}
+
+// answerCommand answers cmd, querying only the namespaces whose Slack App
+// credentials are accepted by authorized, so one Slack App cannot read
+// namespaces configured for another.
+func answerCommand(ctx context.Context, commands CommandHandler, cmd *slack.SlashCommand, authorized func(AppCredentials) bool) (*slack.Msg, error) {
+	logger := log.FromContext(ctx)
+
+	scopes, err := commands.CommandScopes(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve slash command scopes: %w", err)
+	}
+	var namespaces []string
+	for _, scope := range scopes {
+		if authorized(scope.AppCredentials) && !slices.Contains(namespaces, scope.Namespace) {
+			namespaces = append(namespaces, scope.Namespace)
+		}
+	}
+	// Commands from channels without scopes are rejected like forged ones, so
+	// unverified requests cannot tell which channels are allowed.
+	if len(namespaces) == 0 {
+		return nil, errUnauthorized
+	}
+
+	msg, err := commands.HandleCommand(ctx, cmd, namespaces)
+	if err != nil {
+		logger.Error(err, "Failed to handle slash command", "text", cmd.Text, "user", cmd.UserID)
+		msg = ephemeral(":x: " + err.Error())
+	}
+	return msg, nil
+}
+
+func ephemeral(text string) *slack.Msg {
+	return &slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text}
+}
+
+func writeMessage(w http.ResponseWriter, msg *slack.Msg) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(msg)
+}
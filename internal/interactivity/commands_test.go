@@ -0,0 +1,106 @@
+package interactivity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+)
+
+type fakeCommands struct {
+	mu         sync.Mutex
+	namespaces [][]string
+}
+
+func (f *fakeCommands) CommandScopes(_ context.Context, cmd *slack.SlashCommand) ([]CommandScope, error) {
+	if cmd.ChannelID != "C123" {
+		return nil, nil
+	}
+	return []CommandScope{
+		{Namespace: "team-a", AppCredentials: AppCredentials{SigningSecret: signingSecret}},
+		{Namespace: "team-b", AppCredentials: AppCredentials{SigningSecret: "another-app-secret", AppToken: "xapp-another"}},
+		{Namespace: "team-c", AppCredentials: AppCredentials{SigningSecret: signingSecret, AppToken: appToken}},
+	}, nil
+}
+
+func (f *fakeCommands) HandleCommand(_ context.Context, cmd *slack.SlashCommand, namespaces []string) (*slack.Msg, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.namespaces = append(f.namespaces, namespaces)
+	return &slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: "ok: " + cmd.Text}, nil
+}
+
+func (f *fakeCommands) queried() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.namespaces...)
+}
+
+var _ = Describe("Slash command handler", func() {
+	var (
+		commands *fakeCommands
+		server   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		commands = &fakeCommands{}
+		server = httptest.NewServer(NewHandler(&fakeActions{}, commands))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	send := func(channelID, secret string) (*http.Response, *slack.Msg) {
+		form := url.Values{
+			"command":    {"/k8s-cron"},
+			"text":       {"list"},
+			"channel_id": {channelID},
+			"user_id":    {"U123"},
+		}
+		resp, err := http.DefaultClient.Do(signedRequest(server.URL+CommandsPath, form, secret, time.Now()))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			return resp, nil
+		}
+		var msg slack.Msg
+		Expect(json.NewDecoder(resp.Body).Decode(&msg)).To(Succeed())
+		return resp, &msg
+	}
+
+	It("answers with only the namespaces of the app that signed the command", func() {
+		resp, msg := send("C123", signingSecret)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(msg.ResponseType).To(Equal(slack.ResponseTypeEphemeral))
+		Expect(msg.Text).To(Equal("ok: list"))
+		Expect(commands.namespaces).To(Equal([][]string{{"team-a", "team-c"}}))
+	})
+
+	It("rejects commands with an invalid signature", func() {
+		resp, _ := send("C123", "wrong-secret")
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(commands.namespaces).To(BeEmpty())
+	})
+
+	It("rejects commands from channels that are not allowed to query any namespace", func() {
+		resp, _ := send("C999", signingSecret)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(commands.namespaces).To(BeEmpty())
+	})
+
+	It("rejects malformed commands", func() {
+		resp, err := http.Post(server.URL+CommandsPath, "application/x-www-form-urlencoded", strings.NewReader("%zz"))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,139 @@
+package interactivity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/slack-go/slack"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ActionsPath is the path Slack sends interactivity payloads to.
+	ActionsPath = "/slack/actions"
+	// CommandsPath is the path Slack sends slash commands to.
+	CommandsPath = "/slack/commands"
+
+	// maxBodyBytes bounds the size of a request body read from Slack.
+	maxBodyBytes = 1 << 20
+)
+
+// AppCredentials identify the Slack App a request must come from.
+type AppCredentials struct {
+	// SigningSecret verifies requests sent over HTTP.
+	SigningSecret string
+	// AppToken is the app-level token of the Socket Mode connection requests are received on.
+	AppToken string
+}
+
+// ActionHandler handles block actions (button clicks) on notifications.
+type ActionHandler interface {
+	// Credentials returns the credentials of the Slack App the request carrying action must come from.
+	Credentials(ctx context.Context, action *slack.BlockAction) (AppCredentials, error)
+	// HandleAction performs action on behalf of callback.User.
+	HandleAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error
+}
+
+// NewHandler returns an http.Handler serving Slack interactivity requests and slash commands.
+func NewHandler(actions ActionHandler, commands CommandHandler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST "+ActionsPath, &actionsHandler{actions: actions})
+	mux.Handle("POST "+CommandsPath, &commandsHandler{commands: commands})
+	return mux
+}
+
+type actionsHandler struct {
+	actions ActionHandler
+}
+
+func (h *actionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx)
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	callback, err := parseCallback(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if callback.Type != slack.InteractionTypeBlockActions {
+		// Other interaction types are not used by notifications.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = handleActions(ctx, h.actions, callback, func(creds AppCredentials) error {
+		if creds.SigningSecret == "" {
+			return fmt.Errorf("no signing secret is configured")
+		}
+		return verify(r.Header, body, creds.SigningSecret)
+	})
+	if err != nil {
+		logger.Info("Rejected interactivity request", "error", err.Error())
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleActions handles the block actions of callback once authorize accepts
+// the credentials of every action. Every action is checked against the config
+// it refers to before anything is done on its behalf.
+func handleActions(ctx context.Context, actions ActionHandler, callback *slack.InteractionCallback, authorize func(AppCredentials) error) error {
+	logger := log.FromContext(ctx)
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		creds, err := actions.Credentials(ctx, action)
+		if err != nil {
+			return fmt.Errorf("failed to resolve credentials of action %s: %w", action.ActionID, err)
+		}
+		if err := authorize(creds); err != nil {
+			return fmt.Errorf("action %s: %w", action.ActionID, err)
+		}
+	}
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		if err := actions.HandleAction(ctx, callback, action); err != nil {
+			logger.Error(err, "Failed to handle action", "action", action.ActionID, "user", callback.User.ID)
+		}
+	}
+	return nil
+}
+
+// parseCallback extracts the interaction payload from a form-encoded request body.
+func parseCallback(body []byte) (*slack.InteractionCallback, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid form body: %w", err)
+	}
+	payload := form.Get("payload")
+	if payload == "" {
+		return nil, fmt.Errorf("missing payload")
+	}
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(payload), &callback); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return &callback, nil
+}
+
+// verify checks the Slack request signature of body.
+func verify(header http.Header, body []byte, secret string) error {
+	sv, err := slack.NewSecretsVerifier(header, secret)
+	if err != nil {
+		return err
+	}
+	if _, err := sv.Write(body); err != nil {
+		return err
+	}
+	return sv.Ensure()
+}
@@ -0,0 +1,141 @@
+package interactivity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+)
+
+const (
+	signingSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+	appToken      = "xapp-1-A123-test"
+)
+
+type fakeActions struct {
+	mu      sync.Mutex
+	handled []string
+	users   []string
+}
+
+func (f *fakeActions) Credentials(_ context.Context, action *slack.BlockAction) (AppCredentials, error) {
+	if action.Value == "unknown" {
+		return AppCredentials{}, fmt.Errorf("unknown notification")
+	}
+	return AppCredentials{SigningSecret: signingSecret, AppToken: appToken}, nil
+}
+
+func (f *fakeActions) HandleAction(_ context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handled = append(f.handled, action.ActionID+":"+action.Value)
+	f.users = append(f.users, callback.User.ID)
+	return nil
+}
+
+func (f *fakeActions) handledActions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.handled...)
+}
+
+// slackRequest builds a request the way Slack posts an interactivity payload.
+func slackRequest(serverURL, payload, secret string, ts time.Time) *http.Request {
+	return signedRequest(serverURL+ActionsPath, url.Values{"payload": {payload}}, secret, ts)
+}
+
+// signedRequest builds a form request signed the way Slack signs requests.
+func signedRequest(target string, form url.Values, secret string, ts time.Time) *http.Request {
+	body := form.Encode()
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req, err := http.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func blockActionsPayload(actionID, value string) string {
+	return fmt.Sprintf(`{
+		"type": "block_actions",
+		"user": {"id": "U123"},
+		"container": {"type": "message", "channel_id": "C123", "message_ts": "1700000000.000100"},
+		"actions": [{"action_id": %q, "block_id": "notification-actions", "type": "button", "value": %q}]
+	}`, actionID, value)
+}
+
+var _ = Describe("Interactivity handler", func() {
+	var (
+		actions *fakeActions
+		server  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		actions = &fakeActions{}
+		server = httptest.NewServer(NewHandler(actions, &fakeCommands{}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("handles actions signed with the signing secret", func() {
+		req := slackRequest(server.URL, blockActionsPayload("acknowledge", `{"rule":"r"}`), signingSecret, time.Now())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(actions.handled).To(ConsistOf(`acknowledge:{"rule":"r"}`))
+		Expect(actions.users).To(ConsistOf("U123"))
+	})
+
+	It("rejects requests with an invalid signature", func() {
+		req := slackRequest(server.URL, blockActionsPayload("acknowledge", "v"), "another-secret", time.Now())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(actions.handled).To(BeEmpty())
+	})
+
+	It("rejects stale requests", func() {
+		req := slackRequest(server.URL, blockActionsPayload("acknowledge", "v"), signingSecret, time.Now().Add(-time.Hour))
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(actions.handled).To(BeEmpty())
+	})
+
+	It("rejects actions that refer to unknown notifications", func() {
+		req := slackRequest(server.URL, blockActionsPayload("acknowledge", "unknown"), signingSecret, time.Now())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(actions.handled).To(BeEmpty())
+	})
+
+	It("rejects requests without a payload", func() {
+		resp, err := http.Post(server.URL+ActionsPath, "application/x-www-form-urlencoded", strings.NewReader("foo=bar"))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
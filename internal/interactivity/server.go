@@ -0,0 +1,60 @@
+package interactivity
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Server serves Slack interactivity requests. It is added to the manager as a
+// Runnable and runs on every replica, since handling a request only needs the
+// API server.
+type Server struct {
+	// BindAddress is the address the server listens on (e.g., ":8082").
+	BindAddress string
+	// Handler serves the requests, usually the result of NewHandler.
+	Handler http.Handler
+}
+
+// Start runs the server until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("interactivity")
+
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return log.IntoContext(context.Background(), logger)
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("Starting Slack interactivity server", "address", s.BindAddress)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
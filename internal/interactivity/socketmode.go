@@ -0,0 +1,214 @@
+package interactivity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultSyncPeriod = 30 * time.Second
+	maxReconnectDelay = 5 * time.Minute
+)
+
+// AppTokenSource lists the app-level tokens of the Slack Apps that receive
+// interactivity requests over Socket Mode.
+type AppTokenSource interface {
+	AppTokens(ctx context.Context) ([]string, error)
+}
+
+// SocketMode keeps a Socket Mode connection open for every Slack App listed by
+// Tokens and dispatches button clicks and slash commands received on it to the
+// same handlers as the HTTP endpoint. It is added to the manager as a Runnable
+// and only runs on the leader, as Slack delivers each request to one of the
+// open connections of an App.
+type SocketMode struct {
+	Tokens   AppTokenSource
+	Actions  ActionHandler
+	Commands CommandHandler
+	// SyncPeriod is how often Tokens is listed to open and close connections.
+	// Defaults to 30 seconds.
+	SyncPeriod time.Duration
+	// APIURL overrides the Slack Web API URL the connections are opened with.
+	APIURL string
+}
+
+// Start keeps the connections open until ctx is cancelled.
+func (s *SocketMode) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("socketmode")
+	ctx = log.IntoContext(ctx, logger)
+
+	period := s.SyncPeriod
+	if period == 0 {
+		period = defaultSyncPeriod
+	}
+
+	var wg sync.WaitGroup
+	connections := map[string]context.CancelFunc{}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		tokens, err := s.Tokens.AppTokens(ctx)
+		if err != nil {
+			logger.Error(err, "Failed to list app-level tokens")
+			return
+		}
+
+		wanted := map[string]bool{}
+		for _, token := range tokens {
+			wanted[token] = true
+			if _, ok := connections[token]; ok {
+				continue
+			}
+			connCtx, cancel := context.WithCancel(ctx)
+			connections[token] = cancel
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.connect(connCtx, token)
+			}()
+		}
+		for token, cancel := range connections {
+			if !wanted[token] {
+				cancel()
+				delete(connections, token)
+			}
+		}
+	}, period)
+
+	wg.Wait()
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *SocketMode) NeedLeaderElection() bool {
+	return true
+}
+
+// connect keeps a Socket Mode connection for appToken open until ctx is
+// cancelled, reconnecting with a backoff when it fails.
+func (s *SocketMode) connect(ctx context.Context, appToken string) {
+	logger := log.FromContext(ctx).WithValues("app", fingerprint(appToken))
+	ctx = log.IntoContext(ctx, logger)
+
+	options := []slack.Option{slack.OptionAppLevelToken(appToken)}
+	if s.APIURL != "" {
+		options = append(options, slack.OptionAPIURL(s.APIURL))
+	}
+	api := slack.New("", options...)
+
+	backoff := reconnectBackoff()
+	for {
+		client := socketmode.New(api)
+		runCtx, cancel := context.WithCancel(ctx)
+		go s.dispatch(runCtx, client, appToken)
+
+		logger.Info("Opening Socket Mode connection")
+		started := time.Now()
+		err := client.RunContext(runCtx)
+		cancel()
+		if ctx.Err() != nil {
+			logger.Info("Closed Socket Mode connection")
+			return
+		}
+		if time.Since(started) > maxReconnectDelay {
+			// The connection was healthy for a while, so start backing off from scratch.
+			backoff = reconnectBackoff()
+		}
+
+		delay := backoff.Step()
+		logger.Error(err, "Socket Mode connection failed", "retryAfter", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func reconnectBackoff() wait.Backoff {
+	return wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 32, Cap: maxReconnectDelay}
+}
+
+// dispatch handles the events received on a connection until ctx is
+// cancelled. Requests are handled concurrently, like HTTP requests are.
+func (s *SocketMode) dispatch(ctx context.Context, client *socketmode.Client, appToken string) {
+	logger := log.FromContext(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-client.Events:
+			switch evt.Type {
+			case socketmode.EventTypeConnected:
+				logger.Info("Socket Mode connection established")
+			case socketmode.EventTypeInvalidAuth:
+				logger.Info("Socket Mode connection was refused; check the app-level token")
+			case socketmode.EventTypeErrorBadMessage:
+				if bad, ok := evt.Data.(*socketmode.ErrorBadMessage); ok {
+					logger.Error(bad.Cause, "Ignoring malformed Socket Mode request")
+				}
+			case socketmode.EventTypeInteractive:
+				go s.handleInteraction(ctx, client, evt, appToken, logger)
+			case socketmode.EventTypeSlashCommand:
+				go s.handleCommand(ctx, client, evt, appToken, logger)
+			}
+		}
+	}
+}
+
+func (s *SocketMode) handleInteraction(ctx context.Context, client *socketmode.Client, evt socketmode.Event, appToken string, logger logr.Logger) {
+	callback, ok := evt.Data.(slack.InteractionCallback)
+	if !ok || evt.Request == nil {
+		return
+	}
+	// Slack expects an acknowledgement within 3 seconds, before the actions are done.
+	if err := client.AckCtx(ctx, evt.Request.EnvelopeID, nil); err != nil {
+		logger.Error(err, "Failed to acknowledge interaction")
+	}
+	if callback.Type != slack.InteractionTypeBlockActions {
+		return
+	}
+
+	err := handleActions(ctx, s.Actions, &callback, func(creds AppCredentials) error {
+		if creds.AppToken != appToken {
+			return fmt.Errorf("the notification was not posted by a SlackConfig using this Socket Mode connection")
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Info("Rejected interactivity request", "error", err.Error(), "user", callback.User.ID)
+	}
+}
+
+func (s *SocketMode) handleCommand(ctx context.Context, client *socketmode.Client, evt socketmode.Event, appToken string, logger logr.Logger) {
+	cmd, ok := evt.Data.(slack.SlashCommand)
+	if !ok || evt.Request == nil {
+		return
+	}
+
+	msg, err := answerCommand(ctx, s.Commands, &cmd, func(creds AppCredentials) bool {
+		return creds.AppToken == appToken
+	})
+	if err != nil {
+		logger.Info("Rejected slash command", "error", err.Error(), "channel", cmd.ChannelID, "user", cmd.UserID)
+		msg = ephemeral("This channel is not allowed to use " + cmd.Command + ".")
+	}
+	if err := client.AckCtx(ctx, evt.Request.EnvelopeID, msg); err != nil {
+		logger.Error(err, "Failed to answer slash command")
+	}
+}
+
+// fingerprint identifies an app-level token in logs without revealing it.
+func fingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,130 @@
+package interactivity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+)
+
+type fakeTokens []string
+
+func (f fakeTokens) AppTokens(context.Context) ([]string, error) {
+	return f, nil
+}
+
+// socketModeStandIn stands in for the Slack Web API and the Socket Mode
+// websocket endpoint. Connections opened by the client are passed to conns.
+type socketModeStandIn struct {
+	*httptest.Server
+	conns chan *websocket.Conn
+}
+
+func newSocketModeStandIn() *socketModeStandIn {
+	s := &socketModeStandIn{conns: make(chan *websocket.Conn, 1)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+appToken {
+			_, _ = fmt.Fprint(w, `{"ok": false, "error": "invalid_auth"}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"ok": true, "url": "ws://%s/link"}`, r.Host)
+	})
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		// The client sends Origin: https://api.slack.com, like Slack expects.
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.WriteJSON(map[string]any{"type": "hello", "num_connections": 1})
+		s.conns <- conn
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// request sends a Socket Mode envelope and returns the acknowledgement.
+func request(conn *websocket.Conn, envelopeID, requestType, payload string) map[string]any {
+	Expect(conn.WriteJSON(map[string]any{
+		"type":                     requestType,
+		"envelope_id":              envelopeID,
+		"payload":                  json.RawMessage(payload),
+		"accepts_response_payload": true,
+	})).To(Succeed())
+
+	Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+	var ack map[string]any
+	Expect(conn.ReadJSON(&ack)).To(Succeed())
+	Expect(ack).To(HaveKeyWithValue("envelope_id", envelopeID))
+	return ack
+}
+
+var _ = Describe("Socket Mode", func() {
+	var (
+		actions  *fakeActions
+		commands *fakeCommands
+		standIn  *socketModeStandIn
+		conn     *websocket.Conn
+		cancel   context.CancelFunc
+		done     chan struct{}
+	)
+
+	BeforeEach(func() {
+		actions = &fakeActions{}
+		commands = &fakeCommands{}
+		standIn = newSocketModeStandIn()
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan struct{})
+		socketMode := &SocketMode{
+			Tokens:   fakeTokens{appToken},
+			Actions:  actions,
+			Commands: commands,
+			APIURL:   standIn.URL + "/api/",
+		}
+		go func() {
+			defer close(done)
+			_ = socketMode.Start(ctx)
+		}()
+		Eventually(standIn.conns, 5*time.Second).Should(Receive(&conn))
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done, 5*time.Second).Should(BeClosed())
+		_ = conn.Close()
+		standIn.Close()
+	})
+
+	It("dispatches button clicks from notifications of its Slack App", func() {
+		request(conn, "env-1", "interactive", blockActionsPayload("acknowledge", `{"rule":"r"}`))
+		Eventually(actions.handledActions).Should(ConsistOf(`acknowledge:{"rule":"r"}`))
+	})
+
+	It("ignores button clicks on unknown notifications", func() {
+		request(conn, "env-1", "interactive", blockActionsPayload("acknowledge", "unknown"))
+		Consistently(actions.handledActions, 200*time.Millisecond).Should(BeEmpty())
+	})
+
+	It("answers slash commands with the namespaces of its Slack App", func() {
+		ack := request(conn, "env-2", "slash_commands", `{"command": "/k8s-cron", "text": "list", "channel_id": "C123", "user_id": "U123", "is_enterprise_install": "false"}`)
+		Expect(ack).To(HaveKeyWithValue("payload", HaveKeyWithValue("text", "ok: list")))
+		Expect(ack).To(HaveKeyWithValue("payload", HaveKeyWithValue("response_type", slack.ResponseTypeEphemeral)))
+		Expect(commands.queried()).To(Equal([][]string{{"team-c"}}))
+	})
+
+	It("refuses slash commands from channels that may not query any namespace", func() {
+		ack := request(conn, "env-3", "slash_commands", `{"command": "/k8s-cron", "text": "list", "channel_id": "C999", "is_enterprise_install": "false"}`)
+		Expect(ack).To(HaveKeyWithValue("payload", HaveKeyWithValue("text", ContainSubstring("not allowed"))))
+		Expect(commands.queried()).To(BeEmpty())
+	})
+})
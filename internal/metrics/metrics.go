@@ -0,0 +1,198 @@
+// Package metrics defines the Prometheus metrics of the controller:
+// notifications by outcome, the latency of Slack API requests, the runs
+// waiting to be notified again, and the outcomes and durations of the runs of
+// CronJobs and CronWorkflows. They are registered with the controller-runtime
+// metrics registry and served on the metrics endpoint of the manager.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// namespace prefixes the names of the metrics.
+const namespace = "slack_notifier"
+
+// Outcomes of notifications.
+const (
+	OutcomeDelivered  = "delivered"
+	OutcomeFailed     = "failed"
+	OutcomeSuppressed = "suppressed"
+)
+
+// SinkSlack is the sink label of notifications delivered to Slack.
+const SinkSlack = "slack"
+
+// maxTrackedRuns bounds the runs remembered to count every run once.
+const maxTrackedRuns = 10000
+
+var (
+	notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Notifications by rule, sink, status and outcome (delivered, failed or suppressed).",
+	}, []string{"namespace", "rule", "sink", "status", "outcome"})
+
+	slackRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "slack_api_request_duration_seconds",
+		Help:      "Latency of Slack Web API and incoming webhook requests by API method and HTTP status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	retryQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "retry_queue_depth",
+		Help:      "Runs with notifications held back by quiet hours or waiting for escalation.",
+	})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Finished runs of CronJobs and CronWorkflows by outcome.",
+	}, []string{"namespace", "kind", "name", "status"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of finished runs of CronJobs and CronWorkflows by outcome.",
+		// 1s to about 9h
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"namespace", "kind", "name", "status"})
+
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time the last successful run of a CronJob or CronWorkflow finished.",
+	}, []string{"namespace", "kind", "name"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(notifications, slackRequestDuration, retryQueueDepth, jobRuns, jobDuration, jobLastSuccess)
+}
+
+// RecordNotification counts a notification of the rule namespace/rule with
+// status delivered to sink, SinkSlack for Slack.
+func RecordNotification(namespace, rule, sink, status, outcome string) {
+	notifications.WithLabelValues(namespace, rule, sink, status, outcome).Inc()
+}
+
+// pending is the set of runs whose notifications are held back.
+var pending = struct {
+	sync.Mutex
+	runs map[string]struct{}
+}{runs: map[string]struct{}{}}
+
+// SetPending records whether the notifications of the run identified by key
+// are waiting to be sent again.
+func SetPending(key string, held bool) {
+	pending.Lock()
+	defer pending.Unlock()
+	if held {
+		pending.runs[key] = struct{}{}
+	} else {
+		delete(pending.runs, key)
+	}
+	retryQueueDepth.Set(float64(len(pending.runs)))
+}
+
+// Run is the outcome of a finished run of a CronJob or CronWorkflow.
+type Run struct {
+	// UID identifies the Job or Workflow, which is only counted once per status.
+	UID       string
+	Namespace string
+	// Kind and Name identify the CronJob or CronWorkflow.
+	Kind   string
+	Name   string
+	Status string
+	// Duration is not observed if it is zero.
+	Duration time.Duration
+	// Time is when the run finished.
+	Time time.Time
+}
+
+// observed remembers the runs already counted, evicting the oldest ones.
+var observed = struct {
+	sync.Mutex
+	seen  map[string]struct{}
+	order []string
+	// lastSuccess is when the last successful run of each CronJob or
+	// CronWorkflow finished, as runs may be observed out of order.
+	lastSuccess map[string]time.Time
+}{seen: map[string]struct{}{}, lastSuccess: map[string]time.Time{}}
+
+// ObserveRun counts a finished run and observes its duration, and records
+// the time of a successful run unless a later one was recorded. Runs observed
+// before are ignored, as every reconcile of a Job or Workflow reports its
+// outcome again.
+func ObserveRun(run Run) {
+	key := run.UID + "/" + run.Status
+	observed.Lock()
+	if _, ok := observed.seen[key]; ok {
+		observed.Unlock()
+		return
+	}
+	observed.seen[key] = struct{}{}
+	observed.order = append(observed.order, key)
+	if len(observed.order) > maxTrackedRuns {
+		delete(observed.seen, observed.order[0])
+		observed.order = observed.order[1:]
+	}
+	latest := false
+	if strings.EqualFold(run.Status, "Succeeded") {
+		target := run.Namespace + "/" + run.Kind + "/" + run.Name
+		if last, ok := observed.lastSuccess[target]; !ok || run.Time.After(last) {
+			observed.lastSuccess[target] = run.Time
+			latest = true
+		}
+	}
+	observed.Unlock()
+
+	jobRuns.WithLabelValues(run.Namespace, run.Kind, run.Name, run.Status).Inc()
+	if run.Duration > 0 {
+		jobDuration.WithLabelValues(run.Namespace, run.Kind, run.Name, run.Status).Observe(run.Duration.Seconds())
+	}
+	if latest {
+		jobLastSuccess.WithLabelValues(run.Namespace, run.Kind, run.Name).Set(float64(run.Time.Unix()))
+	}
+}
+
+// InstrumentSlackTransport returns a RoundTripper observing the latency of
+// the requests next sends to Slack. Web API requests are labeled with their
+// method, e.g. chat.postMessage, and incoming webhooks with "webhook".
+func InstrumentSlackTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		slackRequestDuration.WithLabelValues(SlackMethod(req), code).Observe(time.Since(start).Seconds())
+		return resp, err
+	})
+}
+
+// SlackMethod returns the Web API method a request calls, or "webhook".
+func SlackMethod(req *http.Request) string {
+	dir, method, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if ok && dir == "api" && method != "" && !strings.Contains(method, "/") {
+		return method
+	}
+	return "webhook"
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
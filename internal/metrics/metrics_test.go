@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Metrics", func() {
+	It("counts notifications by rule, sink, status and outcome", func() {
+		RecordNotification("team-a", "backups", SinkSlack, "Failed", OutcomeDelivered)
+		RecordNotification("team-a", "backups", SinkSlack, "Failed", OutcomeDelivered)
+		RecordNotification("team-a", "backups", "pagerduty", "Failed", OutcomeFailed)
+		Expect(testutil.ToFloat64(notifications.WithLabelValues("team-a", "backups", SinkSlack, "Failed", OutcomeDelivered))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(notifications.WithLabelValues("team-a", "backups", "pagerduty", "Failed", OutcomeFailed))).To(Equal(1.0))
+	})
+
+	It("tracks the runs waiting to be notified again", func() {
+		SetPending("run-1", true)
+		SetPending("run-2", true)
+		SetPending("run-1", true)
+		Expect(testutil.ToFloat64(retryQueueDepth)).To(Equal(2.0))
+		SetPending("run-1", false)
+		SetPending("run-3", false)
+		Expect(testutil.ToFloat64(retryQueueDepth)).To(Equal(1.0))
+		SetPending("run-2", false)
+	})
+
+	It("counts every run once and records the last success", func() {
+		at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+		run := Run{UID: "0b5c", Namespace: "team-a", Kind: "CronJob", Name: "backup", Status: "Failed", Duration: 90 * time.Second, Time: at}
+		ObserveRun(run)
+		ObserveRun(run)
+		run.UID, run.Status = "9d1e", "Succeeded"
+		ObserveRun(run)
+
+		Expect(testutil.ToFloat64(jobRuns.WithLabelValues("team-a", "CronJob", "backup", "Failed"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(jobRuns.WithLabelValues("team-a", "CronJob", "backup", "Succeeded"))).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(jobDuration)).To(Equal(2))
+		Expect(testutil.ToFloat64(jobLastSuccess.WithLabelValues("team-a", "CronJob", "backup"))).To(Equal(float64(at.Unix())))
+
+		By("keeping the last success when an earlier one is observed later")
+		run.UID, run.Time = "7a2f", at.Add(-time.Hour)
+		ObserveRun(run)
+		Expect(testutil.ToFloat64(jobRuns.WithLabelValues("team-a", "CronJob", "backup", "Succeeded"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(jobLastSuccess.WithLabelValues("team-a", "CronJob", "backup"))).To(Equal(float64(at.Unix())))
+	})
+
+	It("observes the latency of Slack requests by method", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/auth.test" {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		DeferCleanup(srv.Close)
+		client := &http.Client{Transport: InstrumentSlackTransport(nil)}
+		for _, path := range []string{"/api/chat.postMessage", "/api/auth.test", "/services/T0/B0/x"} {
+			resp, err := client.Post(srv.URL+path, "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+		}
+
+		Expect(testutil.CollectAndCount(slackRequestDuration)).To(Equal(3))
+		for _, labels := range [][]string{{"chat.postMessage", "200"}, {"auth.test", "429"}, {"webhook", "200"}} {
+			var m dto.Metric
+			Expect(slackRequestDuration.WithLabelValues(labels...).(prometheus.Metric).Write(&m)).To(Succeed())
+			Expect(m.GetHistogram().GetSampleCount()).To(BeEquivalentTo(1), "%v", labels)
+		}
+	})
+})
@@ -0,0 +1,228 @@
+// Package policy enforces the channel policies of SlackConfigs and
+// SlackChannelPolicies on SlackNotificationRules.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+// ErrViolation is wrapped by the errors returned for notifications a policy forbids.
+var ErrViolation = errors.New("channel policy violation")
+
+// broadcastMention matches @channel, @here and @everyone, written out or in Slack's markup.
+var broadcastMention = regexp.MustCompile(`(?i)<!(channel|here|everyone)(\|[^>]*)?>|@(channel|here|everyone)\b`)
+
+// source is a policy that applies to a rule, with the name of the resource defining it.
+type source struct {
+	name   string
+	policy notificationv1alpha1.ChannelPolicy
+}
+
+// Policy is the combination of the channel policies a SlackNotificationRule
+// is subject to. A channel must be allowed by every policy restricting
+// channels, and broadcast mentions are rejected if any policy disallows them.
+type Policy struct {
+	sources []source
+	// configChannel is the default channel of the configuration of the rule.
+	configChannel string
+}
+
+// ForRule returns the Policy of a SlackNotificationRule: the ChannelPolicies of
+// the SlackConfig or ClusterSlackConfig it references, if it exists, and the
+// SlackChannelPolicies, that select the namespace of the rule.
+func ForRule(ctx context.Context, c client.Reader, rule *notificationv1alpha1.SlackNotificationRule) (*Policy, error) {
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: rule.Namespace}, &ns); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", rule.Namespace, err)
+	}
+	nsLabels := labels.Set(ns.Labels)
+
+	p := &Policy{}
+	add := func(name string, policy notificationv1alpha1.ChannelPolicy) error {
+		if policy.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector)
+			if err != nil {
+				return fmt.Errorf("invalid namespace selector in %s: %w", name, err)
+			}
+			if !selector.Matches(nsLabels) {
+				return nil
+			}
+		}
+		p.sources = append(p.sources, source{name: name, policy: policy})
+		return nil
+	}
+
+	spec, name, err := configSpec(ctx, c, rule)
+	if err != nil {
+		return nil, err
+	}
+	if spec != nil {
+		p.configChannel = spec.Channel
+		for i, policy := range spec.ChannelPolicies {
+			if err := add(fmt.Sprintf("%s channelPolicies[%d]", name, i), policy); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var policies notificationv1alpha1.SlackChannelPolicyList
+	if err := c.List(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("failed to list SlackChannelPolicies: %w", err)
+	}
+	for _, policy := range policies.Items {
+		if err := add("SlackChannelPolicy "+policy.Name, policy.Spec); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// configSpec returns the spec and a description of the SlackConfig or
+// ClusterSlackConfig a rule references, or nil if it does not exist.
+func configSpec(ctx context.Context, c client.Reader, rule *notificationv1alpha1.SlackNotificationRule) (*notificationv1alpha1.SlackConfigSpec, string, error) {
+	ref := rule.Spec.SlackConfigRef
+	var spec notificationv1alpha1.SlackConfigSpec
+	var name string
+	var err error
+	if ref.Kind == notificationv1alpha1.KindClusterSlackConfig {
+		var config notificationv1alpha1.ClusterSlackConfig
+		err = c.Get(ctx, types.NamespacedName{Name: ref.Name}, &config)
+		spec, name = config.Spec, "ClusterSlackConfig "+ref.Name
+	} else {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = rule.Namespace
+		}
+		var config notificationv1alpha1.SlackConfig
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &config)
+		spec, name = config.Spec, "SlackConfig "+namespace+"/"+ref.Name
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s: %w", name, err)
+	}
+	return &spec, name, nil
+}
+
+// CheckChannel returns an error wrapping ErrViolation if a policy does not allow channel.
+func (p *Policy) CheckChannel(channel string) error {
+	if channel == "" {
+		return nil
+	}
+	for _, src := range p.sources {
+		if len(src.policy.AllowedChannels) > 0 && !channelAllowed(src.policy.AllowedChannels, channel) {
+			return fmt.Errorf("%w: channel %s is not allowed by %s", ErrViolation, channel, src.name)
+		}
+	}
+	return nil
+}
+
+// CheckMentions returns an error wrapping ErrViolation if any of texts
+// mentions @channel, @here or @everyone and a policy disallows it.
+func (p *Policy) CheckMentions(texts ...string) error {
+	for _, text := range texts {
+		mention := broadcastMention.FindString(text)
+		if mention == "" {
+			continue
+		}
+		for _, src := range p.sources {
+			if src.policy.DisallowBroadcastMentions {
+				return fmt.Errorf("%w: %s mentions are not allowed by %s", ErrViolation, mention, src.name)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRule returns the channels and title templates of a rule spec the policy forbids.
+func (p *Policy) CheckRule(spec *notificationv1alpha1.SlackNotificationRuleSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	check := func(fld *field.Path, err error) {
+		if err != nil {
+			errs = append(errs, field.Forbidden(fld, strings.TrimPrefix(err.Error(), ErrViolation.Error()+": ")))
+		}
+	}
+	for i, note := range spec.Notifications {
+		notePath := fldPath.Child("notifications").Index(i)
+		if note.Channel != "" {
+			check(notePath.Child("channel"), p.CheckChannel(note.Channel))
+		} else if len(note.Destinations) == 0 {
+			check(notePath.Child("channel"), p.CheckChannel(p.configChannel))
+		}
+		if note.QuietHours != nil {
+			check(notePath.Child("quietHours", "channel"), p.CheckChannel(note.QuietHours.Channel))
+		}
+		if note.Escalation != nil {
+			check(notePath.Child("escalation", "channel"), p.CheckChannel(note.Escalation.Channel))
+		}
+		check(notePath.Child("title"), p.CheckMentions(note.Title))
+	}
+	return errs
+}
+
+// CheckDestinations returns the destinations of the notifications of a rule
+// the channel policies forbid. Each destination is subject to the policies of
+// the configuration it sends through rather than that of the rule.
+func CheckDestinations(ctx context.Context, c client.Reader, rule *notificationv1alpha1.SlackNotificationRule, fldPath *field.Path) (field.ErrorList, error) {
+	var errs field.ErrorList
+	for i, note := range rule.Spec.Notifications {
+		notePath := fldPath.Child("notifications").Index(i)
+		for j, dest := range note.Destinations {
+			if dest.SinkRef != nil {
+				// Channel policies restrict Slack channels only.
+				continue
+			}
+			view := rule.DeepCopy()
+			if dest.SlackConfigRef != nil {
+				view.Spec.SlackConfigRef = *dest.SlackConfigRef
+			}
+			p, err := ForRule(ctx, c, view)
+			if err != nil {
+				return nil, err
+			}
+			channel := dest.Channel
+			if channel == "" {
+				channel = p.configChannel
+			}
+			for _, err := range []error{p.CheckChannel(channel), p.CheckMentions(note.Title)} {
+				if err != nil {
+					errs = append(errs, field.Forbidden(notePath.Child("destinations").Index(j), strings.TrimPrefix(err.Error(), ErrViolation.Error()+": ")))
+				}
+			}
+		}
+	}
+	return errs, nil
+}
+
+// channelAllowed reports whether channel matches one of the allowed channels.
+// Names are compared without the leading # and case-insensitively.
+func channelAllowed(allowed []string, channel string) bool {
+	channel = normalizeChannel(channel)
+	for _, pattern := range allowed {
+		if ok, err := path.Match(normalizeChannel(pattern), channel); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeChannel(channel string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(channel), "#"))
+}
@@ -0,0 +1,119 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAlertName is the alertname label of alerts without one.
+	DefaultAlertName = "CronJobNotification"
+	// DefaultAlertExpiry is how long an alert fires without a recovery when
+	// its AlertmanagerSink sets no Expiry.
+	DefaultAlertExpiry = 24 * time.Hour
+)
+
+// alertmanagerAlertsPath is the path of the alerts API below the URL of Alertmanager.
+const alertmanagerAlertsPath = "/api/v2/alerts"
+
+// AlertmanagerSink posts alerts to the Alertmanager v2 API. Alerts are
+// labeled with the rule, namespace, target and status of a message, so that
+// the silences and routes of Alertmanager apply, and annotated with its
+// title and fields. Resolving an alert posts it again with endsAt set to now.
+type AlertmanagerSink struct {
+	// URL is the base URL of Alertmanager, e.g. http://alertmanager:9093.
+	URL string
+	// Labels are added to the labels of every alert. An alertname label
+	// replaces DefaultAlertName.
+	Labels map[string]string
+	// Expiry is how long an alert fires unless it is resolved or sent again.
+	// Defaults to DefaultAlertExpiry.
+	Expiry time.Duration
+	// Client sends the requests. Defaults to a client timing out after 30 seconds.
+	Client *http.Client
+	// Now returns the start and end times of alerts. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// alert is an alert of the Alertmanager v2 API.
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    string            `json:"startsAt,omitempty"`
+	EndsAt      string            `json:"endsAt"`
+}
+
+// Send fires the alert of msg until it is resolved or Expiry passes.
+func (s *AlertmanagerSink) Send(ctx context.Context, msg *Message) error {
+	now := s.now()
+	expiry := s.Expiry
+	if expiry <= 0 {
+		expiry = DefaultAlertExpiry
+	}
+	annotations := map[string]string{"summary": titleOrDefault(msg.Title)}
+	for _, f := range msg.Fields {
+		if name := annotationName(f.Title); name != "" && f.Value != "" {
+			annotations[name] = f.Value
+		}
+	}
+	return s.post(ctx, alert{
+		Labels:      s.labels(msg),
+		Annotations: annotations,
+		StartsAt:    now.UTC().Format(time.RFC3339),
+		EndsAt:      now.Add(expiry).UTC().Format(time.RFC3339),
+	})
+}
+
+// Resolve ends the alert of msg. Alertmanager keeps the start time of the
+// firing alert with the same labels.
+func (s *AlertmanagerSink) Resolve(ctx context.Context, msg *Message) error {
+	return s.post(ctx, alert{Labels: s.labels(msg), EndsAt: s.now().UTC().Format(time.RFC3339)})
+}
+
+// labels returns the labels identifying the alert of msg.
+func (s *AlertmanagerSink) labels(msg *Message) map[string]string {
+	labels := map[string]string{"alertname": DefaultAlertName}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	namespace, target, _ := strings.Cut(msg.Source, "/")
+	for k, v := range map[string]string{"rule": msg.Rule, "namespace": namespace, "target": target, "status": msg.Status} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+func (s *AlertmanagerSink) post(ctx context.Context, a alert) error {
+	body, err := json.Marshal([]alert{a})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, strings.TrimSuffix(s.URL, "/")+alertmanagerAlertsPath, nil, body)
+}
+
+func (s *AlertmanagerSink) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// annotationName converts the title of a field to an annotation name, e.g.
+// "Duration" to "duration".
+func annotationName(title string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(title) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9' && b.Len() > 0:
+			b.WriteRune(c)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
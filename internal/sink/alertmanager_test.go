@@ -0,0 +1,94 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AlertmanagerSink", func() {
+	var (
+		ctx context.Context
+		msg *Message
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+		msg = &Message{
+			Title:  "Job nightly failed",
+			Status: "Failed",
+			Source: "team-a/nightly",
+			Rule:   "backups",
+			Fields: []Field{{Title: "Namespace", Value: "team-a"}, {Title: "Duration", Value: ""}, {Title: "Reason", Value: "BackoffLimitExceeded"}},
+		}
+	})
+
+	alerts := func(body []byte) []map[string]any {
+		var alerts []map[string]any
+		ExpectWithOffset(1, json.Unmarshal(body, &alerts)).To(Succeed())
+		return alerts
+	}
+
+	It("fires alerts labeled by rule, namespace, target and status and resolves them", func() {
+		srv, requests := standIn(http.StatusOK)
+		s := &AlertmanagerSink{
+			URL:    srv.URL + "/",
+			Labels: map[string]string{"severity": "page"},
+			Now:    func() time.Time { return now },
+		}
+		labels := map[string]any{
+			"alertname": "CronJobNotification",
+			"severity":  "page",
+			"rule":      "backups",
+			"namespace": "team-a",
+			"target":    "nightly",
+			"status":    "Failed",
+		}
+
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.uri).To(Equal("/api/v2/alerts"))
+		Expect(alerts(req.body)).To(Equal([]map[string]any{{
+			"labels":      labels,
+			"annotations": map[string]any{"summary": "Job nightly failed", "namespace": "team-a", "reason": "BackoffLimitExceeded"},
+			"startsAt":    "2025-01-15T10:00:00Z",
+			"endsAt":      "2025-01-16T10:00:00Z",
+		}}))
+
+		now = now.Add(time.Hour)
+		Expect(s.Resolve(ctx, &Message{Status: "Failed", Source: "team-a/nightly", Rule: "backups"})).To(Succeed())
+		Eventually(requests).Should(Receive(&req))
+		Expect(alerts(req.body)).To(Equal([]map[string]any{{"labels": labels, "endsAt": "2025-01-15T11:00:00Z"}}))
+	})
+
+	It("lets an alertname label and the expiry be configured", func() {
+		srv, requests := standIn(http.StatusOK)
+		s := &AlertmanagerSink{URL: srv.URL, Labels: map[string]string{"alertname": "BackupFailed"}, Expiry: time.Hour, Now: func() time.Time { return now }}
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(alerts(req.body)[0]).To(And(
+			HaveKeyWithValue("labels", HaveKeyWithValue("alertname", "BackupFailed")),
+			HaveKeyWithValue("endsAt", "2025-01-15T11:00:00Z"),
+		))
+	})
+
+	It("fails when Alertmanager rejects the alert", func() {
+		srv, _ := standIn(http.StatusBadRequest)
+		Expect((&AlertmanagerSink{URL: srv.URL}).Send(ctx, msg)).To(MatchError(ContainSubstring("400 Bad Request")))
+	})
+
+	It("converts field titles to annotation names", func() {
+		Expect(annotationName("Duration")).To(Equal("duration"))
+		Expect(annotationName("Last Run (UTC)")).To(Equal("last_run_utc"))
+		Expect(annotationName("2nd try")).To(Equal("nd_try"))
+		Expect(annotationName("!!")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,219 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS modes of an EmailSink.
+const (
+	TLSNone     = "None"
+	TLSStartTLS = "StartTLS"
+	TLSImplicit = "TLS"
+)
+
+// DefaultSMTPPort is the port of an EmailSink without one, for submission with STARTTLS.
+const DefaultSMTPPort = 587
+
+// defaultMaxRecipients is the default number of recipients of a single email.
+const defaultMaxRecipients = 50
+
+// emailHTML renders the HTML body of an email.
+var emailHTML = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+<h2 style="border-left: 6px solid {{ .Color }}; padding-left: 8px">{{ .Title }}</h2>
+<table cellpadding="4">
+{{- range .Fields }}{{ if .Value }}
+<tr><th align="left">{{ .Title }}</th><td>{{ .Value }}</td></tr>
+{{- end }}{{ end }}
+</table>
+</body>
+</html>
+`))
+
+// EmailSink sends messages as emails over SMTP. Recipients are split into
+// batches of MaxRecipients, each sent a single email.
+type EmailSink struct {
+	Host string
+	// Port defaults to DefaultSMTPPort.
+	Port int
+	// TLS is TLSNone, TLSStartTLS or TLSImplicit. Defaults to TLSStartTLS.
+	TLS string
+	// Username and Password authenticate with PLAIN auth if Username is set.
+	Username string
+	Password string
+	From     string
+	To       []string
+	// MaxRecipients is the most recipients of a single email. Defaults to 50.
+	MaxRecipients int
+	// TLSConfig is used to secure the connection. Defaults to verifying the
+	// certificate of Host.
+	TLSConfig *tls.Config
+	// Now returns the date of the emails. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Send emails msg to the recipients, stopping at the first batch that fails.
+func (s *EmailSink) Send(ctx context.Context, msg *Message) error {
+	batch := s.MaxRecipients
+	if batch <= 0 {
+		batch = defaultMaxRecipients
+	}
+	for start := 0; start < len(s.To); start += batch {
+		to := s.To[start:min(start+batch, len(s.To))]
+		body, err := s.render(msg, to)
+		if err != nil {
+			return err
+		}
+		if err := s.send(ctx, to, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render returns the email of msg to the recipients to, with a plain text
+// and an HTML body.
+func (s *EmailSink) render(msg *Message, to []string) ([]byte, error) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", s.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", titleOrDefault(msg.Title)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+
+	var text strings.Builder
+	text.WriteString(titleOrDefault(msg.Title) + "\n\n")
+	for _, f := range msg.Fields {
+		if f.Value != "" {
+			fmt.Fprintf(&text, "%s: %s\n", f.Title, f.Value)
+		}
+	}
+	var html bytes.Buffer
+	err := emailHTML.Execute(&html, map[string]any{
+		"Title":  titleOrDefault(msg.Title),
+		"Color":  fmt.Sprintf("#%06x", discordColor(msg.Color)),
+		"Fields": msg.Fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	for _, part := range []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=utf-8", []byte(text.String())},
+		{"text/html; charset=utf-8", html.Bytes()},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.body); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// send sends body to the recipients to in a single SMTP transaction.
+func (s *EmailSink) send(ctx context.Context, to []string, body []byte) error {
+	port := s.Port
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+	tlsConfig := s.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: s.Host, MinVersion: tls.VersionTLS12}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+	if s.TLS == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if s.TLS == "" || s.TLS == TLSStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", s.From, err)
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	for _, rcpt := range to {
+		addr, err := mail.ParseAddress(rcpt)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", rcpt, err)
+		}
+		if err := c.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", addr.Address, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,254 @@
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// smtpMessage is an email received by smtpServer.
+type smtpMessage struct {
+	tls  bool
+	auth string
+	from string
+	to   []string
+	data []byte
+}
+
+// smtpServer is an in-process SMTP server accepting every email.
+type smtpServer struct {
+	host, port  string
+	implicitTLS bool
+	tlsConfig   *tls.Config
+	messages    chan smtpMessage
+}
+
+// startSMTPServer starts an SMTP server offering STARTTLS, or expecting TLS
+// right away if implicitTLS is set. It returns the server and the TLS
+// configuration trusting its certificate.
+func startSMTPServer(implicitTLS bool) (*smtpServer, *tls.Config) {
+	// httptest provides a certificate for 127.0.0.1 and a pool trusting it.
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	DeferCleanup(ts.Close)
+	clientTLS := &tls.Config{RootCAs: ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs, ServerName: "127.0.0.1"}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(ln.Close)
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	Expect(err).NotTo(HaveOccurred())
+	s := &smtpServer{
+		host:        host,
+		port:        port,
+		implicitTLS: implicitTLS,
+		tlsConfig:   &tls.Config{Certificates: ts.TLS.Certificates},
+		messages:    make(chan smtpMessage, 10),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, clientTLS
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	var m smtpMessage
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+		m.tls = true
+	}
+	tp := textproto.NewConn(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_ = tp.PrintfLine("%s", line)
+		}
+	}
+	reply("220 127.0.0.1 ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		arg := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		switch strings.ToUpper(fields[0]) {
+		case "EHLO", "HELO":
+			if m.tls {
+				reply("250-127.0.0.1", "250 AUTH PLAIN")
+			} else {
+				reply("250-127.0.0.1", "250-STARTTLS", "250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, tp, m.tls = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			m.auth = string(decoded)
+			reply("235 Authenticated")
+		case "MAIL":
+			m.from = strings.Trim(strings.TrimPrefix(strings.ToUpper(arg[:5])+arg[5:], "FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			m.to = append(m.to, strings.Trim(arg[3:], "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			m.data, _ = tp.ReadDotBytes()
+			s.messages <- m
+			m = smtpMessage{tls: m.tls, auth: m.auth}
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// parts returns the headers of an email and its bodies by content type.
+func parts(data []byte) (mail.Header, map[string]string) {
+	m, err := mail.ReadMessage(strings.NewReader(string(data)))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	_, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	bodies := map[string]string{}
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(p)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		mediaType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		bodies[mediaType] = string(body)
+	}
+	return m.Header, bodies
+}
+
+var _ = Describe("EmailSink", func() {
+	var (
+		ctx context.Context
+		msg *Message
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		msg = &Message{
+			Title:  "Job nightly <failed>",
+			Status: "Failed",
+			Color:  ColorDanger,
+			Fields: []Field{{Title: "Namespace", Value: "team-a"}, {Title: "Duration", Value: ""}},
+		}
+	})
+
+	newSink := func(srv *smtpServer, tlsConfig *tls.Config, mode string) *EmailSink {
+		port, err := strconv.Atoi(srv.port)
+		Expect(err).NotTo(HaveOccurred())
+		return &EmailSink{
+			Host:      srv.host,
+			Port:      port,
+			TLS:       mode,
+			TLSConfig: tlsConfig,
+			Username:  "notifier",
+			Password:  "s3cret",
+			From:      "Notifier <notifier@example.com>",
+			To:        []string{"oncall@example.com", "Team A <team-a@example.com>"},
+			Now:       func() time.Time { return time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC) },
+		}
+	}
+
+	It("sends a plain text and an HTML body over STARTTLS", func() {
+		srv, tlsConfig := startSMTPServer(false)
+		Expect(newSink(srv, tlsConfig, TLSStartTLS).Send(ctx, msg)).To(Succeed())
+
+		var m smtpMessage
+		Eventually(srv.messages).Should(Receive(&m))
+		Expect(m.tls).To(BeTrue())
+		Expect(m.auth).To(Equal("\x00notifier\x00s3cret"))
+		Expect(m.from).To(Equal("notifier@example.com"))
+		Expect(m.to).To(Equal([]string{"oncall@example.com", "team-a@example.com"}))
+
+		header, bodies := parts(m.data)
+		Expect(header.Get("From")).To(Equal("Notifier <notifier@example.com>"))
+		Expect(header.Get("To")).To(Equal("oncall@example.com, Team A <team-a@example.com>"))
+		subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subject).To(Equal("Job nightly <failed>"))
+		Expect(header.Get("Date")).To(Equal("Wed, 15 Jan 2025 10:00:00 +0000"))
+		Expect(bodies["text/plain"]).To(Equal("Job nightly <failed>\n\nNamespace: team-a\n"))
+		Expect(bodies["text/html"]).To(ContainSubstring("Job nightly &lt;failed&gt;"))
+		Expect(bodies["text/html"]).To(ContainSubstring("<th align=\"left\">Namespace</th><td>team-a</td>"))
+		Expect(bodies["text/html"]).NotTo(ContainSubstring("Duration"))
+	})
+
+	It("connects with implicit TLS", func() {
+		srv, tlsConfig := startSMTPServer(true)
+		Expect(newSink(srv, tlsConfig, TLSImplicit).Send(ctx, msg)).To(Succeed())
+		var m smtpMessage
+		Eventually(srv.messages).Should(Receive(&m))
+		Expect(m.tls).To(BeTrue())
+	})
+
+	It("sends without TLS to a local server", func() {
+		srv, _ := startSMTPServer(false)
+		s := newSink(srv, nil, TLSNone)
+		s.Username = ""
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var m smtpMessage
+		Eventually(srv.messages).Should(Receive(&m))
+		Expect(m.tls).To(BeFalse())
+		Expect(m.auth).To(BeEmpty())
+	})
+
+	It("batches recipients", func() {
+		srv, tlsConfig := startSMTPServer(false)
+		s := newSink(srv, tlsConfig, TLSStartTLS)
+		s.To = []string{"a@example.com", "b@example.com", "c@example.com"}
+		s.MaxRecipients = 2
+		Expect(s.Send(ctx, msg)).To(Succeed())
+
+		var first, second smtpMessage
+		Eventually(srv.messages).Should(Receive(&first))
+		Eventually(srv.messages).Should(Receive(&second))
+		Expect(first.to).To(Equal([]string{"a@example.com", "b@example.com"}))
+		Expect(second.to).To(Equal([]string{"c@example.com"}))
+		header, _ := parts(second.data)
+		Expect(header.Get("To")).To(Equal("c@example.com"))
+	})
+
+	It("fails when the server certificate is not trusted", func() {
+		srv, _ := startSMTPServer(false)
+		err := newSink(srv, nil, TLSStartTLS).Send(ctx, msg)
+		Expect(err).To(MatchError(ContainSubstring("failed to start TLS")))
+	})
+})
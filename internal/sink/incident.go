@@ -0,0 +1,184 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Severities of incidents.
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Default endpoints of the incident services.
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com"
+)
+
+// incidentSource is the source incidents are reported by.
+const incidentSource = "slack-notifier-controller"
+
+// errNoDedupKey is returned for messages sent to an IncidentSink without a DedupKey.
+var errNoDedupKey = errors.New("incidents require a dedup key")
+
+// PagerDutySink triggers and resolves PagerDuty incidents with the Events API v2.
+type PagerDutySink struct {
+	// URL is the Events API endpoint. Defaults to DefaultPagerDutyURL.
+	URL string
+	// RoutingKey is the integration key of the Events API v2 integration.
+	RoutingKey string
+	// Severity is the severity of triggered incidents. Defaults to SeverityCritical.
+	Severity string
+	// Client sends the requests. Defaults to a client timing out after 30 seconds.
+	Client *http.Client
+}
+
+// pagerDutySummaryLimit is the maximum length of the summary of a PagerDuty event.
+const pagerDutySummaryLimit = 1024
+
+func (s *PagerDutySink) Send(ctx context.Context, msg *Message) error {
+	if msg.DedupKey == "" {
+		return errNoDedupKey
+	}
+	source := msg.Source
+	if source == "" {
+		source = incidentSource
+	}
+	return s.enqueue(ctx, map[string]any{
+		"event_action": "trigger",
+		"dedup_key":    msg.DedupKey,
+		"payload": map[string]any{
+			"summary":        truncate(titleOrDefault(msg.Title), pagerDutySummaryLimit),
+			"source":         source,
+			"severity":       severityOrDefault(s.Severity),
+			"custom_details": fieldMap(msg.Fields),
+		},
+	})
+}
+
+func (s *PagerDutySink) Resolve(ctx context.Context, msg *Message) error {
+	return s.enqueue(ctx, map[string]any{"event_action": "resolve", "dedup_key": msg.DedupKey})
+}
+
+func (s *PagerDutySink) enqueue(ctx context.Context, event map[string]any) error {
+	event["routing_key"] = s.RoutingKey
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	endpoint := s.URL
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyURL
+	}
+	return post(ctx, s.Client, endpoint, nil, body)
+}
+
+// OpsgenieSink creates and closes Opsgenie alerts with the Alert API. The
+// dedup key of a message is the alias of its alert.
+type OpsgenieSink struct {
+	// URL is the base URL of the API. Defaults to DefaultOpsgenieURL.
+	URL string
+	// APIKey is the key of an API integration.
+	APIKey string
+	// Severity is mapped to the priority of created alerts: critical is P1,
+	// error P2, warning P3 and info P4. Defaults to SeverityCritical.
+	Severity string
+	// Client sends the requests. Defaults to a client timing out after 30 seconds.
+	Client *http.Client
+}
+
+// opsgenieMessageLimit is the maximum length of the message of an Opsgenie alert.
+const opsgenieMessageLimit = 130
+
+func (s *OpsgenieSink) Send(ctx context.Context, msg *Message) error {
+	if msg.DedupKey == "" {
+		return errNoDedupKey
+	}
+	var description strings.Builder
+	for _, f := range msg.Fields {
+		if f.Value != "" {
+			fmt.Fprintf(&description, "%s: %s\n", f.Title, f.Value)
+		}
+	}
+	body, err := json.Marshal(map[string]any{
+		"message":     truncate(titleOrDefault(msg.Title), opsgenieMessageLimit),
+		"alias":       msg.DedupKey,
+		"description": description.String(),
+		"details":     fieldMap(msg.Fields),
+		"entity":      msg.Source,
+		"source":      incidentSource,
+		"priority":    opsgeniePriority(severityOrDefault(s.Severity)),
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, s.endpoint("v2/alerts"), s.headers(), body)
+}
+
+func (s *OpsgenieSink) Resolve(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(map[string]any{"source": incidentSource, "note": "A later run succeeded"})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, s.endpoint("v2/alerts/"+url.PathEscape(msg.DedupKey)+"/close?identifierType=alias"), s.headers(), body)
+}
+
+func (s *OpsgenieSink) endpoint(path string) string {
+	base := s.URL
+	if base == "" {
+		base = DefaultOpsgenieURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + path
+}
+
+func (s *OpsgenieSink) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + s.APIKey}
+}
+
+func opsgeniePriority(severity string) string {
+	switch severity {
+	case SeverityError:
+		return "P2"
+	case SeverityWarning:
+		return "P3"
+	case SeverityInfo:
+		return "P4"
+	}
+	return "P1"
+}
+
+func severityOrDefault(severity string) string {
+	if severity == "" {
+		return SeverityCritical
+	}
+	return severity
+}
+
+// fieldMap returns the non-empty fields by title.
+func fieldMap(fields []Field) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if f.Value != "" {
+			m[f.Title] = f.Value
+		}
+	}
+	return m
+}
+
+// truncate shortens s to limit runes, ending it with an ellipsis if it is cut.
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(append(r[:limit-1], '…'))
+}
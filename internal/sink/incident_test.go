@@ -0,0 +1,83 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Incident sinks", func() {
+	var (
+		ctx context.Context
+		msg *Message
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		msg = &Message{
+			Title:    "Job nightly failed",
+			Status:   "Failed",
+			Source:   "team-a/nightly",
+			Fields:   []Field{{Title: "Namespace", Value: "team-a"}, {Title: "Duration", Value: ""}},
+			DedupKey: "team-a/backups/0b5c",
+		}
+	})
+
+	It("triggers and resolves PagerDuty incidents", func() {
+		srv, requests := standIn(http.StatusAccepted)
+		s := &PagerDutySink{URL: srv.URL + "/v2/enqueue", RoutingKey: "R0UT1NG", Severity: SeverityError}
+
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.uri).To(Equal("/v2/enqueue"))
+		event := decode(req.body)
+		Expect(event).To(HaveKeyWithValue("routing_key", "R0UT1NG"))
+		Expect(event).To(HaveKeyWithValue("event_action", "trigger"))
+		Expect(event).To(HaveKeyWithValue("dedup_key", "team-a/backups/0b5c"))
+		Expect(event["payload"]).To(And(
+			HaveKeyWithValue("summary", "Job nightly failed"),
+			HaveKeyWithValue("source", "team-a/nightly"),
+			HaveKeyWithValue("severity", "error"),
+			HaveKeyWithValue("custom_details", Equal(map[string]any{"Namespace": "team-a"})),
+		))
+
+		Expect(s.Resolve(ctx, &Message{DedupKey: msg.DedupKey})).To(Succeed())
+		Eventually(requests).Should(Receive(&req))
+		Expect(decode(req.body)).To(Equal(map[string]any{"routing_key": "R0UT1NG", "event_action": "resolve", "dedup_key": "team-a/backups/0b5c"}))
+	})
+
+	It("creates and closes Opsgenie alerts by alias", func() {
+		srv, requests := standIn(http.StatusAccepted)
+		s := &OpsgenieSink{URL: srv.URL + "/", APIKey: "k3y"}
+
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.uri).To(Equal("/v2/alerts"))
+		Expect(req.header.Get("Authorization")).To(Equal("GenieKey k3y"))
+		alert := decode(req.body)
+		Expect(alert).To(HaveKeyWithValue("message", "Job nightly failed"))
+		Expect(alert).To(HaveKeyWithValue("alias", "team-a/backups/0b5c"))
+		Expect(alert).To(HaveKeyWithValue("priority", "P1"))
+		Expect(alert).To(HaveKeyWithValue("description", "Namespace: team-a\n"))
+
+		Expect(s.Resolve(ctx, &Message{DedupKey: msg.DedupKey})).To(Succeed())
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.uri).To(Equal("/v2/alerts/team-a%2Fbackups%2F0b5c/close?identifierType=alias"))
+		Expect(req.header.Get("Authorization")).To(Equal("GenieKey k3y"))
+	})
+
+	It("requires a dedup key", func() {
+		msg.DedupKey = ""
+		Expect((&PagerDutySink{}).Send(ctx, msg)).To(MatchError(errNoDedupKey))
+		Expect((&OpsgenieSink{}).Send(ctx, msg)).To(MatchError(errNoDedupKey))
+	})
+
+	It("reports rejected events", func() {
+		srv, _ := standIn(http.StatusBadRequest)
+		Expect((&PagerDutySink{URL: srv.URL}).Resolve(ctx, &Message{DedupKey: "key"})).To(MatchError(ContainSubstring("400 Bad Request")))
+	})
+})
@@ -0,0 +1,154 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// TeamsRenderer renders messages as Adaptive Cards for Microsoft Teams
+// workflows and incoming webhooks.
+type TeamsRenderer struct{}
+
+func (TeamsRenderer) Render(msg *Message) ([]byte, error) {
+	facts := make([]map[string]string, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		facts = append(facts, map[string]string{"title": f.Title, "value": f.Value})
+	}
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []any{
+			map[string]any{
+				"type":   "TextBlock",
+				"text":   titleOrDefault(msg.Title),
+				"weight": "Bolder",
+				"size":   "Medium",
+				"wrap":   true,
+				"color":  teamsColor(msg.Color),
+			},
+			map[string]any{"type": "FactSet", "facts": facts},
+		},
+	}
+	return json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []any{
+			map[string]any{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	})
+}
+
+func teamsColor(color string) string {
+	switch color {
+	case ColorGood:
+		return "Good"
+	case ColorDanger:
+		return "Attention"
+	}
+	return "Warning"
+}
+
+// discordTitleLimit is the maximum length of the title of a Discord embed.
+const discordTitleLimit = 256
+
+// DiscordRenderer renders messages as embeds for Discord webhooks.
+type DiscordRenderer struct{}
+
+func (DiscordRenderer) Render(msg *Message) ([]byte, error) {
+	fields := make([]map[string]any, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		// Discord rejects embeds with empty field values.
+		if f.Value == "" {
+			continue
+		}
+		fields = append(fields, map[string]any{"name": f.Title, "value": f.Value, "inline": true})
+	}
+	return json.Marshal(map[string]any{
+		"embeds": []any{
+			map[string]any{"title": truncate(titleOrDefault(msg.Title), discordTitleLimit), "color": discordColor(msg.Color), "fields": fields},
+		},
+	})
+}
+
+func discordColor(color string) int {
+	switch color {
+	case ColorGood:
+		return 0x2eb886
+	case ColorDanger:
+		return 0xa30200
+	}
+	return 0xdaa038
+}
+
+// MattermostRenderer renders messages as Slack-compatible attachments for
+// Mattermost incoming webhooks.
+type MattermostRenderer struct{}
+
+func (MattermostRenderer) Render(msg *Message) ([]byte, error) {
+	fields := make([]map[string]any, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		fields = append(fields, map[string]any{"title": f.Title, "value": f.Value, "short": true})
+	}
+	return json.Marshal(map[string]any{
+		"text": titleOrDefault(msg.Title),
+		"attachments": []any{
+			map[string]any{"color": fmt.Sprintf("#%06x", discordColor(msg.Color)), "fields": fields},
+		},
+	})
+}
+
+// TemplateRenderer renders messages with a Go template for generic webhooks.
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+// NewTemplateRenderer returns a TemplateRenderer executing bodyTemplate
+// against the Message, or rendering the Message as JSON if it is empty.
+func NewTemplateRenderer(bodyTemplate string) (*TemplateRenderer, error) {
+	if bodyTemplate == "" {
+		return &TemplateRenderer{}, nil
+	}
+	tmpl, err := ParseBodyTemplate(bodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+// ParseBodyTemplate parses the body template of a generic webhook. The
+// template has a json function encoding its argument as JSON.
+func ParseBodyTemplate(bodyTemplate string) (*template.Template, error) {
+	tmpl, err := template.New("body").Funcs(template.FuncMap{"json": toJSON}).Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse body template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (r *TemplateRenderer) Render(msg *Message) ([]byte, error) {
+	if r.tmpl == nil {
+		return json.Marshal(msg)
+	}
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, msg); err != nil {
+		return nil, fmt.Errorf("failed to execute body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func titleOrDefault(title string) string {
+	if title == "" {
+		return "Kubernetes Notification"
+	}
+	return title
+}
@@ -0,0 +1,177 @@
+// Package sink delivers notifications to chat and webhook services other than
+// Slack. A Sink posts a Message rendered by a Renderer for the service.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Types of sinks.
+const (
+	TypeTeams        = "Teams"
+	TypeDiscord      = "Discord"
+	TypeMattermost   = "Mattermost"
+	TypeWebhook      = "Webhook"
+	TypePagerDuty    = "PagerDuty"
+	TypeOpsgenie     = "Opsgenie"
+	TypeEmail        = "Email"
+	TypeAlertmanager = "Alertmanager"
+)
+
+// Colors of a Message, named like the colors of Slack attachments.
+const (
+	ColorGood    = "good"
+	ColorWarning = "warning"
+	ColorDanger  = "danger"
+)
+
+// Headers of a signed request.
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the timestamp, a dot and the body, keyed with the signing secret.
+	SignatureHeader = "X-Signature-256"
+	// TimestampHeader carries the Unix time the request was signed at.
+	TimestampHeader = "X-Signature-Timestamp"
+)
+
+// Message is a notification independent of the service it is delivered to.
+type Message struct {
+	// Title is the rendered title of the notification.
+	Title string `json:"title"`
+	// Status is the status the notification is sent for.
+	Status string `json:"status"`
+	// Source is the namespace/name of the object the notification is about.
+	Source string `json:"source,omitempty"`
+	// Rule is the name of the rule sending the notification.
+	Rule string `json:"rule,omitempty"`
+	// Color is ColorGood, ColorWarning or ColorDanger.
+	Color  string  `json:"color"`
+	Fields []Field `json:"fields"`
+	// Object is the object that triggered the notification, as unstructured data.
+	Object map[string]any `json:"object,omitempty"`
+	// DedupKey identifies the incident an IncidentSink opens for the message.
+	DedupKey string `json:"dedupKey,omitempty"`
+}
+
+// Field is a titled value shown with a notification.
+type Field struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Sink delivers messages to a service.
+type Sink interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// IncidentSink is a Sink opening incidents, which stay open until they are resolved.
+type IncidentSink interface {
+	Sink
+	// Resolve resolves the incident opened for msg. Only the DedupKey,
+	// Status, Source and Rule of msg are set.
+	Resolve(ctx context.Context, msg *Message) error
+}
+
+// Renderer renders a message to the JSON payload a service accepts.
+type Renderer interface {
+	Render(msg *Message) ([]byte, error)
+}
+
+// NewRenderer returns the Renderer of a sink type. bodyTemplate is only used
+// by TypeWebhook.
+func NewRenderer(sinkType, bodyTemplate string) (Renderer, error) {
+	switch sinkType {
+	case TypeTeams:
+		return TeamsRenderer{}, nil
+	case TypeDiscord:
+		return DiscordRenderer{}, nil
+	case TypeMattermost:
+		return MattermostRenderer{}, nil
+	case TypeWebhook:
+		return NewTemplateRenderer(bodyTemplate)
+	}
+	return nil, fmt.Errorf("unknown sink type %q", sinkType)
+}
+
+// HTTPSink posts messages to a URL.
+type HTTPSink struct {
+	URL      string
+	Renderer Renderer
+	// SigningSecret signs the requests with SignatureHeader and
+	// TimestampHeader. Requests are not signed if it is empty.
+	SigningSecret string
+	// Headers are added to the requests.
+	Headers map[string]string
+	// Client sends the requests. Defaults to a client timing out after 30 seconds.
+	Client *http.Client
+	// Now returns the time requests are signed at. Defaults to time.Now.
+	Now func() time.Time
+}
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+func (s *HTTPSink) Send(ctx context.Context, msg *Message) error {
+	body, err := s.Renderer.Render(msg)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{}
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	if s.SigningSecret != "" {
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+		timestamp := strconv.FormatInt(now().Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = Sign(s.SigningSecret, timestamp, body)
+	}
+	return post(ctx, s.Client, s.URL, headers, body)
+}
+
+// post posts the JSON body to url with client, or a default client if it is
+// nil. Responses other than 2xx are returned as errors.
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to post message: %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// Sign returns the value of SignatureHeader for body sent at timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// request is a request received by a local stand-in of a service.
+type request struct {
+	uri    string
+	header http.Header
+	body   []byte
+}
+
+// standIn starts an HTTP server recording the requests it receives and
+// answering them with status.
+func standIn(status int) (*httptest.Server, chan request) {
+	requests := make(chan request, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer GinkgoRecover()
+		Expect(r.Method).To(Equal(http.MethodPost))
+		body, err := io.ReadAll(r.Body)
+		Expect(err).NotTo(HaveOccurred())
+		requests <- request{uri: r.URL.RequestURI(), header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	DeferCleanup(srv.Close)
+	return srv, requests
+}
+
+func decode(body []byte) map[string]any {
+	var payload map[string]any
+	ExpectWithOffset(1, json.Unmarshal(body, &payload)).To(Succeed())
+	return payload
+}
+
+var _ = Describe("Sinks", func() {
+	var (
+		ctx context.Context
+		msg *Message
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		msg = &Message{
+			Title:  "Job nightly failed",
+			Status: "Failed",
+			Color:  ColorDanger,
+			Fields: []Field{{Title: "Namespace", Value: "team-a"}, {Title: "Duration", Value: ""}},
+			Object: map[string]any{"metadata": map[string]any{"name": "nightly-1"}},
+		}
+	})
+
+	send := func(sinkType, bodyTemplate string) (map[string]any, request) {
+		srv, requests := standIn(http.StatusOK)
+		renderer, err := NewRenderer(sinkType, bodyTemplate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect((&HTTPSink{URL: srv.URL, Renderer: renderer}).Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.header.Get("Content-Type")).To(Equal("application/json"))
+		if sinkType == TypeWebhook && bodyTemplate != "" {
+			return nil, req
+		}
+		return decode(req.body), req
+	}
+
+	It("posts Adaptive Cards to Microsoft Teams", func() {
+		payload, _ := send(TypeTeams, "")
+		Expect(payload).To(HaveKeyWithValue("type", "message"))
+		attachment := payload["attachments"].([]any)[0].(map[string]any)
+		Expect(attachment).To(HaveKeyWithValue("contentType", "application/vnd.microsoft.card.adaptive"))
+		body := attachment["content"].(map[string]any)["body"].([]any)
+		Expect(body[0]).To(HaveKeyWithValue("text", "Job nightly failed"))
+		Expect(body[0]).To(HaveKeyWithValue("color", "Attention"))
+		Expect(body[1].(map[string]any)["facts"]).To(ContainElement(HaveKeyWithValue("value", "team-a")))
+	})
+
+	It("posts embeds to Discord without empty fields", func() {
+		msg.Title = strings.Repeat("x", 300)
+		payload, _ := send(TypeDiscord, "")
+		embed := payload["embeds"].([]any)[0].(map[string]any)
+		Expect([]rune(embed["title"].(string))).To(HaveLen(discordTitleLimit))
+		Expect(embed).To(HaveKeyWithValue("color", BeNumerically("==", 0xa30200)))
+		Expect(embed["fields"]).To(ConsistOf(HaveKeyWithValue("name", "Namespace")))
+	})
+
+	It("posts attachments to Mattermost", func() {
+		payload, _ := send(TypeMattermost, "")
+		Expect(payload).To(HaveKeyWithValue("text", "Job nightly failed"))
+		attachment := payload["attachments"].([]any)[0].(map[string]any)
+		Expect(attachment).To(HaveKeyWithValue("color", "#a30200"))
+		Expect(attachment["fields"]).To(HaveLen(2))
+	})
+
+	It("posts the message as JSON to a generic webhook without a template", func() {
+		payload, _ := send(TypeWebhook, "")
+		Expect(payload).To(HaveKeyWithValue("title", "Job nightly failed"))
+		Expect(payload).To(HaveKeyWithValue("status", "Failed"))
+		Expect(payload["object"]).To(HaveKeyWithValue("metadata", HaveKeyWithValue("name", "nightly-1")))
+	})
+
+	It("renders the body template of a generic webhook", func() {
+		_, req := send(TypeWebhook, `{"summary": {{ json .Title }}, "job": {{ json .Object.metadata.name }}}`)
+		Expect(decode(req.body)).To(Equal(map[string]any{"summary": "Job nightly failed", "job": "nightly-1"}))
+	})
+
+	It("signs requests and adds headers", func() {
+		srv, requests := standIn(http.StatusNoContent)
+		s := &HTTPSink{
+			URL:           srv.URL,
+			Renderer:      &TemplateRenderer{},
+			SigningSecret: "s3cret",
+			Headers:       map[string]string{"Authorization": "Bearer abc"},
+			Now:           func() time.Time { return time.Unix(1700000000, 0) },
+		}
+		Expect(s.Send(ctx, msg)).To(Succeed())
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.header.Get("Authorization")).To(Equal("Bearer abc"))
+		Expect(req.header.Get(TimestampHeader)).To(Equal("1700000000"))
+		Expect(req.header.Get(SignatureHeader)).To(Equal(Sign("s3cret", "1700000000", req.body)))
+		Expect(req.header.Get(SignatureHeader)).To(HavePrefix("sha256="))
+	})
+
+	It("fails on responses other than 2xx", func() {
+		srv, _ := standIn(http.StatusBadRequest)
+		err := (&HTTPSink{URL: srv.URL, Renderer: DiscordRenderer{}}).Send(ctx, msg)
+		Expect(err).To(MatchError(ContainSubstring("400 Bad Request")))
+	})
+
+	It("rejects unknown types and invalid templates", func() {
+		_, err := NewRenderer("Pager", "")
+		Expect(err).To(HaveOccurred())
+		_, err = NewRenderer(TypeWebhook, "{{ .Title")
+		Expect(err).To(MatchError(ContainSubstring("failed to parse body template")))
+	})
+})
@@ -3,93 +3,400 @@ package slack
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 	"text/template"
 
 	"github.com/slack-go/slack"
+
+	"github.com/murasame29/slack-notifier-controller/internal/metrics"
+	"github.com/murasame29/slack-notifier-controller/internal/tracing"
+)
+
+// Action IDs of the buttons attached to notifications.
+const (
+	ActionAcknowledge = "acknowledge"
+	ActionRerun       = "rerun"
+	ActionSuspend     = "suspend"
+	ActionResume      = "resume"
+	ActionResubmit    = "resubmit"
 )
 
+const (
+	// ActionsBlockID is the block ID of the actions block carrying the buttons.
+	ActionsBlockID = "notification-actions"
+	// AcknowledgedBlockID is the block ID of the note replacing the Acknowledge button.
+	AcknowledgedBlockID = "notification-acknowledged"
+)
+
+// Button is an interactive button attached to a message.
+type Button struct {
+	ActionID string
+	Text     string
+	Value    string
+	Style    slack.Style
+}
+
 type Client interface {
-	Send(ctx context.Context, webhookURL string, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any) error
+	// Send posts a notification with token authentication, or to an incoming
+	// webhook. It returns the channel ID and timestamp of the message posted
+	// with token authentication, which are empty for a webhook.
+	Send(ctx context.Context, webhookURL string, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any) (string, string, error)
+	// SendWithActions sends like Send using token authentication and attaches
+	// the given buttons. It returns the channel ID and timestamp of the posted message.
+	SendWithActions(ctx context.Context, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any, buttons []Button) (string, string, error)
+	// Acknowledge replaces the Acknowledge button of a message posted by
+	// SendWithActions with a note naming the user who acknowledged it.
+	Acknowledge(ctx context.Context, token string, channelID string, ts string, msg slack.Message, userID string) error
+	// Reply posts text as a reply in the thread of the message ts.
+	Reply(ctx context.Context, token string, channelID string, ts string, text string) error
+	// UserGroupMembers returns the IDs of the users in a user group.
+	UserGroupMembers(ctx context.Context, token string, groupID string) ([]string, error)
+	// AuthTest checks token with auth.test and returns the identity it belongs to.
+	AuthTest(ctx context.Context, token string) (*slack.AuthTestResponse, error)
+	// ConversationInfo returns the channel messages to channel are posted to.
+	// channel is a conversation ID or a channel name, with or without a
+	// leading #. It returns ErrChannelNotFound if the channel does not exist
+	// or is not visible to the token.
+	ConversationInfo(ctx context.Context, token string, channel string) (*slack.Channel, error)
+	// RefreshToken exchanges the refresh token of an App with token rotation
+	// enabled for a new access token and refresh token with oauth.v2.access.
+	RefreshToken(ctx context.Context, clientID string, clientSecret string, refreshToken string) (*slack.OAuthV2Response, error)
+}
+
+// ErrChannelNotFound is returned by ConversationInfo for unknown channels.
+var ErrChannelNotFound = errors.New("channel not found")
+
+// conversationIDPattern matches the IDs of public and private channels and
+// direct messages, as opposed to channel names, which are lowercase.
+var conversationIDPattern = regexp.MustCompile(`^[CGD][A-Z0-9]+$`)
+
+// IsConversationID reports whether channel is a conversation ID rather than a
+// channel name with or without a leading #.
+func IsConversationID(channel string) bool {
+	return conversationIDPattern.MatchString(channel)
 }
 
 type slackClient struct {
 	httpClient *http.Client
+	apiURL     string
 }
 
-func NewClient() Client {
-	return &slackClient{
-		httpClient: &http.Client{},
+// Option configures a Client.
+type Option func(*slackClient)
+
+// WithAPIURL overrides the Slack Web API base URL (e.g., "https://slack.com/api/").
+func WithAPIURL(url string) Option {
+	return func(c *slackClient) {
+		c.apiURL = url
 	}
 }
 
-func (c *slackClient) Send(ctx context.Context, webhookURL string, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any) error {
-	// Render title
-	title := ""
-	if titleTmpl != "" {
-		tmplTitle, err := template.New("title").Parse(titleTmpl)
-		if err != nil {
-			return fmt.Errorf("failed to parse title template: %w", err)
-		}
-		var titleBuf bytes.Buffer
-		if err := tmplTitle.Execute(&titleBuf, data); err != nil {
-			return fmt.Errorf("failed to execute title template: %w", err)
-		}
-		title = titleBuf.String()
+func NewClient(opts ...Option) Client {
+	c := &slackClient{
+		httpClient: &http.Client{Transport: tracing.InstrumentTransport(metrics.InstrumentSlackTransport(nil), spanName)},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *slackClient) api(token string) *slack.Client {
+	options := []slack.Option{slack.OptionHTTPClient(c.httpClient)}
+	if c.apiURL != "" {
+		options = append(options, slack.OptionAPIURL(c.apiURL))
 	}
+	return slack.New(token, options...)
+}
 
-	attachment := slack.Attachment{
-		Color:  color, // Valid values: "good", "warning", "danger", or hex
-		Fields: fields,
+// ParseTitle parses a title template.
+func ParseTitle(titleTmpl string) (*template.Template, error) {
+	tmplTitle, err := template.New("title").Parse(titleTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse title template: %w", err)
+	}
+	return tmplTitle, nil
+}
+
+// RenderTitle renders a title template against data.
+func RenderTitle(titleTmpl string, data any) (string, error) {
+	if titleTmpl == "" {
+		return "", nil
+	}
+	tmplTitle, err := ParseTitle(titleTmpl)
+	if err != nil {
+		return "", err
+	}
+	var titleBuf bytes.Buffer
+	if err := tmplTitle.Execute(&titleBuf, data); err != nil {
+		return "", fmt.Errorf("failed to execute title template: %w", err)
+	}
+	return titleBuf.String(), nil
+}
+
+// renderTitle renders a title template like RenderTitle in a span of ctx.
+func renderTitle(ctx context.Context, titleTmpl string, data any) (title string, err error) {
+	_, span := tracing.Start(ctx, "RenderTitle")
+	defer func() { tracing.End(span, err) }()
+	return RenderTitle(titleTmpl, data)
+}
+
+// spanName names the span of a request to Slack after its Web API method.
+func spanName(req *http.Request) string {
+	return "Slack " + metrics.SlackMethod(req)
+}
+
+// NewMessage returns the message a notification with the rendered title is
+// posted as: the title as its text and the fields in an attachment of the
+// given color. channel is only set for incoming webhooks.
+func NewMessage(channel string, title string, color string, fields []slack.AttachmentField) *slack.WebhookMessage {
+	// Use Title as the main message text
+	text := "Kubernetes Notification"
+	if title != "" {
+		text = title
+	}
+	return &slack.WebhookMessage{
+		Channel: channel,
+		Text:    text,
+		Attachments: []slack.Attachment{{
+			Color:  color, // Valid values: "good", "warning", "danger", or hex
+			Fields: fields,
+		}},
+	}
+}
+
+// NewMessageWithActions returns the message of NewMessage with the title
+// repeated in a section block followed by the buttons.
+func NewMessageWithActions(channel string, title string, color string, fields []slack.AttachmentField, buttons []Button) *slack.WebhookMessage {
+	msg := NewMessage(channel, title, color, fields)
+	elements := make([]slack.BlockElement, 0, len(buttons))
+	for _, b := range buttons {
+		button := slack.NewButtonBlockElement(b.ActionID, b.Value, slack.NewTextBlockObject(slack.PlainTextType, b.Text, false, false))
+		button.Style = b.Style
+		elements = append(elements, button)
+	}
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, msg.Text, false, false), nil, nil),
+	}
+	if len(elements) > 0 {
+		blocks = append(blocks, slack.NewActionBlock(ActionsBlockID, elements...))
+	}
+	msg.Blocks = &slack.Blocks{BlockSet: blocks}
+	return msg
+}
+
+// msgOptions returns the options posting msg with chat.postMessage.
+func msgOptions(msg *slack.WebhookMessage) []slack.MsgOption {
+	options := []slack.MsgOption{
+		slack.MsgOptionText(msg.Text, false),
+		slack.MsgOptionAttachments(msg.Attachments...),
+	}
+	if msg.Blocks != nil {
+		options = append(options, slack.MsgOptionBlocks(msg.Blocks.BlockSet...))
+	}
+	return options
+}
+
+func (c *slackClient) Send(ctx context.Context, webhookURL string, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any) (string, string, error) {
+	// Render title
+	title, err := renderTitle(ctx, titleTmpl, data)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Send via Token (API)
 	if token != "" {
-		api := slack.New(token)
+		api := c.api(token)
 		// If channel is not provided, we must fail or rely on default
 		if channel == "" {
-			return fmt.Errorf("channel is required when using token authentication")
-		}
-
-		options := []slack.MsgOption{
-			slack.MsgOptionAttachments(attachment),
+			return "", "", fmt.Errorf("channel is required when using token authentication")
 		}
 
-		// Use Title as the main message text
-		mainText := "Kubernetes Notification"
-		if title != "" {
-			mainText = title
-		}
-		options = append(options, slack.MsgOptionText(mainText, false))
-
-		_, _, err := api.PostMessageContext(ctx, channel, options...)
+		channelID, ts, err := api.PostMessageContext(ctx, channel, msgOptions(NewMessage("", title, color, fields))...)
 		if err != nil {
-			return fmt.Errorf("failed to post message to slack via API: %w", err)
+			return "", "", fmt.Errorf("failed to post message to slack via API: %w", err)
 		}
-		return nil
+		return channelID, ts, nil
 	}
 
 	// Send via Webhook
 	if webhookURL != "" {
-		msg := &slack.WebhookMessage{
-			Attachments: []slack.Attachment{attachment},
-		}
-		if title != "" {
-			msg.Text = title
-		} else {
-			msg.Text = "Kubernetes Notification"
+		err := slack.PostWebhookCustomHTTPContext(ctx, webhookURL, c.httpClient, NewMessage(channel, title, color, fields))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to post webhook: %w", err)
 		}
+		return "", "", nil
+	}
+
+	return "", "", fmt.Errorf("neither token nor webhookURL provided")
+}
+
+func (c *slackClient) SendWithActions(ctx context.Context, token string, channel string, titleTmpl string, color string, fields []slack.AttachmentField, data any, buttons []Button) (string, string, error) {
+	if token == "" {
+		return "", "", fmt.Errorf("token is required to send interactive messages")
+	}
+	if channel == "" {
+		return "", "", fmt.Errorf("channel is required when using token authentication")
+	}
+
+	title, err := renderTitle(ctx, titleTmpl, data)
+	if err != nil {
+		return "", "", err
+	}
 
-		if channel != "" {
-			msg.Channel = channel
+	msg := NewMessageWithActions("", title, color, fields, buttons)
+	channelID, ts, err := c.api(token).PostMessageContext(ctx, channel, msgOptions(msg)...)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to post message to slack via API: %w", err)
+	}
+	return channelID, ts, nil
+}
+
+func (c *slackClient) Acknowledge(ctx context.Context, token string, channelID string, ts string, msg slack.Message, userID string) error {
+	blocks := make([]slack.Block, 0, len(msg.Blocks.BlockSet)+1)
+	for _, b := range msg.Blocks.BlockSet {
+		switch b.ID() {
+		case AcknowledgedBlockID:
+			continue
+		case ActionsBlockID:
+			// Keep the other buttons, drop the Acknowledge button.
+			if actions, ok := b.(*slack.ActionBlock); ok && actions.Elements != nil {
+				var remaining []slack.BlockElement
+				for _, e := range actions.Elements.ElementSet {
+					if button, ok := e.(*slack.ButtonBlockElement); ok && button.ActionID == ActionAcknowledge {
+						continue
+					}
+					remaining = append(remaining, e)
+				}
+				if len(remaining) > 0 {
+					blocks = append(blocks, slack.NewActionBlock(ActionsBlockID, remaining...))
+				}
+				continue
+			}
 		}
-		err := slack.PostWebhookContext(ctx, webhookURL, msg)
+		blocks = append(blocks, b)
+	}
+	blocks = append(blocks, slack.NewContextBlock(AcknowledgedBlockID,
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf(":white_check_mark: Acknowledged by <@%s>", userID), false, false)))
+
+	_, _, _, err := c.api(token).UpdateMessageContext(ctx, channelID, ts,
+		slack.MsgOptionText(msg.Text, false),
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionAttachments(msg.Attachments...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	return nil
+}
+
+func (c *slackClient) Reply(ctx context.Context, token string, channelID string, ts string, text string) error {
+	_, _, err := c.api(token).PostMessageContext(ctx, channelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(ts),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reply to message: %w", err)
+	}
+	return nil
+}
+
+func (c *slackClient) UserGroupMembers(ctx context.Context, token string, groupID string) ([]string, error) {
+	members, err := c.api(token).GetUserGroupMembersContext(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members of user group %s: %w", groupID, err)
+	}
+	return members, nil
+}
+
+func (c *slackClient) AuthTest(ctx context.Context, token string) (*slack.AuthTestResponse, error) {
+	resp, err := c.api(token).AuthTestContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("auth.test failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *slackClient) ConversationInfo(ctx context.Context, token string, channel string) (*slack.Channel, error) {
+	api := c.api(token)
+	channelID := channel
+	if !IsConversationID(channel) {
+		id, err := findChannelID(ctx, api, strings.TrimPrefix(channel, "#"))
 		if err != nil {
-			return fmt.Errorf("failed to post webhook: %w", err)
+			return nil, err
 		}
-		return nil
+		channelID = id
+	}
+
+	info, err := api.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channelID})
+	var slackErr slack.SlackErrorResponse
+	if errors.As(err, &slackErr) && slackErr.Err == "channel_not_found" {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNotFound, channel)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("conversations.info failed: %w", err)
+	}
+	return info, nil
+}
+
+func (c *slackClient) RefreshToken(ctx context.Context, clientID string, clientSecret string, refreshToken string) (*slack.OAuthV2Response, error) {
+	apiURL := c.apiURL
+	if apiURL == "" {
+		apiURL = slack.APIURL
+	}
+	values := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
 	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL+"oauth.v2.access", strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oauth.v2.access request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth.v2.access failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth.v2.access failed: %s", resp.Status)
+	}
+
+	var out slack.OAuthV2Response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode oauth.v2.access response: %w", err)
+	}
+	if err := out.Err(); err != nil {
+		return nil, fmt.Errorf("oauth.v2.access failed: %w", err)
+	}
+	return &out, nil
+}
 
-	return fmt.Errorf("neither token nor webhookURL provided")
+// findChannelID looks up the ID of a channel by name with conversations.list,
+// since conversations.info only accepts IDs.
+func findChannelID(ctx context.Context, api *slack.Client, name string) (string, error) {
+	params := &slack.GetConversationsParameters{Types: []string{"public_channel", "private_channel"}, Limit: 1000}
+	for {
+		channels, cursor, err := api.GetConversationsContext(ctx, params)
+		if err != nil {
+			return "", fmt.Errorf("conversations.list failed: %w", err)
+		}
+		for _, ch := range channels {
+			if ch.Name == name {
+				return ch.ID, nil
+			}
+		}
+		if cursor == "" {
+			return "", fmt.Errorf("%w: #%s", ErrChannelNotFound, name)
+		}
+		params.Cursor = cursor
+	}
 }
@@ -0,0 +1,147 @@
+// Package tracing traces the reconciles of Jobs and Workflows and the
+// notifications they send with OpenTelemetry. Spans are exported over OTLP
+// once Setup is called, and carry the trace ID into the logs written while
+// they are active.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// TracerName is the name of the tracer spans are started with.
+const TracerName = "github.com/murasame29/slack-notifier-controller"
+
+// DefaultServiceName is the service name of the traces without one.
+const DefaultServiceName = "slack-notifier-controller"
+
+// Attributes of the spans.
+const (
+	// TriggerKey is the namespace and name of the Job or Workflow.
+	TriggerKey = attribute.Key("notification.trigger")
+	// TargetKey is the namespace and name of the CronJob or CronWorkflow.
+	TargetKey = attribute.Key("notification.target")
+	// TargetKindKey is CronJob or CronWorkflow.
+	TargetKindKey = attribute.Key("notification.target.kind")
+	// StatusKey is the status notifications are sent for.
+	StatusKey = attribute.Key("notification.status")
+	// RuleKey is the namespace and name of a SlackNotificationRule, or the
+	// name of a ClusterSlackNotificationRule.
+	RuleKey = attribute.Key("notification.rule")
+	// SlackConfigKey is the kind, namespace and name of a Slack configuration.
+	SlackConfigKey = attribute.Key("notification.slack_config")
+	// ChannelKey is the channel a notification is posted to.
+	ChannelKey = attribute.Key("notification.channel")
+	// SinkKey is the name of the SinkConfig a notification is delivered to.
+	SinkKey = attribute.Key("notification.sink")
+	// MatchedRulesKey is the number of rules selecting a CronJob or CronWorkflow.
+	MatchedRulesKey = attribute.Key("notification.rules.matched")
+	// CredentialProviderKey is the provider a credential is read with.
+	CredentialProviderKey = attribute.Key("credential.provider")
+	// CredentialKey is the namespace, name and key a credential is read from.
+	CredentialKey = attribute.Key("credential.ref")
+	// CredentialCachedKey is set if a credential was read from the cache.
+	CredentialCachedKey = attribute.Key("credential.cached")
+)
+
+// Options configures the export of traces.
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver, e.g. a collector.
+	Endpoint string
+	// Insecure disables TLS to the endpoint.
+	Insecure bool
+	// SampleRatio is the fraction of traces sampled, from 0 to 1. Spans of a
+	// sampled parent are always sampled.
+	SampleRatio float64
+	// ServiceName defaults to DefaultServiceName.
+	ServiceName string
+}
+
+// Setup exports the spans of the global tracer provider over OTLP and
+// propagates trace context with W3C headers. The returned function flushes
+// the spans not exported yet and stops exporting.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx. A span
+// starting a trace adds its trace ID to the logger of the returned context
+// as traceID.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	parent := trace.SpanContextFromContext(ctx)
+	ctx, span := otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	if sc := span.SpanContext(); sc.IsValid() && sc.TraceID() != parent.TraceID() {
+		ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("traceID", sc.TraceID().String()))
+	}
+	return ctx, span
+}
+
+// End marks span as failed if err is set and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InstrumentTransport returns a RoundTripper tracing every request next
+// sends in a span named by name. The span records the method, host and
+// response status of the request but not its URL, as the URLs of incoming
+// webhooks contain their secret.
+func InstrumentTransport(next http.RoundTripper, name func(*http.Request) string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, span := Start(req.Context(), name(req),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+		)
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err == nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, resp.Status)
+			}
+		}
+		End(span, err)
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		ctx      context.Context
+		recorder *tracetest.SpanRecorder
+		logs     []string
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(func() { otel.SetTracerProvider(previous) })
+
+		logs = nil
+		ctx = log.IntoContext(context.Background(), funcr.New(func(_, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{}))
+	})
+
+	It("adds the trace ID to the logs of a trace once", func() {
+		ctx, span := Start(ctx, "Notify", StatusKey.String("Failed"))
+		_, child := Start(ctx, "NotifyRule")
+		log.FromContext(ctx).Info("sending")
+		child.End()
+		End(span, nil)
+
+		traceID := span.SpanContext().TraceID().String()
+		Expect(logs).To(ConsistOf(ContainSubstring(`"traceID"="` + traceID + `"`)))
+		Expect(strings.Count(logs[0], "traceID")).To(Equal(1))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("NotifyRule"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(span.SpanContext().SpanID()))
+		Expect(spans[1].Attributes()).To(ContainElement(StatusKey.String("Failed")))
+	})
+
+	It("does not log a trace ID without a tracer provider", func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		ctx, span := Start(ctx, "Notify")
+		log.FromContext(ctx).Info("sending")
+		End(span, nil)
+		Expect(logs).To(ConsistOf(Not(ContainSubstring("traceID"))))
+	})
+
+	It("marks spans ended with an error as failed", func() {
+		_, span := Start(ctx, "SendSlack")
+		End(span, errors.New("channel_not_found"))
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[0].Status().Description).To(Equal("channel_not_found"))
+		Expect(spans[0].Events()).To(ContainElement(HaveField("Name", "exception")))
+	})
+
+	It("traces HTTP requests without their URL", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		DeferCleanup(server.Close)
+
+		client := &http.Client{Transport: InstrumentTransport(nil, func(*http.Request) string { return "Slack webhook" })}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/services/T000/B000/secret", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("Slack webhook"))
+		Expect(spans[0].Attributes()).To(ContainElements(
+			attribute.String("http.request.method", http.MethodPost),
+			attribute.Int("http.response.status_code", http.StatusNotFound),
+		))
+		for _, attr := range spans[0].Attributes() {
+			Expect(attr.Value.Emit()).NotTo(ContainSubstring("secret"))
+		}
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+	})
+})
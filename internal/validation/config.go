@@ -0,0 +1,120 @@
+package validation
+
+import (
+	pathpkg "path"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/credentials"
+)
+
+// ValidateConfigSpec returns the problems of a SlackConfig spec: secret refs
+// that do not match AuthType or do not name a file or environment variable for
+// the CredentialProvider, settings token authentication needs and invalid
+// channel policies.
+func ValidateConfigSpec(spec *notificationv1alpha1.SlackConfigSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch spec.AuthType {
+	case "Webhook":
+		if spec.WebhookURLSecretRef == nil {
+			errs = append(errs, field.Required(path.Child("webhookUrlSecretRef"), "required when authType is Webhook"))
+		}
+		if spec.TokenSecretRef != nil {
+			errs = append(errs, field.Forbidden(path.Child("tokenSecretRef"), "not allowed when authType is Webhook"))
+		}
+		if spec.Interactivity != nil {
+			errs = append(errs, field.Forbidden(path.Child("interactivity"), "requires authType Token"))
+		}
+	case "Token":
+		if spec.TokenSecretRef == nil {
+			errs = append(errs, field.Required(path.Child("tokenSecretRef"), "required when authType is Token"))
+		}
+		if spec.WebhookURLSecretRef != nil {
+			errs = append(errs, field.Forbidden(path.Child("webhookUrlSecretRef"), "not allowed when authType is Token"))
+		}
+		if spec.Channel == "" {
+			errs = append(errs, field.Required(path.Child("channel"), "required when authType is Token"))
+		}
+	}
+	if spec.TokenRotation != nil {
+		errs = append(errs, validateTokenRotation(spec, path)...)
+	}
+	errs = append(errs, validateCredentialRefs(spec, path)...)
+	for i := range spec.ChannelPolicies {
+		errs = append(errs, ValidateChannelPolicy(&spec.ChannelPolicies[i], path.Child("channelPolicies").Index(i))...)
+	}
+	return errs
+}
+
+// validateTokenRotation checks that token rotation is used with a token stored in a Secret.
+func validateTokenRotation(spec *notificationv1alpha1.SlackConfigSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	rotationPath := path.Child("tokenRotation")
+	if spec.AuthType != "Token" {
+		errs = append(errs, field.Forbidden(rotationPath, "requires authType Token"))
+	}
+	if spec.CredentialProvider != "" && spec.CredentialProvider != credentials.ProviderSecret {
+		errs = append(errs, field.Forbidden(rotationPath, "requires credentialProvider Secret"))
+	}
+	if spec.TokenSecretRef != nil && spec.TokenSecretRef.Key == notificationv1alpha1.RotatedRefreshTokenKey {
+		errs = append(errs, field.Invalid(path.Child("tokenSecretRef", "key"), spec.TokenSecretRef.Key, "is reserved for the rotated refresh token"))
+	}
+	if spec.TokenRotation.ClientID == "" {
+		errs = append(errs, field.Required(rotationPath.Child("clientId"), ""))
+	}
+	return errs
+}
+
+// validateCredentialRefs checks that the credential refs of a spec name a file
+// or an environment variable when CredentialProvider reads them from one.
+func validateCredentialRefs(spec *notificationv1alpha1.SlackConfigSpec, path *field.Path) field.ErrorList {
+	type credentialRef struct {
+		path *field.Path
+		ref  *corev1.SecretKeySelector
+	}
+	refs := []credentialRef{
+		{path.Child("webhookUrlSecretRef"), spec.WebhookURLSecretRef},
+		{path.Child("tokenSecretRef"), spec.TokenSecretRef},
+	}
+	if spec.Interactivity != nil {
+		refs = append(refs,
+			credentialRef{path.Child("interactivity", "signingSecretRef"), spec.Interactivity.SigningSecretRef},
+			credentialRef{path.Child("interactivity", "appTokenSecretRef"), spec.Interactivity.AppTokenSecretRef})
+	}
+	var errs field.ErrorList
+	for _, r := range refs {
+		if r.ref == nil {
+			continue
+		}
+		var msgs []string
+		switch spec.CredentialProvider {
+		case credentials.ProviderFile:
+			msgs = append(utilvalidation.IsDNS1123Subdomain(r.ref.Name), utilvalidation.IsConfigMapKey(r.ref.Key)...)
+		case credentials.ProviderEnv:
+			msgs = utilvalidation.IsEnvVarName(r.ref.Key)
+		}
+		for _, msg := range msgs {
+			errs = append(errs, field.Invalid(r.path, r.ref.Name+"/"+r.ref.Key, msg))
+		}
+	}
+	return errs
+}
+
+// ValidateChannelPolicy returns the problems of a ChannelPolicy: an invalid
+// namespace selector or channel patterns that do not parse.
+func ValidateChannelPolicy(policy *notificationv1alpha1.ChannelPolicy, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if policy.NamespaceSelector != nil {
+		errs = metav1validation.ValidateLabelSelector(policy.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))
+	}
+	for i, channel := range policy.AllowedChannels {
+		if _, err := pathpkg.Match(channel, ""); err != nil {
+			errs = append(errs, field.Invalid(path.Child("allowedChannels").Index(i), channel, "invalid pattern"))
+		}
+	}
+	return errs
+}
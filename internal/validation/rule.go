@@ -0,0 +1,166 @@
+// Package validation checks the parts of the notification resources the CRD
+// schema cannot, such as label selectors, statuses and templates.
+package validation
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/slack"
+)
+
+// Statuses are the statuses notifications can be sent for, by target resource.
+var Statuses = map[string][]string{
+	"CronJob":      {"Running", "Succeeded", "Failed"},
+	"CronWorkflow": {"Pending", "Running", "Succeeded", "Failed", "Error"},
+}
+
+// IsValidStatus reports whether notifications can be sent for status of
+// targetResource. Statuses are matched case-insensitively, like the notifier does.
+func IsValidStatus(targetResource, status string) bool {
+	return slices.ContainsFunc(Statuses[targetResource], func(s string) bool {
+		return strings.EqualFold(s, status)
+	})
+}
+
+// ValidateRuleSpec returns the problems of a SlackNotificationRule spec.
+func ValidateRuleSpec(spec *notificationv1alpha1.SlackNotificationRuleSpec, path *field.Path) field.ErrorList {
+	errs := metav1validation.ValidateLabelSelector(&spec.LabelSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("labelSelector"))
+	errs = append(errs, validateSlackConfigRef(spec.SlackConfigRef, path.Child("slackConfigRef"))...)
+
+	for i, note := range spec.Notifications {
+		notePath := path.Child("notifications").Index(i)
+		if _, ok := Statuses[spec.TargetResource]; ok && !IsValidStatus(spec.TargetResource, note.Status) {
+			errs = append(errs, field.NotSupported(notePath.Child("status"), note.Status, Statuses[spec.TargetResource]))
+		}
+		if _, err := slack.ParseTitle(note.Title); err != nil {
+			errs = append(errs, field.Invalid(notePath.Child("title"), note.Title, err.Error()))
+		}
+		errs = append(errs, validateQuietHours(note.QuietHours, notePath.Child("quietHours"))...)
+		if note.Escalation != nil && note.Escalation.After.Duration <= 0 {
+			errs = append(errs, field.Invalid(notePath.Child("escalation", "after"), note.Escalation.After.Duration.String(), "must be positive"))
+		}
+		errs = append(errs, validateDestinations(&note, notePath)...)
+	}
+	if spec.History != nil && spec.History.MaxAge != nil && spec.History.MaxAge.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("history", "maxAge"), spec.History.MaxAge.Duration.String(), "must be positive"))
+	}
+	return errs
+}
+
+// validateDestinations checks that a notification with destinations does not
+// also choose a channel itself, that sinks are not combined with Slack
+// settings and that no destination is listed twice.
+func validateDestinations(note *notificationv1alpha1.NotificationRule, path *field.Path) field.ErrorList {
+	if len(note.Destinations) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	if note.Channel != "" {
+		errs = append(errs, field.Forbidden(path.Child("channel"), "not allowed with destinations"))
+	}
+	if note.Escalation != nil {
+		errs = append(errs, field.Forbidden(path.Child("escalation"), "not supported with destinations"))
+	}
+	if note.QuietHours != nil && note.QuietHours.Action == "Reroute" {
+		errs = append(errs, field.Forbidden(path.Child("quietHours", "action"), "Reroute is not supported with destinations"))
+	}
+	// key identifies a destination.
+	type key struct {
+		ref     notificationv1alpha1.SlackConfigReference
+		channel string
+		sink    string
+	}
+	seen := map[key]bool{}
+	for i, dest := range note.Destinations {
+		destPath := path.Child("destinations").Index(i)
+		k := key{channel: dest.Channel}
+		if dest.SinkRef != nil {
+			if dest.SlackConfigRef != nil {
+				errs = append(errs, field.Forbidden(destPath.Child("slackConfigRef"), "not allowed with sinkRef"))
+			}
+			if dest.Channel != "" {
+				errs = append(errs, field.Forbidden(destPath.Child("channel"), "not allowed with sinkRef"))
+			}
+			if dest.SinkRef.Name == "" {
+				errs = append(errs, field.Required(destPath.Child("sinkRef", "name"), ""))
+			}
+			k.sink = dest.SinkRef.Name
+		}
+		if dest.SlackConfigRef != nil {
+			errs = append(errs, validateSlackConfigRef(*dest.SlackConfigRef, destPath.Child("slackConfigRef"))...)
+			k.ref = *dest.SlackConfigRef
+		}
+		if seen[k] {
+			errs = append(errs, field.Duplicate(destPath, dest))
+		}
+		seen[k] = true
+	}
+	return errs
+}
+
+func validateSlackConfigRef(ref notificationv1alpha1.SlackConfigReference, path *field.Path) field.ErrorList {
+	if ref.Namespace == "" {
+		return nil
+	}
+	if ref.Kind == notificationv1alpha1.KindClusterSlackConfig {
+		return field.ErrorList{field.Forbidden(path.Child("namespace"), "not allowed for a ClusterSlackConfig")}
+	}
+	var errs field.ErrorList
+	for _, msg := range utilvalidation.IsDNS1123Label(ref.Namespace) {
+		errs = append(errs, field.Invalid(path.Child("namespace"), ref.Namespace, msg))
+	}
+	return errs
+}
+
+func validateQuietHours(qh *notificationv1alpha1.QuietHours, path *field.Path) field.ErrorList {
+	if qh == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if qh.TimeZone != "" {
+		if _, err := time.LoadLocation(qh.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(path.Child("timeZone"), qh.TimeZone, "unknown time zone"))
+		}
+	}
+	if qh.Action == "Reroute" && qh.Channel == "" {
+		errs = append(errs, field.Required(path.Child("channel"), "required when action is Reroute"))
+	}
+	return errs
+}
+
+// ValidateClusterRuleSpec returns the problems of a ClusterSlackNotificationRule spec.
+func ValidateClusterRuleSpec(spec *notificationv1alpha1.ClusterSlackNotificationRuleSpec, path *field.Path) field.ErrorList {
+	errs := metav1validation.ValidateLabelSelector(&spec.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))
+	errs = append(errs, validateClusterSlackConfigRef(spec.SlackConfigRef, path.Child("slackConfigRef"))...)
+	for i, note := range spec.Notifications {
+		for j, dest := range note.Destinations {
+			destPath := path.Child("notifications").Index(i).Child("destinations").Index(j)
+			if dest.SlackConfigRef != nil {
+				errs = append(errs, validateClusterSlackConfigRef(*dest.SlackConfigRef, destPath.Child("slackConfigRef"))...)
+			}
+			if dest.SinkRef != nil {
+				errs = append(errs, field.Forbidden(destPath.Child("sinkRef"), "not available to ClusterSlackNotificationRules"))
+			}
+		}
+	}
+	return append(errs, ValidateRuleSpec(&spec.SlackNotificationRuleSpec, path)...)
+}
+
+// validateClusterSlackConfigRef checks that a reference of a cluster rule names a ClusterSlackConfig.
+func validateClusterSlackConfigRef(ref notificationv1alpha1.SlackConfigReference, path *field.Path) field.ErrorList {
+	if ref.Kind != "" && ref.Kind != notificationv1alpha1.KindClusterSlackConfig {
+		return field.ErrorList{field.NotSupported(path.Child("kind"), ref.Kind, []string{notificationv1alpha1.KindClusterSlackConfig})}
+	}
+	if ref.Kind == "" && ref.Namespace != "" {
+		// An explicit ClusterSlackConfig kind is checked by ValidateRuleSpec.
+		return field.ErrorList{field.Forbidden(path.Child("namespace"), "not allowed for a ClusterSlackConfig")}
+	}
+	return nil
+}
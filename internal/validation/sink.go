@@ -0,0 +1,167 @@
+package validation
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/sink"
+)
+
+// ValidateSinkConfigSpec returns the problems of a SinkConfig spec: settings
+// for another type of sink, a missing URL or incident key, an invalid API
+// URL or email address and a body template that does not parse.
+func ValidateSinkConfigSpec(spec *notificationv1alpha1.SinkConfigSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	incident := spec.Type == sink.TypePagerDuty || spec.Type == sink.TypeOpsgenie
+	noURL := incident || spec.Type == sink.TypeEmail
+	switch {
+	case noURL && spec.URLSecretRef != nil:
+		errs = append(errs, field.Forbidden(path.Child("urlSecretRef"), "not allowed when type is "+spec.Type))
+	case !noURL && spec.URLSecretRef == nil:
+		errs = append(errs, field.Required(path.Child("urlSecretRef"), "required when type is "+spec.Type))
+	case !noURL && spec.URLSecretRef.Name == "":
+		errs = append(errs, field.Required(path.Child("urlSecretRef", "name"), ""))
+	}
+	errs = append(errs, validateIncidentSink(spec, incident, path.Child("incident"))...)
+	errs = append(errs, validateEmailSink(spec, path.Child("email"))...)
+	errs = append(errs, validateAlertmanagerSink(spec, path.Child("alertmanager"))...)
+	if spec.Webhook == nil {
+		return errs
+	}
+	webhookPath := path.Child("webhook")
+	if spec.Type != sink.TypeWebhook {
+		return append(errs, field.Forbidden(webhookPath, "only allowed when type is Webhook"))
+	}
+	if spec.Webhook.BodyTemplate != "" {
+		if _, err := sink.ParseBodyTemplate(spec.Webhook.BodyTemplate); err != nil {
+			errs = append(errs, field.Invalid(webhookPath.Child("bodyTemplate"), spec.Webhook.BodyTemplate, err.Error()))
+		}
+	}
+	for name := range spec.Webhook.Headers {
+		if !validHeaderName(name) {
+			errs = append(errs, field.Invalid(webhookPath.Child("headers").Key(name), name, "invalid header name"))
+		}
+	}
+	return errs
+}
+
+// validateIncidentSink checks that the incident settings are set exactly for
+// PagerDuty and Opsgenie and that the API URL is an http or https URL.
+func validateIncidentSink(spec *notificationv1alpha1.SinkConfigSpec, incident bool, path *field.Path) field.ErrorList {
+	switch {
+	case !incident && spec.Incident != nil:
+		return field.ErrorList{field.Forbidden(path, "only allowed when type is PagerDuty or Opsgenie")}
+	case incident && spec.Incident == nil:
+		return field.ErrorList{field.Required(path, "required when type is "+spec.Type)}
+	case !incident:
+		return nil
+	}
+	var errs field.ErrorList
+	if spec.Incident.KeySecretRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("keySecretRef", "name"), ""))
+	}
+	if apiURL := spec.Incident.APIURL; apiURL != "" {
+		if u, err := url.Parse(apiURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("apiUrl"), apiURL, "must be an http or https URL"))
+		}
+	}
+	return errs
+}
+
+// validateEmailSink checks that the email settings are set exactly for Email,
+// that the sender and recipients are email addresses and that a password
+// comes with a username.
+func validateEmailSink(spec *notificationv1alpha1.SinkConfigSpec, path *field.Path) field.ErrorList {
+	email := spec.Email
+	switch {
+	case spec.Type != sink.TypeEmail && email != nil:
+		return field.ErrorList{field.Forbidden(path, "only allowed when type is Email")}
+	case spec.Type == sink.TypeEmail && email == nil:
+		return field.ErrorList{field.Required(path, "required when type is Email")}
+	case email == nil:
+		return nil
+	}
+	var errs field.ErrorList
+	if email.Host == "" {
+		errs = append(errs, field.Required(path.Child("host"), ""))
+	}
+	if email.Port < 0 || email.Port > 65535 {
+		errs = append(errs, field.Invalid(path.Child("port"), email.Port, "must be between 1 and 65535"))
+	}
+	switch email.TLS {
+	case "", sink.TLSNone, sink.TLSStartTLS, sink.TLSImplicit:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("tls"), email.TLS, []string{sink.TLSNone, sink.TLSStartTLS, sink.TLSImplicit}))
+	}
+	if email.PasswordSecretRef != nil {
+		if email.Username == "" {
+			errs = append(errs, field.Required(path.Child("username"), "required with passwordSecretRef"))
+		}
+		if email.PasswordSecretRef.Name == "" {
+			errs = append(errs, field.Required(path.Child("passwordSecretRef", "name"), ""))
+		}
+	}
+	if _, err := mail.ParseAddress(email.From); err != nil {
+		errs = append(errs, field.Invalid(path.Child("from"), email.From, err.Error()))
+	}
+	if len(email.To) == 0 {
+		errs = append(errs, field.Required(path.Child("to"), ""))
+	}
+	for i, to := range email.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			errs = append(errs, field.Invalid(path.Child("to").Index(i), to, err.Error()))
+		}
+	}
+	return errs
+}
+
+// alertLabelName matches the names of Prometheus labels.
+var alertLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// alertIdentityLabels are the labels an Alertmanager sink derives from a notification.
+var alertIdentityLabels = []string{"rule", "namespace", "target", "status"}
+
+// validateAlertmanagerSink checks that the Alertmanager settings are only set
+// for Alertmanager, that its labels are valid and do not replace the labels
+// identifying an alert, and that the expiry is positive.
+func validateAlertmanagerSink(spec *notificationv1alpha1.SinkConfigSpec, path *field.Path) field.ErrorList {
+	am := spec.Alertmanager
+	if am == nil {
+		return nil
+	}
+	if spec.Type != sink.TypeAlertmanager {
+		return field.ErrorList{field.Forbidden(path, "only allowed when type is Alertmanager")}
+	}
+	var errs field.ErrorList
+	for name := range am.Labels {
+		switch {
+		case !alertLabelName.MatchString(name) || strings.HasPrefix(name, "__"):
+			errs = append(errs, field.Invalid(path.Child("labels").Key(name), name, "must be a Prometheus label name"))
+		case slices.Contains(alertIdentityLabels, name):
+			errs = append(errs, field.Forbidden(path.Child("labels").Key(name), "set from the notification"))
+		}
+	}
+	if am.Expiry != nil && am.Expiry.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("expiry"), am.Expiry.Duration.String(), "must be positive"))
+	}
+	return errs
+}
+
+// validHeaderName reports whether name is an HTTP header field name (RFC 9110 token).
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var clusterslackconfiglog = logf.Log.WithName("clusterslackconfig-resource")
+
+// SetupClusterSlackConfigWebhookWithManager registers the webhook for ClusterSlackConfig in the manager.
+func SetupClusterSlackConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.ClusterSlackConfig{}).
+		WithValidator(&ClusterSlackConfigCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-clusterslackconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=clusterslackconfigs,verbs=create;update,versions=v1alpha1,name=vclusterslackconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterSlackConfigCustomValidator rejects ClusterSlackConfigs with the
+// problems SlackConfigCustomValidator rejects in SlackConfigs.
+type ClusterSlackConfigCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterSlackConfigCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackConfig.
+func (v *ClusterSlackConfigCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterslackconfig, ok := obj.(*notificationv1alpha1.ClusterSlackConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSlackConfig object but got %T", obj)
+	}
+	clusterslackconfiglog.Info("Validation for ClusterSlackConfig upon creation", "name", clusterslackconfig.GetName())
+
+	return nil, validateClusterSlackConfig(clusterslackconfig)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackConfig.
+func (v *ClusterSlackConfigCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	clusterslackconfig, ok := newObj.(*notificationv1alpha1.ClusterSlackConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSlackConfig object for the newObj but got %T", newObj)
+	}
+	clusterslackconfiglog.Info("Validation for ClusterSlackConfig upon update", "name", clusterslackconfig.GetName())
+
+	return nil, validateClusterSlackConfig(clusterslackconfig)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackConfig.
+func (v *ClusterSlackConfigCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateClusterSlackConfig(clusterslackconfig *notificationv1alpha1.ClusterSlackConfig) error {
+	errs := validation.ValidateConfigSpec(&clusterslackconfig.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("ClusterSlackConfig").GroupKind(), clusterslackconfig.Name, errs)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("ClusterSlackConfig Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.ClusterSlackConfig
+		validator ClusterSlackConfigCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.ClusterSlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec: notificationv1alpha1.SlackConfigSpec{
+				AuthType:            "Webhook",
+				WebhookURLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack-webhook"}, Key: "url"},
+			},
+		}
+		validator = ClusterSlackConfigCustomValidator{}
+	})
+
+	Context("When creating or updating ClusterSlackConfig under Validating Webhook", func() {
+		It("Should admit a Webhook config", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny a Webhook config without a webhook URL", func() {
+			obj.Spec.WebhookURLSecretRef = nil
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.webhookUrlSecretRef"))
+		})
+	})
+})
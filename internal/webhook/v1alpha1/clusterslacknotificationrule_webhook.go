@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var clusterslacknotificationrulelog = logf.Log.WithName("clusterslacknotificationrule-resource")
+
+// SetupClusterSlackNotificationRuleWebhookWithManager registers the webhook for ClusterSlackNotificationRule in the manager.
+func SetupClusterSlackNotificationRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.ClusterSlackNotificationRule{}).
+		WithValidator(&ClusterSlackNotificationRuleCustomValidator{}).
+		WithDefaulter(&ClusterSlackNotificationRuleCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-notification-murasame29-com-v1alpha1-clusterslacknotificationrule,mutating=true,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=clusterslacknotificationrules,verbs=create;update,versions=v1alpha1,name=mclusterslacknotificationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterSlackNotificationRuleCustomDefaulter fills in the notifications of
+// ClusterSlackNotificationRules like SlackNotificationRuleCustomDefaulter does.
+type ClusterSlackNotificationRuleCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ClusterSlackNotificationRuleCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind ClusterSlackNotificationRule.
+func (d *ClusterSlackNotificationRuleCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	clusterslacknotificationrule, ok := obj.(*notificationv1alpha1.ClusterSlackNotificationRule)
+	if !ok {
+		return fmt.Errorf("expected a ClusterSlackNotificationRule object but got %T", obj)
+	}
+	clusterslacknotificationrulelog.Info("Defaulting for ClusterSlackNotificationRule", "name", clusterslacknotificationrule.GetName())
+
+	defaultRuleSpec(&clusterslacknotificationrule.Spec.SlackNotificationRuleSpec)
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-clusterslacknotificationrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=clusterslacknotificationrules,verbs=create;update,versions=v1alpha1,name=vclusterslacknotificationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterSlackNotificationRuleCustomValidator rejects ClusterSlackNotificationRules
+// with an invalid namespace selector or the problems
+// SlackNotificationRuleCustomValidator rejects in SlackNotificationRules.
+type ClusterSlackNotificationRuleCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterSlackNotificationRuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackNotificationRule.
+func (v *ClusterSlackNotificationRuleCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterslacknotificationrule, ok := obj.(*notificationv1alpha1.ClusterSlackNotificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSlackNotificationRule object but got %T", obj)
+	}
+	clusterslacknotificationrulelog.Info("Validation for ClusterSlackNotificationRule upon creation", "name", clusterslacknotificationrule.GetName())
+
+	return nil, validateClusterSlackNotificationRule(clusterslacknotificationrule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackNotificationRule.
+func (v *ClusterSlackNotificationRuleCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	clusterslacknotificationrule, ok := newObj.(*notificationv1alpha1.ClusterSlackNotificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSlackNotificationRule object for the newObj but got %T", newObj)
+	}
+	clusterslacknotificationrulelog.Info("Validation for ClusterSlackNotificationRule upon update", "name", clusterslacknotificationrule.GetName())
+
+	return nil, validateClusterSlackNotificationRule(clusterslacknotificationrule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterSlackNotificationRule.
+func (v *ClusterSlackNotificationRuleCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateClusterSlackNotificationRule(clusterslacknotificationrule *notificationv1alpha1.ClusterSlackNotificationRule) error {
+	errs := validation.ValidateClusterRuleSpec(&clusterslacknotificationrule.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("ClusterSlackNotificationRule").GroupKind(), clusterslacknotificationrule.Name, errs)
+}
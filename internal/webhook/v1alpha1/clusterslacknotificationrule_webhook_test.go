@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("ClusterSlackNotificationRule Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.ClusterSlackNotificationRule
+		oldObj    *notificationv1alpha1.ClusterSlackNotificationRule
+		validator ClusterSlackNotificationRuleCustomValidator
+		defaulter ClusterSlackNotificationRuleCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.ClusterSlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-failures"},
+			Spec: notificationv1alpha1.ClusterSlackNotificationRuleSpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				SlackNotificationRuleSpec: notificationv1alpha1.SlackNotificationRuleSpec{
+					TargetResource: "CronJob",
+					SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "platform"},
+					Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Failed", Title: "{{ .metadata.name }} failed"}},
+				},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = ClusterSlackNotificationRuleCustomValidator{}
+		defaulter = ClusterSlackNotificationRuleCustomDefaulter{}
+	})
+
+	Context("When creating ClusterSlackNotificationRule under Defaulting Webhook", func() {
+		It("Should fill in a failure notification for a rule without notifications", func() {
+			obj.Spec.Notifications = nil
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Notifications).To(ConsistOf(HaveField("Status", "Failed")))
+			Expect(obj.Spec.Notifications[0].Title).To(Equal(":x: {{ .metadata.namespace }}/{{ .metadata.name }} failed"))
+		})
+	})
+
+	Context("When creating or updating ClusterSlackNotificationRule under Validating Webhook", func() {
+		It("Should admit a valid rule", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny an invalid namespace selector", func() {
+			obj.Spec.NamespaceSelector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Maybe"}}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.namespaceSelector"))
+		})
+
+		It("Should deny a reference to a namespaced SlackConfig", func() {
+			obj.Spec.SlackConfigRef.Kind = notificationv1alpha1.KindSlackConfig
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.slackConfigRef.kind")))
+		})
+
+		It("Should deny a namespace in the reference", func() {
+			obj.Spec.SlackConfigRef.Namespace = "shared-notify"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.slackConfigRef.namespace")))
+		})
+
+		It("Should deny destinations in namespaced SlackConfigs", func() {
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+				{SlackConfigRef: &notificationv1alpha1.SlackConfigReference{Name: "sre"}},
+				{SlackConfigRef: &notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindSlackConfig, Name: "team-a"}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[1].slackConfigRef.kind")))
+			Expect(err).NotTo(MatchError(ContainSubstring("destinations[0]")))
+		})
+
+		It("Should deny sinks", func() {
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{{SinkRef: &corev1.LocalObjectReference{Name: "teams"}}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[0].sinkRef: Forbidden")))
+		})
+
+		It("Should validate the rule like a SlackNotificationRule on update", func() {
+			obj.Spec.Notifications[0].Status = "Pending"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].status")))
+		})
+	})
+})
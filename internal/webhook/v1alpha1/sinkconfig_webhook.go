@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var sinkconfiglog = logf.Log.WithName("sinkconfig-resource")
+
+// SetupSinkConfigWebhookWithManager registers the webhook for SinkConfig in the manager.
+func SetupSinkConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.SinkConfig{}).
+		WithValidator(&SinkConfigCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-sinkconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=sinkconfigs,verbs=create;update,versions=v1alpha1,name=vsinkconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SinkConfigCustomValidator rejects SinkConfigs with webhook settings for
+// another type of sink, a body template that does not parse or invalid header names.
+type SinkConfigCustomValidator struct{}
+
+var _ webhook.CustomValidator = &SinkConfigCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SinkConfig.
+func (v *SinkConfigCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	sinkconfig, ok := obj.(*notificationv1alpha1.SinkConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a SinkConfig object but got %T", obj)
+	}
+	sinkconfiglog.Info("Validation for SinkConfig upon creation", "name", sinkconfig.GetName())
+
+	return nil, validateSinkConfig(sinkconfig)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SinkConfig.
+func (v *SinkConfigCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	sinkconfig, ok := newObj.(*notificationv1alpha1.SinkConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a SinkConfig object for the newObj but got %T", newObj)
+	}
+	sinkconfiglog.Info("Validation for SinkConfig upon update", "name", sinkconfig.GetName())
+
+	return nil, validateSinkConfig(sinkconfig)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SinkConfig.
+func (v *SinkConfigCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateSinkConfig(sinkconfig *notificationv1alpha1.SinkConfig) error {
+	errs := validation.ValidateSinkConfigSpec(&sinkconfig.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("SinkConfig").GroupKind(), sinkconfig.Name, errs)
+}
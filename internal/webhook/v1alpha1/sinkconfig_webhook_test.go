@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("SinkConfig Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.SinkConfig
+		validator SinkConfigCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.SinkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "default"},
+			Spec: notificationv1alpha1.SinkConfigSpec{
+				Type: "Webhook",
+				URLSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "alerts-webhook"},
+					Key:                  "url",
+				},
+				Webhook: &notificationv1alpha1.WebhookSink{
+					BodyTemplate: `{"text": {{ json .Title }}}`,
+					Headers:      map[string]string{"X-Team": "a"},
+				},
+			},
+		}
+		validator = SinkConfigCustomValidator{}
+	})
+
+	Context("When creating or updating SinkConfig under Validating Webhook", func() {
+		It("Should admit a valid webhook sink", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny webhook settings for other types of sinks", func() {
+			obj.Spec.Type = "Teams"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.webhook: Forbidden"))
+		})
+
+		It("Should admit a PagerDuty sink with an incident key", func() {
+			obj.Spec = notificationv1alpha1.SinkConfigSpec{
+				Type: "PagerDuty",
+				Incident: &notificationv1alpha1.IncidentSink{
+					KeySecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pagerduty"}, Key: "routing-key"},
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should require incident settings exactly for PagerDuty and Opsgenie", func() {
+			obj.Spec.Type = "Opsgenie"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.urlSecretRef: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.incident: Required value")))
+
+			obj.Spec.Type = "Webhook"
+			obj.Spec.Incident = &notificationv1alpha1.IncidentSink{APIURL: "ftp://opsgenie"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.incident: Forbidden")))
+
+			obj.Spec.Type = "Opsgenie"
+			obj.Spec.URLSecretRef = nil
+			obj.Spec.Webhook = nil
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.incident.keySecretRef.name: Required value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.incident.apiUrl: Invalid value")))
+		})
+
+		It("Should admit an email sink", func() {
+			obj.Spec = notificationv1alpha1.SinkConfigSpec{
+				Type: "Email",
+				Email: &notificationv1alpha1.EmailSink{
+					Host:              "smtp.example.com",
+					Username:          "notifier",
+					PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "smtp"}, Key: "password"},
+					From:              "Notifier <notifier@example.com>",
+					To:                []string{"oncall@example.com"},
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny invalid email settings", func() {
+			obj.Spec.Type = "Email"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.urlSecretRef: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.email: Required value")))
+
+			obj.Spec = notificationv1alpha1.SinkConfigSpec{
+				Type: "Email",
+				Email: &notificationv1alpha1.EmailSink{
+					Host:              "smtp.example.com",
+					PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "smtp"}, Key: "password"},
+					From:              "notifier",
+					To:                []string{"oncall@example.com", "on call"},
+				},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.email.username: Required value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.email.from: Invalid value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.email.to[1]: Invalid value")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.email.to[0]")))
+		})
+
+		It("Should validate Alertmanager labels", func() {
+			obj.Spec.Type = "Alertmanager"
+			obj.Spec.Webhook = nil
+			obj.Spec.Alertmanager = &notificationv1alpha1.AlertmanagerSink{Labels: map[string]string{"severity": "page", "alertname": "BackupFailed"}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Alertmanager.Labels = map[string]string{"team-name": "a", "status": "ok", "__meta": "x"}
+			obj.Spec.Alertmanager.Expiry = &metav1.Duration{}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.alertmanager.labels[team-name]: Invalid value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.alertmanager.labels[__meta]: Invalid value")))
+			Expect(err).To(MatchError(ContainSubstring("spec.alertmanager.labels[status]: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.alertmanager.expiry: Invalid value")))
+
+			obj.Spec.Type = "Webhook"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.alertmanager: Forbidden")))
+		})
+
+		It("Should deny body templates that do not parse and invalid headers", func() {
+			obj.Spec.Webhook.BodyTemplate = "{{ .Title"
+			obj.Spec.Webhook.Headers["X Team"] = "b"
+			_, err := validator.ValidateUpdate(ctx, obj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.webhook.bodyTemplate")))
+			Expect(err).To(MatchError(ContainSubstring("spec.webhook.headers[X Team]")))
+		})
+	})
+})
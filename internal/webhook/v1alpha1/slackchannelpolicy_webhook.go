@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var slackchannelpolicylog = logf.Log.WithName("slackchannelpolicy-resource")
+
+// SetupSlackChannelPolicyWebhookWithManager registers the webhook for SlackChannelPolicy in the manager.
+func SetupSlackChannelPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.SlackChannelPolicy{}).
+		WithValidator(&SlackChannelPolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-slackchannelpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=slackchannelpolicies,verbs=create;update,versions=v1alpha1,name=vslackchannelpolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SlackChannelPolicyCustomValidator rejects SlackChannelPolicies with an
+// invalid namespace selector or channel patterns that do not parse.
+type SlackChannelPolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &SlackChannelPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SlackChannelPolicy.
+func (v *SlackChannelPolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	slackchannelpolicy, ok := obj.(*notificationv1alpha1.SlackChannelPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackChannelPolicy object but got %T", obj)
+	}
+	slackchannelpolicylog.Info("Validation for SlackChannelPolicy upon creation", "name", slackchannelpolicy.GetName())
+
+	return nil, validateSlackChannelPolicy(slackchannelpolicy)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SlackChannelPolicy.
+func (v *SlackChannelPolicyCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	slackchannelpolicy, ok := newObj.(*notificationv1alpha1.SlackChannelPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackChannelPolicy object for the newObj but got %T", newObj)
+	}
+	slackchannelpolicylog.Info("Validation for SlackChannelPolicy upon update", "name", slackchannelpolicy.GetName())
+
+	return nil, validateSlackChannelPolicy(slackchannelpolicy)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SlackChannelPolicy.
+func (v *SlackChannelPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateSlackChannelPolicy(slackchannelpolicy *notificationv1alpha1.SlackChannelPolicy) error {
+	errs := validation.ValidateChannelPolicy(&slackchannelpolicy.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("SlackChannelPolicy").GroupKind(), slackchannelpolicy.Name, errs)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("SlackChannelPolicy Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.SlackChannelPolicy
+		validator SlackChannelPolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.SlackChannelPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: notificationv1alpha1.ChannelPolicy{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				AllowedChannels:   []string{"#team-a-*", "C0123ABCD"},
+			},
+		}
+		validator = SlackChannelPolicyCustomValidator{}
+	})
+
+	Context("When creating or updating SlackChannelPolicy under Validating Webhook", func() {
+		It("Should admit a valid policy", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny channel patterns that do not parse", func() {
+			obj.Spec.AllowedChannels = append(obj.Spec.AllowedChannels, "#team-[a")
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.allowedChannels[2]"))
+		})
+
+		It("Should deny invalid namespace selectors", func() {
+			obj.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a b"}}
+			_, err := validator.ValidateUpdate(ctx, obj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.namespaceSelector")))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var slackconfiglog = logf.Log.WithName("slackconfig-resource")
+
+// SetupSlackConfigWebhookWithManager registers the webhook for SlackConfig in the manager.
+func SetupSlackConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.SlackConfig{}).
+		WithValidator(&SlackConfigCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-slackconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=slackconfigs,verbs=create;update,versions=v1alpha1,name=vslackconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SlackConfigCustomValidator rejects SlackConfigs whose secret refs do not
+// match their AuthType, and Token configs without a channel, when they are
+// applied instead of when a notification is sent.
+type SlackConfigCustomValidator struct{}
+
+var _ webhook.CustomValidator = &SlackConfigCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SlackConfig.
+func (v *SlackConfigCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	slackconfig, ok := obj.(*notificationv1alpha1.SlackConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackConfig object but got %T", obj)
+	}
+	slackconfiglog.Info("Validation for SlackConfig upon creation", "name", slackconfig.GetName())
+
+	return nil, validateSlackConfig(slackconfig)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SlackConfig.
+func (v *SlackConfigCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	slackconfig, ok := newObj.(*notificationv1alpha1.SlackConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackConfig object for the newObj but got %T", newObj)
+	}
+	slackconfiglog.Info("Validation for SlackConfig upon update", "name", slackconfig.GetName())
+
+	return nil, validateSlackConfig(slackconfig)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SlackConfig.
+func (v *SlackConfigCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateSlackConfig(slackconfig *notificationv1alpha1.SlackConfig) error {
+	errs := validation.ValidateConfigSpec(&slackconfig.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("SlackConfig").GroupKind(), slackconfig.Name, errs)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("SlackConfig Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.SlackConfig
+		oldObj    *notificationv1alpha1.SlackConfig
+		validator SlackConfigCustomValidator
+	)
+
+	secretRef := func(name string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "value"}
+	}
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.SlackConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "default"},
+			Spec: notificationv1alpha1.SlackConfigSpec{
+				AuthType:       "Token",
+				TokenSecretRef: secretRef("slack-token"),
+				Channel:        "#alerts",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = SlackConfigCustomValidator{}
+	})
+
+	Context("When creating or updating SlackConfig under Validating Webhook", func() {
+		It("Should admit a Token config with a channel", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should admit a Webhook config", func() {
+			obj.Spec = notificationv1alpha1.SlackConfigSpec{AuthType: "Webhook", WebhookURLSecretRef: secretRef("slack-webhook")}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny a Token config without a channel", func() {
+			obj.Spec.Channel = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.channel"))
+		})
+
+		It("Should deny a Token config referencing a webhook URL instead of a token", func() {
+			obj.Spec.TokenSecretRef = nil
+			obj.Spec.WebhookURLSecretRef = secretRef("slack-webhook")
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tokenSecretRef")))
+			Expect(err).To(MatchError(ContainSubstring("spec.webhookUrlSecretRef")))
+		})
+
+		It("Should deny a Webhook config with interactivity", func() {
+			obj.Spec = notificationv1alpha1.SlackConfigSpec{
+				AuthType:            "Webhook",
+				WebhookURLSecretRef: secretRef("slack-webhook"),
+				Interactivity:       &notificationv1alpha1.SlackInteractivity{SigningSecretRef: secretRef("slack")},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.interactivity")))
+		})
+
+		It("Should deny references that do not name an environment variable for the Env provider", func() {
+			obj.Spec.CredentialProvider = "Env"
+			obj.Spec.TokenSecretRef.Key = "slack token"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tokenSecretRef")))
+
+			obj.Spec.TokenSecretRef.Key = "SLACK_TOKEN"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny references that do not name a file for the File provider", func() {
+			obj.Spec.CredentialProvider = "File"
+			obj.Spec.TokenSecretRef.Key = "../token"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tokenSecretRef")))
+		})
+
+		It("Should deny token rotation for tokens that are not stored in a Secret", func() {
+			obj.Spec.TokenRotation = &notificationv1alpha1.SlackTokenRotation{
+				ClientID:              "123.456",
+				ClientSecretRef:       *secretRef("slack-oauth"),
+				RefreshTokenSecretRef: *secretRef("slack-oauth"),
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.CredentialProvider = "Env"
+			obj.Spec.TokenSecretRef.Key = "SLACK_TOKEN"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tokenRotation: Forbidden")))
+		})
+
+		It("Should deny the refresh token key as the key of a rotating token", func() {
+			obj.Spec.TokenRotation = &notificationv1alpha1.SlackTokenRotation{ClientID: "123.456"}
+			obj.Spec.TokenSecretRef.Key = notificationv1alpha1.RotatedRefreshTokenKey
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tokenSecretRef.key")))
+		})
+
+		It("Should validate updates", func() {
+			obj.Spec.Channel = ""
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.channel")))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+	"github.com/murasame29/slack-notifier-controller/internal/policy"
+	"github.com/murasame29/slack-notifier-controller/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var slacknotificationrulelog = logf.Log.WithName("slacknotificationrule-resource")
+
+// defaultStatuses are the statuses notified by a rule without notifications, by target resource.
+var defaultStatuses = map[string][]string{
+	"CronJob":      {"Failed"},
+	"CronWorkflow": {"Failed", "Error"},
+}
+
+// defaultTitleIcons prefix the default titles of notifications.
+var defaultTitleIcons = map[string]string{
+	"pending":   ":hourglass:",
+	"running":   ":arrow_forward:",
+	"succeeded": ":white_check_mark:",
+	"failed":    ":x:",
+	"error":     ":x:",
+}
+
+// SetupSlackNotificationRuleWebhookWithManager registers the webhook for SlackNotificationRule in the manager.
+func SetupSlackNotificationRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&notificationv1alpha1.SlackNotificationRule{}).
+		WithValidator(&SlackNotificationRuleCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&SlackNotificationRuleCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-notification-murasame29-com-v1alpha1-slacknotificationrule,mutating=true,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=slacknotificationrules,verbs=create;update,versions=v1alpha1,name=mslacknotificationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SlackNotificationRuleCustomDefaulter fills in standard notifications:
+// failures of the target resource when a rule has no notifications, and a
+// title naming the resource and status for notifications without one.
+type SlackNotificationRuleCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &SlackNotificationRuleCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind SlackNotificationRule.
+func (d *SlackNotificationRuleCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	slacknotificationrule, ok := obj.(*notificationv1alpha1.SlackNotificationRule)
+	if !ok {
+		return fmt.Errorf("expected a SlackNotificationRule object but got %T", obj)
+	}
+	slacknotificationrulelog.Info("Defaulting for SlackNotificationRule", "name", slacknotificationrule.GetName())
+
+	defaultRuleSpec(&slacknotificationrule.Spec)
+	return nil
+}
+
+// defaultRuleSpec fills in the notifications of a rule spec.
+func defaultRuleSpec(spec *notificationv1alpha1.SlackNotificationRuleSpec) {
+	if len(spec.Notifications) == 0 {
+		for _, status := range defaultStatuses[spec.TargetResource] {
+			spec.Notifications = append(spec.Notifications, notificationv1alpha1.NotificationRule{Status: status})
+		}
+	}
+	for i := range spec.Notifications {
+		note := &spec.Notifications[i]
+		if note.Title == "" {
+			note.Title = defaultTitle(note.Status)
+		}
+	}
+}
+
+// defaultTitle is the title template of a notification for status. It is
+// rendered against the Job or Workflow that triggered the notification.
+func defaultTitle(status string) string {
+	title := "{{ .metadata.namespace }}/{{ .metadata.name }} " + strings.ToLower(status)
+	if icon, ok := defaultTitleIcons[strings.ToLower(status)]; ok {
+		title = icon + " " + title
+	}
+	return title
+}
+
+// +kubebuilder:webhook:path=/validate-notification-murasame29-com-v1alpha1-slacknotificationrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=notification.murasame29.com,resources=slacknotificationrules,verbs=create;update,versions=v1alpha1,name=vslacknotificationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SlackNotificationRuleCustomValidator rejects SlackNotificationRules with an
+// invalid label selector, unknown statuses for their TargetResource or title
+// templates that do not parse, and rules violating the channel policies of
+// their namespace.
+type SlackNotificationRuleCustomValidator struct {
+	// Client reads the channel policies. They are not enforced if it is nil.
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &SlackNotificationRuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SlackNotificationRule.
+func (v *SlackNotificationRuleCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	slacknotificationrule, ok := obj.(*notificationv1alpha1.SlackNotificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackNotificationRule object but got %T", obj)
+	}
+	slacknotificationrulelog.Info("Validation for SlackNotificationRule upon creation", "name", slacknotificationrule.GetName())
+
+	return nil, v.validateSlackNotificationRule(ctx, slacknotificationrule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SlackNotificationRule.
+func (v *SlackNotificationRuleCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	slacknotificationrule, ok := newObj.(*notificationv1alpha1.SlackNotificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a SlackNotificationRule object for the newObj but got %T", newObj)
+	}
+	slacknotificationrulelog.Info("Validation for SlackNotificationRule upon update", "name", slacknotificationrule.GetName())
+
+	return nil, v.validateSlackNotificationRule(ctx, slacknotificationrule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SlackNotificationRule.
+func (v *SlackNotificationRuleCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *SlackNotificationRuleCustomValidator) validateSlackNotificationRule(ctx context.Context, slacknotificationrule *notificationv1alpha1.SlackNotificationRule) error {
+	errs := validation.ValidateRuleSpec(&slacknotificationrule.Spec, field.NewPath("spec"))
+	if len(errs) == 0 && v.Client != nil {
+		p, err := policy.ForRule(ctx, v.Client, slacknotificationrule)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		errs = p.CheckRule(&slacknotificationrule.Spec, field.NewPath("spec"))
+		destErrs, err := policy.CheckDestinations(ctx, v.Client, slacknotificationrule, field.NewPath("spec"))
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		errs = append(errs, destErrs...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(notificationv1alpha1.GroupVersion.WithKind("SlackNotificationRule").GroupKind(), slacknotificationrule.Name, errs)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notificationv1alpha1 "github.com/murasame29/slack-notifier-controller/api/v1alpha1"
+)
+
+var _ = Describe("SlackNotificationRule Webhook", func() {
+	var (
+		obj       *notificationv1alpha1.SlackNotificationRule
+		oldObj    *notificationv1alpha1.SlackNotificationRule
+		validator SlackNotificationRuleCustomValidator
+		defaulter SlackNotificationRuleCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &notificationv1alpha1.SlackNotificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "default"},
+			Spec: notificationv1alpha1.SlackNotificationRuleSpec{
+				TargetResource: "CronWorkflow",
+				LabelSelector:  metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				SlackConfigRef: notificationv1alpha1.SlackConfigReference{Name: "slack"},
+				Notifications:  []notificationv1alpha1.NotificationRule{{Status: "Error", Title: "{{ .metadata.name }} errored"}},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = SlackNotificationRuleCustomValidator{}
+		defaulter = SlackNotificationRuleCustomDefaulter{}
+	})
+
+	Context("When creating SlackNotificationRule under Defaulting Webhook", func() {
+		It("Should fill in failure notifications for a CronWorkflow rule without notifications", func() {
+			obj.Spec.Notifications = nil
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Notifications).To(HaveLen(2))
+			Expect(obj.Spec.Notifications[0].Status).To(Equal("Failed"))
+			Expect(obj.Spec.Notifications[1].Status).To(Equal("Error"))
+			Expect(obj.Spec.Notifications[0].Title).To(Equal(":x: {{ .metadata.namespace }}/{{ .metadata.name }} failed"))
+		})
+
+		It("Should fill in a failure notification for a CronJob rule without notifications", func() {
+			obj.Spec.TargetResource = "CronJob"
+			obj.Spec.Notifications = nil
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Notifications).To(ConsistOf(HaveField("Status", "Failed")))
+		})
+
+		It("Should only fill in missing titles of configured notifications", func() {
+			obj.Spec.Notifications = append(obj.Spec.Notifications, notificationv1alpha1.NotificationRule{Status: "Succeeded"})
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Notifications).To(HaveLen(2))
+			Expect(obj.Spec.Notifications[0].Title).To(Equal("{{ .metadata.name }} errored"))
+			Expect(obj.Spec.Notifications[1].Title).To(Equal(":white_check_mark: {{ .metadata.namespace }}/{{ .metadata.name }} succeeded"))
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+	})
+
+	Context("When creating or updating SlackNotificationRule under Validating Webhook", func() {
+		It("Should admit a valid rule", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny statuses the target resource never reports", func() {
+			obj.Spec.TargetResource = "CronJob"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.notifications[0].status"))
+		})
+
+		It("Should deny title templates that do not parse", func() {
+			obj.Spec.Notifications[0].Title = "{{ .metadata.name"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].title")))
+		})
+
+		It("Should deny invalid label selectors", func() {
+			obj.Spec.LabelSelector = metav1.LabelSelector{MatchLabels: map[string]string{"team": "a b"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.labelSelector")))
+		})
+
+		It("Should admit a SlackConfig in another namespace", func() {
+			obj.Spec.SlackConfigRef.Namespace = "shared-notify"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny a namespace for a ClusterSlackConfig", func() {
+			obj.Spec.SlackConfigRef = notificationv1alpha1.SlackConfigReference{
+				Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "platform", Namespace: "shared-notify",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.slackConfigRef.namespace")))
+		})
+
+		It("Should admit destinations in other configurations", func() {
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+				{Channel: "#team-a"},
+				{SlackConfigRef: &notificationv1alpha1.SlackConfigReference{Kind: notificationv1alpha1.KindClusterSlackConfig, Name: "sre"}},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny destinations combined with a channel or an escalation", func() {
+			obj.Spec.Notifications[0].Channel = "#team-a"
+			obj.Spec.Notifications[0].Escalation = &notificationv1alpha1.Escalation{After: metav1.Duration{Duration: time.Hour}}
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{{Channel: "#sre"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].channel: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].escalation: Forbidden")))
+		})
+
+		It("Should deny a history max age that is not positive", func() {
+			obj.Spec.History = &notificationv1alpha1.HistoryRetention{Limit: 10, MaxAge: &metav1.Duration{}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.history.maxAge: Invalid value")))
+		})
+
+		It("Should deny duplicate destinations", func() {
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{{Channel: "#sre"}, {Channel: "#sre"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[1]: Duplicate value")))
+		})
+
+		It("Should deny sinks combined with Slack settings", func() {
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{
+				{SinkRef: &corev1.LocalObjectReference{Name: "teams"}},
+				{SinkRef: &corev1.LocalObjectReference{Name: "discord"}, Channel: "#sre"},
+				{SinkRef: &corev1.LocalObjectReference{Name: "teams"}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[1].channel: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[2]: Duplicate value")))
+			Expect(err).NotTo(MatchError(ContainSubstring("destinations[0]")))
+		})
+
+		It("Should validate updates", func() {
+			obj.Spec.Notifications[0].Status = "Completed"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].status")))
+		})
+	})
+
+	Context("When validating SlackNotificationRule against channel policies", func() {
+		BeforeEach(func() {
+			obj.Spec.Notifications[0].Channel = "#general"
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "a"}}},
+				&notificationv1alpha1.SlackChannelPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: notificationv1alpha1.ChannelPolicy{
+						NamespaceSelector:         &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+						AllowedChannels:           []string{"#team-a-*"},
+						DisallowBroadcastMentions: true,
+					},
+				},
+			).Build()
+		})
+
+		It("Should deny channels the policy does not allow", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.notifications[0].channel"))
+			Expect(err.Error()).To(ContainSubstring("SlackChannelPolicy team-a"))
+		})
+
+		It("Should admit channels the policy allows", func() {
+			obj.Spec.Notifications[0].Channel = "#Team-A-alerts"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny broadcast mentions in titles", func() {
+			obj.Spec.Notifications[0].Channel = "#team-a-alerts"
+			obj.Spec.Notifications[0].Title = "<!here> {{ .metadata.name }} errored"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].title")))
+		})
+
+		It("Should check the channels of destinations", func() {
+			obj.Spec.Notifications[0].Channel = ""
+			obj.Spec.Notifications[0].Destinations = []notificationv1alpha1.NotificationDestination{{Channel: "#team-a-alerts"}, {Channel: "#general"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications[0].destinations[1]: Forbidden: channel #general")))
+			Expect(err).NotTo(MatchError(ContainSubstring("destinations[0]")))
+		})
+
+		It("Should not apply policies selecting other namespaces", func() {
+			obj.Namespace = "team-b"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+	})
+})